@@ -7,10 +7,12 @@ import (
 	"github.com/yoheimuta/protolint/linter/rule"
 	"github.com/yoheimuta/protolint/linter/strs"
 	"github.com/yoheimuta/protolint/linter/visitor"
+	"github.com/yoheimuta/protolint/plugin"
 )
 
 // EnumNamesLowerSnakeCaseRule verifies that all enum names are LowerSnakeCase.
 type EnumNamesLowerSnakeCaseRule struct {
+	allow []string
 }
 
 // NewEnumNamesLowerSnakeCaseRule creates a new EnumNamesLowerSnakeCaseRule.
@@ -38,20 +40,40 @@ func (r EnumNamesLowerSnakeCaseRule) Severity() rule.Severity {
 	return rule.SeverityWarning
 }
 
+// Configure receives the config in the plugins section of .protolint.yaml.
+// The names in allow are accepted as they are.
+func (r EnumNamesLowerSnakeCaseRule) Configure(config plugin.RuleConfig) (rule.Rule, error) {
+	var c struct {
+		Allow []string `json:"allow"`
+	}
+	if err := config.Decode(&c); err != nil {
+		return nil, err
+	}
+	r.allow = c.Allow
+	return r, nil
+}
+
 // Apply applies the rule to the proto.
 func (r EnumNamesLowerSnakeCaseRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
 	v := &enumNamesLowerSnakeCaseVisitor{
 		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		allow:          r.allow,
 	}
 	return visitor.RunVisitor(v, proto, r.ID())
 }
 
 type enumNamesLowerSnakeCaseVisitor struct {
 	*visitor.BaseAddVisitor
+	allow []string
 }
 
 // VisitEnum checks the enum field.
 func (v *enumNamesLowerSnakeCaseVisitor) VisitEnum(e *parser.Enum) bool {
+	for _, name := range v.allow {
+		if e.EnumName == name {
+			return false
+		}
+	}
 	if !strs.IsLowerSnakeCase(e.EnumName) {
 		v.AddFailuref(e.Meta.Pos, "Enum name %q must be underscore_separated_names", e.EnumName)
 	}
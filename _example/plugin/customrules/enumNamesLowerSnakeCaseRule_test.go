@@ -0,0 +1,25 @@
+package customrules_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/yoheimuta/protolint/_example/plugin/customrules"
+	"github.com/yoheimuta/protolint/linter/linttest"
+	"github.com/yoheimuta/protolint/plugin"
+)
+
+func TestEnumNamesLowerSnakeCaseRule(t *testing.T) {
+	config, err := plugin.NewRuleConfig(map[string]interface{}{
+		"allow": []interface{}{"LegacyName"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := customrules.NewEnumNamesLowerSnakeCaseRule().Configure(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linttest.Run(t, filepath.Join("testdata", "enumNamesLowerSnakeCase"), r)
+}
@@ -7,6 +7,7 @@ require (
 	github.com/golang/protobuf v1.5.4
 	github.com/hashicorp/go-hclog v1.6.3
 	github.com/hashicorp/go-plugin v1.7.0
+	github.com/tetratelabs/wazero v1.9.0
 	github.com/yoheimuta/go-protoparser/v4 v4.14.2
 	google.golang.org/grpc v1.79.1
 	google.golang.org/protobuf v1.36.11
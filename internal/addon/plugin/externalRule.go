@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
 
 	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
 
@@ -9,6 +11,8 @@ import (
 	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
 
 	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/fixer"
 	"github.com/yoheimuta/protolint/linter/report"
 	"github.com/yoheimuta/protolint/linter/rule"
 )
@@ -19,6 +23,11 @@ type externalRule struct {
 	purpose  string
 	client   shared.RuleSet
 	severity rule.Severity
+	// severityOverridden is true when the config sets the severity, which the failures can't change.
+	severityOverridden bool
+	// protocolVersion is the version of the protocol which both the host and the plugin speak.
+	protocolVersion int
+	fixMode         bool
 }
 
 func newExternalRule(
@@ -26,12 +35,18 @@ func newExternalRule(
 	purpose string,
 	client shared.RuleSet,
 	severity rule.Severity,
+	severityOverridden bool,
+	protocolVersion int,
+	fixMode bool,
 ) externalRule {
 	return externalRule{
-		id:       id,
-		purpose:  purpose,
-		client:   client,
-		severity: severity,
+		id:                 id,
+		purpose:            purpose,
+		client:             client,
+		severity:           severity,
+		severityOverridden: severityOverridden,
+		protocolVersion:    protocolVersion,
+		fixMode:            fixMode,
 	}
 }
 
@@ -57,6 +72,10 @@ func (r externalRule) Severity() rule.Severity {
 
 // Apply applies the rule to the proto.
 func (r externalRule) Apply(p *parser.Proto) ([]report.Failure, error) {
+	if 2 <= r.protocolVersion {
+		return r.applyV2(p)
+	}
+
 	relPath := p.Meta.Filename
 	absPath, err := filepath.Abs(relPath)
 	if err != nil {
@@ -82,3 +101,80 @@ func (r externalRule) Apply(p *parser.Proto) ([]report.Failure, error) {
 	}
 	return fs, nil
 }
+
+// applyV2 sends the content of the proto, which may be in memory or fixed by the previous rules,
+// and applies the returned edits in the fix mode.
+func (r externalRule) applyV2(p *parser.Proto) ([]report.Failure, error) {
+	displayPath := p.Meta.Filename
+	absPath, err := filepath.Abs(displayPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := osutil.ReadFile(displayPath)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.ApplyV2(&proto.ApplyV2Request{
+		Id:          r.id,
+		Path:        absPath,
+		DisplayPath: displayPath,
+		Content:     content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var fs []report.Failure
+	for _, f := range resp.Failures {
+		severity := r.severity
+		if !r.severityOverridden && f.Severity != proto.RuleSeverity_RULE_SEVERITY_UNSPECIFIED {
+			severity = getSeverity(f.Severity)
+		}
+		fs = append(fs, report.Failuref(meta.Position{
+			Filename: displayPath,
+			Offset:   int(f.GetPos().GetOffset()),
+			Line:     int(f.GetPos().GetLine()),
+			Column:   int(f.GetPos().GetColumn()),
+		}, r.id, string(severity), "%s", f.Message))
+	}
+
+	if r.fixMode && 0 < len(resp.Edits) {
+		if err := r.fix(displayPath, len(content), resp.Edits); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+// fix applies the edits to the file through the fixer.
+func (r externalRule) fix(
+	fileName string,
+	size int,
+	edits []*proto.ApplyV2Response_TextEdit,
+) error {
+	edits = append([]*proto.ApplyV2Response_TextEdit(nil), edits...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].StartOffset < edits[j].StartOffset
+	})
+
+	f, err := fixer.NewBaseFixing(fileName)
+	if err != nil {
+		return err
+	}
+	end := 0
+	for _, e := range edits {
+		start, next := int(e.StartOffset), int(e.EndOffset)
+		if start < end || next < start || size < next {
+			return fmt.Errorf("plugin rule %s returned an invalid edit from %d to %d", r.id, start, next)
+		}
+		// fixer.TextEdit.End is inclusive.
+		f.Replace(fixer.TextEdit{
+			Pos:     start,
+			End:     next - 1,
+			NewText: []byte(e.NewText),
+		})
+		end = next
+	}
+	return f.Finally()
+}
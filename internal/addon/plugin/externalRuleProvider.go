@@ -1,32 +1,63 @@
 package plugin
 
 import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
 	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
 	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/linter/config"
 
 	"github.com/yoheimuta/protolint/linter/rule"
 )
 
 // GetExternalRules provides the external rules.
+// The configs in pluginRules are sent to the plugins, and the severities in them override the ones of the plugins.
 func GetExternalRules(
 	clients []shared.RuleSet,
+	pluginRules config.PluginRules,
 	fixMode bool,
 	verbose bool,
 ) ([]rule.Rule, error) {
-	var rs []rule.Rule
+	if len(clients) == 0 {
+		return nil, nil
+	}
+
+	configs, err := pluginRules.Configs()
+	if err != nil {
+		return nil, err
+	}
+	ruleConfigs := make(map[string]*structpb.Value)
+	for id, c := range configs {
+		v, err := structpb.NewValue(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config of the plugin rule %q: %w", id, err)
+		}
+		ruleConfigs[id] = v
+	}
 
+	var rs []rule.Rule
 	for _, client := range clients {
 		resp, err := client.ListRules(&proto.ListRulesRequest{
-			Verbose: verbose,
-			FixMode: fixMode,
+			Verbose:         verbose,
+			FixMode:         fixMode,
+			ProtocolVersion: shared.ProtocolVersion,
+			RuleConfigs:     ruleConfigs,
 		})
 		if err != nil {
 			return nil, err
 		}
 
+		// The old plugins leave the version unset.
+		protocolVersion := min(max(int(resp.ProtocolVersion), 1), shared.ProtocolVersion)
 		for _, r := range resp.Rules {
 			severity := getSeverity(r.Severity)
-			rs = append(rs, newExternalRule(r.Id, r.Purpose, client, severity))
+			overridden := false
+			if s := pluginRules[r.Id].Severity; s != "" {
+				severity, overridden = s, true
+			}
+			rs = append(rs, newExternalRule(r.Id, r.Purpose, client, severity, overridden, protocolVersion, fixMode))
 		}
 	}
 	return rs, nil
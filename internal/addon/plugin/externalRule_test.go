@@ -0,0 +1,212 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin"
+	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// fakeRuleSet is a plugin speaking the protocol version, which reports a failure on the first byte
+// and replaces "foo" at the offset 10 with "bar".
+type fakeRuleSet struct {
+	protocolVersion int32
+	gotList         *proto.ListRulesRequest
+	gotV1           *proto.ApplyRequest
+	gotV2           *proto.ApplyV2Request
+}
+
+func (s *fakeRuleSet) ListRules(req *proto.ListRulesRequest) (*proto.ListRulesResponse, error) {
+	s.gotList = req
+	return &proto.ListRulesResponse{
+		Rules: []*proto.ListRulesResponse_Rule{
+			{
+				Id:       "FAKE",
+				Severity: proto.RuleSeverity_RULE_SEVERITY_ERROR,
+			},
+		},
+		ProtocolVersion: s.protocolVersion,
+	}, nil
+}
+
+func (s *fakeRuleSet) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
+	s.gotV1 = req
+	return &proto.ApplyResponse{
+		Failures: []*proto.ApplyResponse_Failure{
+			{
+				Message: "v1",
+				Pos:     &proto.ApplyResponse_Position{Offset: 0, Line: 1, Column: 1},
+			},
+		},
+	}, nil
+}
+
+func (s *fakeRuleSet) ApplyV2(req *proto.ApplyV2Request) (*proto.ApplyV2Response, error) {
+	s.gotV2 = req
+	return &proto.ApplyV2Response{
+		Failures: []*proto.ApplyV2Response_Failure{
+			{
+				Message:  "v2",
+				Pos:      &proto.ApplyResponse_Position{Offset: 0, Line: 1, Column: 1},
+				Severity: proto.RuleSeverity_RULE_SEVERITY_WARNING,
+			},
+		},
+		Edits: []*proto.ApplyV2Response_TextEdit{
+			{
+				StartOffset: 10,
+				EndOffset:   13,
+				NewText:     "bar",
+			},
+		},
+	}, nil
+}
+
+func TestGetExternalRules_Apply(t *testing.T) {
+	const content = "package a.foo;\n"
+
+	tests := []struct {
+		name                 string
+		inputProtocolVersion int32
+		inputFixMode         bool
+		inputPluginRules     config.PluginRules
+		wantSeverity         rule.Severity
+		wantMessage          string
+		wantContent          string
+		wantV2               bool
+		wantRuleConfigs      map[string]interface{}
+	}{
+		{
+			name:         "the old plugin is called with the path",
+			wantSeverity: rule.SeverityError,
+			wantMessage:  "v1",
+			wantContent:  content,
+		},
+		{
+			name:                 "the plugin speaking v2 is called with the content and reports the severity",
+			inputProtocolVersion: 2,
+			wantSeverity:         rule.SeverityWarning,
+			wantMessage:          "v2",
+			wantContent:          content,
+			wantV2:               true,
+		},
+		{
+			name:                 "the edits are applied in the fix mode",
+			inputProtocolVersion: 2,
+			inputFixMode:         true,
+			wantSeverity:         rule.SeverityWarning,
+			wantMessage:          "v2",
+			wantContent:          "package a.bar;\n",
+			wantV2:               true,
+		},
+		{
+			name:                 "the newer plugin is called with the version the host speaks",
+			inputProtocolVersion: 3,
+			wantSeverity:         rule.SeverityWarning,
+			wantMessage:          "v2",
+			wantContent:          content,
+			wantV2:               true,
+		},
+		{
+			name:                 "the config is sent and its severity overrides the one of the failure",
+			inputProtocolVersion: 2,
+			inputPluginRules: config.PluginRules{
+				"FAKE": {
+					CustomizableSeverityOption: config.CustomizableSeverityOption{
+						Severity: rule.SeverityNote,
+					},
+					Config: map[interface{}]interface{}{
+						"max": 3,
+						"names": []interface{}{
+							"foo",
+						},
+					},
+				},
+				"OTHER": {},
+			},
+			wantSeverity: rule.SeverityNote,
+			wantMessage:  "v2",
+			wantContent:  content,
+			wantV2:       true,
+			wantRuleConfigs: map[string]interface{}{
+				"FAKE": map[string]interface{}{
+					"max": float64(3),
+					"names": []interface{}{
+						"foo",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "a.proto")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			p, err := file.NewProtoFile(path, path).Parse(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := &fakeRuleSet{protocolVersion: test.inputProtocolVersion}
+			rules, err := plugin.GetExternalRules([]shared.RuleSet{client}, test.inputPluginRules, test.inputFixMode, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotRuleConfigs := make(map[string]interface{})
+			for id, c := range client.gotList.RuleConfigs {
+				gotRuleConfigs[id] = c.AsInterface()
+			}
+			if len(test.wantRuleConfigs) == 0 {
+				test.wantRuleConfigs = map[string]interface{}{}
+			}
+			if !reflect.DeepEqual(gotRuleConfigs, test.wantRuleConfigs) {
+				t.Errorf("got the configs %v, but want %v", gotRuleConfigs, test.wantRuleConfigs)
+			}
+
+			got, err := rules[0].Apply(p)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			want := []report.Failure{
+				report.Failuref(
+					meta.Position{Filename: path, Offset: 0, Line: 1, Column: 1},
+					"FAKE",
+					string(test.wantSeverity),
+					"%s",
+					test.wantMessage,
+				),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, but want %v", got, want)
+			}
+			if test.wantV2 != (client.gotV2 != nil) {
+				t.Errorf("got the v2 request %v, but want %t", client.gotV2, test.wantV2)
+			}
+			if client.gotV2 != nil && string(client.gotV2.Content) != content {
+				t.Errorf("got the content %q, but want %q", client.gotV2.Content, content)
+			}
+
+			gotContent, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(gotContent) != test.wantContent {
+				t.Errorf("got %q, but want %q", gotContent, test.wantContent)
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.30.0
+// 	protoc-gen-go v1.36.11
 // 	protoc        v3.12.4
 // source: plugin.proto
 
@@ -9,8 +9,10 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -73,21 +75,22 @@ func (RuleSeverity) EnumDescriptor() ([]byte, []int) {
 }
 
 type ListRulesRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Verbose bool                   `protobuf:"varint,1,opt,name=verbose,proto3" json:"verbose,omitempty"`
+	FixMode bool                   `protobuf:"varint,2,opt,name=fix_mode,json=fixMode,proto3" json:"fix_mode,omitempty"`
+	// protocol_version is the highest version the host speaks. 0 means 1.
+	ProtocolVersion int32 `protobuf:"varint,3,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// rule_configs are the values in the plugins section of the config, keyed by the rule IDs.
+	RuleConfigs   map[string]*structpb.Value `protobuf:"bytes,4,rep,name=rule_configs,json=ruleConfigs,proto3" json:"rule_configs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
-
-	Verbose bool `protobuf:"varint,1,opt,name=verbose,proto3" json:"verbose,omitempty"`
-	FixMode bool `protobuf:"varint,2,opt,name=fix_mode,json=fixMode,proto3" json:"fix_mode,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListRulesRequest) Reset() {
 	*x = ListRulesRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ListRulesRequest) String() string {
@@ -98,7 +101,7 @@ func (*ListRulesRequest) ProtoMessage() {}
 
 func (x *ListRulesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -127,21 +130,34 @@ func (x *ListRulesRequest) GetFixMode() bool {
 	return false
 }
 
-type ListRulesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ListRulesRequest) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
 
+func (x *ListRulesRequest) GetRuleConfigs() map[string]*structpb.Value {
+	if x != nil {
+		return x.RuleConfigs
+	}
+	return nil
+}
+
+type ListRulesResponse struct {
+	state protoimpl.MessageState    `protogen:"open.v1"`
 	Rules []*ListRulesResponse_Rule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	// protocol_version is the version the plugin speaks. 0 means 1.
+	ProtocolVersion int32 `protobuf:"varint,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ListRulesResponse) Reset() {
 	*x = ListRulesResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ListRulesResponse) String() string {
@@ -152,7 +168,7 @@ func (*ListRulesResponse) ProtoMessage() {}
 
 func (x *ListRulesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -174,22 +190,26 @@ func (x *ListRulesResponse) GetRules() []*ListRulesResponse_Rule {
 	return nil
 }
 
+func (x *ListRulesResponse) GetProtocolVersion() int32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
 type ApplyRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ApplyRequest) Reset() {
 	*x = ApplyRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ApplyRequest) String() string {
@@ -200,7 +220,7 @@ func (*ApplyRequest) ProtoMessage() {}
 
 func (x *ApplyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -230,20 +250,17 @@ func (x *ApplyRequest) GetPath() string {
 }
 
 type ApplyResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Failures      []*ApplyResponse_Failure `protobuf:"bytes,1,rep,name=failures,proto3" json:"failures,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Failures []*ApplyResponse_Failure `protobuf:"bytes,1,rep,name=failures,proto3" json:"failures,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ApplyResponse) Reset() {
 	*x = ApplyResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ApplyResponse) String() string {
@@ -254,7 +271,7 @@ func (*ApplyResponse) ProtoMessage() {}
 
 func (x *ApplyResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -276,23 +293,209 @@ func (x *ApplyResponse) GetFailures() []*ApplyResponse_Failure {
 	return nil
 }
 
-type ListRulesResponse_Rule struct {
-	state         protoimpl.MessageState
+type ApplyV2Request struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// path is the absolute path to the file.
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// display_path is the path shown in the failures.
+	DisplayPath string `protobuf:"bytes,3,opt,name=display_path,json=displayPath,proto3" json:"display_path,omitempty"`
+	// content is the content to lint, which may differ from the file on the disk.
+	Content       []byte `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyV2Request) Reset() {
+	*x = ApplyV2Request{}
+	mi := &file_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyV2Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyV2Request) ProtoMessage() {}
+
+func (x *ApplyV2Request) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyV2Request.ProtoReflect.Descriptor instead.
+func (*ApplyV2Request) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ApplyV2Request) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApplyV2Request) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ApplyV2Request) GetDisplayPath() string {
+	if x != nil {
+		return x.DisplayPath
+	}
+	return ""
+}
+
+func (x *ApplyV2Request) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ApplyV2Response struct {
+	state    protoimpl.MessageState     `protogen:"open.v1"`
+	Failures []*ApplyV2Response_Failure `protobuf:"bytes,1,rep,name=failures,proto3" json:"failures,omitempty"`
+	// edits are the fixes, which the host applies in the fix mode. They must not overlap.
+	Edits         []*ApplyV2Response_TextEdit `protobuf:"bytes,2,rep,name=edits,proto3" json:"edits,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	Id       string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Purpose  string       `protobuf:"bytes,2,opt,name=purpose,proto3" json:"purpose,omitempty"`
-	Severity RuleSeverity `protobuf:"varint,3,opt,name=severity,proto3,enum=proto.RuleSeverity" json:"severity,omitempty"`
+func (x *ApplyV2Response) Reset() {
+	*x = ApplyV2Response{}
+	mi := &file_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRulesResponse_Rule) Reset() {
-	*x = ListRulesResponse_Rule{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[4]
+func (x *ApplyV2Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyV2Response) ProtoMessage() {}
+
+func (x *ApplyV2Response) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyV2Response.ProtoReflect.Descriptor instead.
+func (*ApplyV2Response) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ApplyV2Response) GetFailures() []*ApplyV2Response_Failure {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+func (x *ApplyV2Response) GetEdits() []*ApplyV2Response_TextEdit {
+	if x != nil {
+		return x.Edits
+	}
+	return nil
+}
+
+// WasmCall is the input of the plugin compiled to a WASI module, which is run once for each call.
+// The module reads it from the stdin and writes the response of the call to the stdout.
+type WasmCall struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// list_rules is the request of the ListRules call, which the rules are initialized with.
+	ListRules *ListRulesRequest `protobuf:"bytes,1,opt,name=list_rules,json=listRules,proto3" json:"list_rules,omitempty"`
+	// apply is set when the call is Apply.
+	Apply *ApplyRequest `protobuf:"bytes,2,opt,name=apply,proto3" json:"apply,omitempty"`
+	// apply_v2 is set when the call is ApplyV2. Neither of them is set when the call is ListRules.
+	ApplyV2       *ApplyV2Request `protobuf:"bytes,3,opt,name=apply_v2,json=applyV2,proto3" json:"apply_v2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WasmCall) Reset() {
+	*x = WasmCall{}
+	mi := &file_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WasmCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WasmCall) ProtoMessage() {}
+
+func (x *WasmCall) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[6]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WasmCall.ProtoReflect.Descriptor instead.
+func (*WasmCall) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WasmCall) GetListRules() *ListRulesRequest {
+	if x != nil {
+		return x.ListRules
 	}
+	return nil
+}
+
+func (x *WasmCall) GetApply() *ApplyRequest {
+	if x != nil {
+		return x.Apply
+	}
+	return nil
+}
+
+func (x *WasmCall) GetApplyV2() *ApplyV2Request {
+	if x != nil {
+		return x.ApplyV2
+	}
+	return nil
+}
+
+type ListRulesResponse_Rule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Purpose       string                 `protobuf:"bytes,2,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	Severity      RuleSeverity           `protobuf:"varint,3,opt,name=severity,proto3,enum=proto.RuleSeverity" json:"severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRulesResponse_Rule) Reset() {
+	*x = ListRulesResponse_Rule{}
+	mi := &file_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ListRulesResponse_Rule) String() string {
@@ -302,8 +505,8 @@ func (x *ListRulesResponse_Rule) String() string {
 func (*ListRulesResponse_Rule) ProtoMessage() {}
 
 func (x *ListRulesResponse_Rule) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_plugin_proto_msgTypes[8]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -340,22 +543,19 @@ func (x *ListRulesResponse_Rule) GetSeverity() RuleSeverity {
 }
 
 type ApplyResponse_Position struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        int32                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Line          int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	Column        int32                  `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Offset int32 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
-	Line   int32 `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
-	Column int32 `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ApplyResponse_Position) Reset() {
 	*x = ApplyResponse_Position{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ApplyResponse_Position) String() string {
@@ -365,8 +565,8 @@ func (x *ApplyResponse_Position) String() string {
 func (*ApplyResponse_Position) ProtoMessage() {}
 
 func (x *ApplyResponse_Position) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_plugin_proto_msgTypes[9]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -403,21 +603,18 @@ func (x *ApplyResponse_Position) GetColumn() int32 {
 }
 
 type ApplyResponse_Failure struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Message       string                  `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Pos           *ApplyResponse_Position `protobuf:"bytes,2,opt,name=pos,proto3" json:"pos,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Message string                  `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	Pos     *ApplyResponse_Position `protobuf:"bytes,2,opt,name=pos,proto3" json:"pos,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ApplyResponse_Failure) Reset() {
 	*x = ApplyResponse_Failure{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_plugin_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_plugin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ApplyResponse_Failure) String() string {
@@ -427,8 +624,8 @@ func (x *ApplyResponse_Failure) String() string {
 func (*ApplyResponse_Failure) ProtoMessage() {}
 
 func (x *ApplyResponse_Failure) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_plugin_proto_msgTypes[10]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -457,105 +654,248 @@ func (x *ApplyResponse_Failure) GetPos() *ApplyResponse_Position {
 	return nil
 }
 
-var File_plugin_proto protoreflect.FileDescriptor
+type ApplyV2Response_Failure struct {
+	state   protoimpl.MessageState  `protogen:"open.v1"`
+	Message string                  `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Pos     *ApplyResponse_Position `protobuf:"bytes,2,opt,name=pos,proto3" json:"pos,omitempty"`
+	// severity overrides the one of the rule unless it's unspecified.
+	Severity      RuleSeverity `protobuf:"varint,3,opt,name=severity,proto3,enum=proto.RuleSeverity" json:"severity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyV2Response_Failure) Reset() {
+	*x = ApplyV2Response_Failure{}
+	mi := &file_plugin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyV2Response_Failure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyV2Response_Failure) ProtoMessage() {}
 
-var file_plugin_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x47, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6c,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
-	0x62, 0x6f, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x76, 0x65, 0x72, 0x62,
-	0x6f, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x69, 0x78, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x66, 0x69, 0x78, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0xab,
-	0x01, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x75,
-	0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x1a, 0x61, 0x0a, 0x04, 0x52, 0x75, 0x6c,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x73,
-	0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69,
-	0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x22, 0x32, 0x0a, 0x0c,
-	0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
-	0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
-	0x22, 0xef, 0x01, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70,
-	0x6c, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75,
-	0x72, 0x65, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x1a, 0x4e, 0x0a, 0x08,
-	0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
-	0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
-	0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x1a, 0x54, 0x0a, 0x07,
-	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x2f, 0x0a, 0x03, 0x70, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x70,
-	0x6f, 0x73, 0x2a, 0x79, 0x0a, 0x0c, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69,
-	0x74, 0x79, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x53, 0x45, 0x56, 0x45, 0x52,
-	0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
-	0x00, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49,
-	0x54, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x45, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x55, 0x4c,
-	0x45, 0x5f, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41, 0x52, 0x4e, 0x49,
-	0x4e, 0x47, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x55, 0x4c, 0x45, 0x5f, 0x53, 0x45, 0x56,
-	0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x32, 0x84, 0x01,
-	0x0a, 0x0e, 0x52, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x12, 0x3e, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x17, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x32, 0x0a, 0x05, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3c, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x79, 0x6f, 0x68, 0x65, 0x69, 0x6d, 0x75, 0x74, 0x61, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
-	0x61, 0x64, 0x64, 0x6f, 0x6e, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ApplyV2Response_Failure) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
+// Deprecated: Use ApplyV2Response_Failure.ProtoReflect.Descriptor instead.
+func (*ApplyV2Response_Failure) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *ApplyV2Response_Failure) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ApplyV2Response_Failure) GetPos() *ApplyResponse_Position {
+	if x != nil {
+		return x.Pos
+	}
+	return nil
+}
+
+func (x *ApplyV2Response_Failure) GetSeverity() RuleSeverity {
+	if x != nil {
+		return x.Severity
+	}
+	return RuleSeverity_RULE_SEVERITY_UNSPECIFIED
+}
+
+// TextEdit replaces the bytes of the content from start_offset up to, but not including, end_offset.
+type ApplyV2Response_TextEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartOffset   int32                  `protobuf:"varint,1,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"`
+	EndOffset     int32                  `protobuf:"varint,2,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
+	NewText       string                 `protobuf:"bytes,3,opt,name=new_text,json=newText,proto3" json:"new_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyV2Response_TextEdit) Reset() {
+	*x = ApplyV2Response_TextEdit{}
+	mi := &file_plugin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyV2Response_TextEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyV2Response_TextEdit) ProtoMessage() {}
+
+func (x *ApplyV2Response_TextEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyV2Response_TextEdit.ProtoReflect.Descriptor instead.
+func (*ApplyV2Response_TextEdit) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{5, 1}
+}
+
+func (x *ApplyV2Response_TextEdit) GetStartOffset() int32 {
+	if x != nil {
+		return x.StartOffset
+	}
+	return 0
+}
+
+func (x *ApplyV2Response_TextEdit) GetEndOffset() int32 {
+	if x != nil {
+		return x.EndOffset
+	}
+	return 0
+}
+
+func (x *ApplyV2Response_TextEdit) GetNewText() string {
+	if x != nil {
+		return x.NewText
+	}
+	return ""
+}
+
+var File_plugin_proto protoreflect.FileDescriptor
+
+const file_plugin_proto_rawDesc = "" +
+	"\n" +
+	"\fplugin.proto\x12\x05proto\x1a\x1cgoogle/protobuf/struct.proto\"\x97\x02\n" +
+	"\x10ListRulesRequest\x12\x18\n" +
+	"\averbose\x18\x01 \x01(\bR\averbose\x12\x19\n" +
+	"\bfix_mode\x18\x02 \x01(\bR\afixMode\x12)\n" +
+	"\x10protocol_version\x18\x03 \x01(\x05R\x0fprotocolVersion\x12K\n" +
+	"\frule_configs\x18\x04 \x03(\v2(.proto.ListRulesRequest.RuleConfigsEntryR\vruleConfigs\x1aV\n" +
+	"\x10RuleConfigsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\"\xd6\x01\n" +
+	"\x11ListRulesResponse\x123\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1d.proto.ListRulesResponse.RuleR\x05rules\x12)\n" +
+	"\x10protocol_version\x18\x02 \x01(\x05R\x0fprotocolVersion\x1aa\n" +
+	"\x04Rule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\apurpose\x18\x02 \x01(\tR\apurpose\x12/\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\x13.proto.RuleSeverityR\bseverity\"2\n" +
+	"\fApplyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"\xef\x01\n" +
+	"\rApplyResponse\x128\n" +
+	"\bfailures\x18\x01 \x03(\v2\x1c.proto.ApplyResponse.FailureR\bfailures\x1aN\n" +
+	"\bPosition\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x05R\x06offset\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\x05R\x06column\x1aT\n" +
+	"\aFailure\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12/\n" +
+	"\x03pos\x18\x02 \x01(\v2\x1d.proto.ApplyResponse.PositionR\x03pos\"q\n" +
+	"\x0eApplyV2Request\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12!\n" +
+	"\fdisplay_path\x18\x03 \x01(\tR\vdisplayPath\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\fR\acontent\"\xf5\x02\n" +
+	"\x0fApplyV2Response\x12:\n" +
+	"\bfailures\x18\x01 \x03(\v2\x1e.proto.ApplyV2Response.FailureR\bfailures\x125\n" +
+	"\x05edits\x18\x02 \x03(\v2\x1f.proto.ApplyV2Response.TextEditR\x05edits\x1a\x85\x01\n" +
+	"\aFailure\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12/\n" +
+	"\x03pos\x18\x02 \x01(\v2\x1d.proto.ApplyResponse.PositionR\x03pos\x12/\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\x13.proto.RuleSeverityR\bseverity\x1ag\n" +
+	"\bTextEdit\x12!\n" +
+	"\fstart_offset\x18\x01 \x01(\x05R\vstartOffset\x12\x1d\n" +
+	"\n" +
+	"end_offset\x18\x02 \x01(\x05R\tendOffset\x12\x19\n" +
+	"\bnew_text\x18\x03 \x01(\tR\anewText\"\x9f\x01\n" +
+	"\bWasmCall\x126\n" +
+	"\n" +
+	"list_rules\x18\x01 \x01(\v2\x17.proto.ListRulesRequestR\tlistRules\x12)\n" +
+	"\x05apply\x18\x02 \x01(\v2\x13.proto.ApplyRequestR\x05apply\x120\n" +
+	"\bapply_v2\x18\x03 \x01(\v2\x15.proto.ApplyV2RequestR\aapplyV2*y\n" +
+	"\fRuleSeverity\x12\x1d\n" +
+	"\x19RULE_SEVERITY_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RULE_SEVERITY_NOTE\x10\x01\x12\x19\n" +
+	"\x15RULE_SEVERITY_WARNING\x10\x02\x12\x17\n" +
+	"\x13RULE_SEVERITY_ERROR\x10\x032\xbe\x01\n" +
+	"\x0eRuleSetService\x12>\n" +
+	"\tListRules\x12\x17.proto.ListRulesRequest\x1a\x18.proto.ListRulesResponse\x122\n" +
+	"\x05Apply\x12\x13.proto.ApplyRequest\x1a\x14.proto.ApplyResponse\x128\n" +
+	"\aApplyV2\x12\x15.proto.ApplyV2Request\x1a\x16.proto.ApplyV2ResponseB<Z:github.com/yoheimuta/protolint/internal/addon/plugin/protob\x06proto3"
+
 var (
 	file_plugin_proto_rawDescOnce sync.Once
-	file_plugin_proto_rawDescData = file_plugin_proto_rawDesc
+	file_plugin_proto_rawDescData []byte
 )
 
 func file_plugin_proto_rawDescGZIP() []byte {
 	file_plugin_proto_rawDescOnce.Do(func() {
-		file_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(file_plugin_proto_rawDescData)
+		file_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_proto_rawDesc), len(file_plugin_proto_rawDesc)))
 	})
 	return file_plugin_proto_rawDescData
 }
 
 var file_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_plugin_proto_goTypes = []interface{}{
-	(RuleSeverity)(0),              // 0: proto.RuleSeverity
-	(*ListRulesRequest)(nil),       // 1: proto.ListRulesRequest
-	(*ListRulesResponse)(nil),      // 2: proto.ListRulesResponse
-	(*ApplyRequest)(nil),           // 3: proto.ApplyRequest
-	(*ApplyResponse)(nil),          // 4: proto.ApplyResponse
-	(*ListRulesResponse_Rule)(nil), // 5: proto.ListRulesResponse.Rule
-	(*ApplyResponse_Position)(nil), // 6: proto.ApplyResponse.Position
-	(*ApplyResponse_Failure)(nil),  // 7: proto.ApplyResponse.Failure
+var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_plugin_proto_goTypes = []any{
+	(RuleSeverity)(0),                // 0: proto.RuleSeverity
+	(*ListRulesRequest)(nil),         // 1: proto.ListRulesRequest
+	(*ListRulesResponse)(nil),        // 2: proto.ListRulesResponse
+	(*ApplyRequest)(nil),             // 3: proto.ApplyRequest
+	(*ApplyResponse)(nil),            // 4: proto.ApplyResponse
+	(*ApplyV2Request)(nil),           // 5: proto.ApplyV2Request
+	(*ApplyV2Response)(nil),          // 6: proto.ApplyV2Response
+	(*WasmCall)(nil),                 // 7: proto.WasmCall
+	nil,                              // 8: proto.ListRulesRequest.RuleConfigsEntry
+	(*ListRulesResponse_Rule)(nil),   // 9: proto.ListRulesResponse.Rule
+	(*ApplyResponse_Position)(nil),   // 10: proto.ApplyResponse.Position
+	(*ApplyResponse_Failure)(nil),    // 11: proto.ApplyResponse.Failure
+	(*ApplyV2Response_Failure)(nil),  // 12: proto.ApplyV2Response.Failure
+	(*ApplyV2Response_TextEdit)(nil), // 13: proto.ApplyV2Response.TextEdit
+	(*structpb.Value)(nil),           // 14: google.protobuf.Value
 }
 var file_plugin_proto_depIdxs = []int32{
-	5, // 0: proto.ListRulesResponse.rules:type_name -> proto.ListRulesResponse.Rule
-	7, // 1: proto.ApplyResponse.failures:type_name -> proto.ApplyResponse.Failure
-	0, // 2: proto.ListRulesResponse.Rule.severity:type_name -> proto.RuleSeverity
-	6, // 3: proto.ApplyResponse.Failure.pos:type_name -> proto.ApplyResponse.Position
-	1, // 4: proto.RuleSetService.ListRules:input_type -> proto.ListRulesRequest
-	3, // 5: proto.RuleSetService.Apply:input_type -> proto.ApplyRequest
-	2, // 6: proto.RuleSetService.ListRules:output_type -> proto.ListRulesResponse
-	4, // 7: proto.RuleSetService.Apply:output_type -> proto.ApplyResponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	8,  // 0: proto.ListRulesRequest.rule_configs:type_name -> proto.ListRulesRequest.RuleConfigsEntry
+	9,  // 1: proto.ListRulesResponse.rules:type_name -> proto.ListRulesResponse.Rule
+	11, // 2: proto.ApplyResponse.failures:type_name -> proto.ApplyResponse.Failure
+	12, // 3: proto.ApplyV2Response.failures:type_name -> proto.ApplyV2Response.Failure
+	13, // 4: proto.ApplyV2Response.edits:type_name -> proto.ApplyV2Response.TextEdit
+	1,  // 5: proto.WasmCall.list_rules:type_name -> proto.ListRulesRequest
+	3,  // 6: proto.WasmCall.apply:type_name -> proto.ApplyRequest
+	5,  // 7: proto.WasmCall.apply_v2:type_name -> proto.ApplyV2Request
+	14, // 8: proto.ListRulesRequest.RuleConfigsEntry.value:type_name -> google.protobuf.Value
+	0,  // 9: proto.ListRulesResponse.Rule.severity:type_name -> proto.RuleSeverity
+	10, // 10: proto.ApplyResponse.Failure.pos:type_name -> proto.ApplyResponse.Position
+	10, // 11: proto.ApplyV2Response.Failure.pos:type_name -> proto.ApplyResponse.Position
+	0,  // 12: proto.ApplyV2Response.Failure.severity:type_name -> proto.RuleSeverity
+	1,  // 13: proto.RuleSetService.ListRules:input_type -> proto.ListRulesRequest
+	3,  // 14: proto.RuleSetService.Apply:input_type -> proto.ApplyRequest
+	5,  // 15: proto.RuleSetService.ApplyV2:input_type -> proto.ApplyV2Request
+	2,  // 16: proto.RuleSetService.ListRules:output_type -> proto.ListRulesResponse
+	4,  // 17: proto.RuleSetService.Apply:output_type -> proto.ApplyResponse
+	6,  // 18: proto.RuleSetService.ApplyV2:output_type -> proto.ApplyV2Response
+	16, // [16:19] is the sub-list for method output_type
+	13, // [13:16] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_plugin_proto_init() }
@@ -563,99 +903,13 @@ func file_plugin_proto_init() {
 	if File_plugin_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_plugin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRulesRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRulesResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ApplyRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ApplyResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRulesResponse_Rule); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ApplyResponse_Position); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_plugin_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ApplyResponse_Failure); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_plugin_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_proto_rawDesc), len(file_plugin_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -665,7 +919,6 @@ func file_plugin_proto_init() {
 		MessageInfos:      file_plugin_proto_msgTypes,
 	}.Build()
 	File_plugin_proto = out.File
-	file_plugin_proto_rawDesc = nil
 	file_plugin_proto_goTypes = nil
 	file_plugin_proto_depIdxs = nil
 }
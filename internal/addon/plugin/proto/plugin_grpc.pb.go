@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion7
 const (
 	RuleSetService_ListRules_FullMethodName = "/proto.RuleSetService/ListRules"
 	RuleSetService_Apply_FullMethodName     = "/proto.RuleSetService/Apply"
+	RuleSetService_ApplyV2_FullMethodName   = "/proto.RuleSetService/ApplyV2"
 )
 
 // RuleSetServiceClient is the client API for RuleSetService service.
@@ -29,6 +30,8 @@ const (
 type RuleSetServiceClient interface {
 	ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesResponse, error)
 	Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+	// ApplyV2 is called instead of Apply when both the host and the plugin speak the protocol version 2.
+	ApplyV2(ctx context.Context, in *ApplyV2Request, opts ...grpc.CallOption) (*ApplyV2Response, error)
 }
 
 type ruleSetServiceClient struct {
@@ -57,12 +60,23 @@ func (c *ruleSetServiceClient) Apply(ctx context.Context, in *ApplyRequest, opts
 	return out, nil
 }
 
+func (c *ruleSetServiceClient) ApplyV2(ctx context.Context, in *ApplyV2Request, opts ...grpc.CallOption) (*ApplyV2Response, error) {
+	out := new(ApplyV2Response)
+	err := c.cc.Invoke(ctx, RuleSetService_ApplyV2_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RuleSetServiceServer is the server API for RuleSetService service.
 // All implementations must embed UnimplementedRuleSetServiceServer
 // for forward compatibility
 type RuleSetServiceServer interface {
 	ListRules(context.Context, *ListRulesRequest) (*ListRulesResponse, error)
 	Apply(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	// ApplyV2 is called instead of Apply when both the host and the plugin speak the protocol version 2.
+	ApplyV2(context.Context, *ApplyV2Request) (*ApplyV2Response, error)
 	mustEmbedUnimplementedRuleSetServiceServer()
 }
 
@@ -76,6 +90,9 @@ func (UnimplementedRuleSetServiceServer) ListRules(context.Context, *ListRulesRe
 func (UnimplementedRuleSetServiceServer) Apply(context.Context, *ApplyRequest) (*ApplyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
 }
+func (UnimplementedRuleSetServiceServer) ApplyV2(context.Context, *ApplyV2Request) (*ApplyV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyV2 not implemented")
+}
 func (UnimplementedRuleSetServiceServer) mustEmbedUnimplementedRuleSetServiceServer() {}
 
 // UnsafeRuleSetServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +142,24 @@ func _RuleSetService_Apply_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RuleSetService_ApplyV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyV2Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuleSetServiceServer).ApplyV2(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RuleSetService_ApplyV2_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuleSetServiceServer).ApplyV2(ctx, req.(*ApplyV2Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RuleSetService_ServiceDesc is the grpc.ServiceDesc for RuleSetService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,6 +175,10 @@ var RuleSetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Apply",
 			Handler:    _RuleSetService_Apply_Handler,
 		},
+		{
+			MethodName: "ApplyV2",
+			Handler:    _RuleSetService_ApplyV2_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin.proto",
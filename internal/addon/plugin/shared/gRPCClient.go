@@ -20,3 +20,8 @@ func (c *GRPCClient) ListRules(req *proto.ListRulesRequest) (*proto.ListRulesRes
 func (c *GRPCClient) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
 	return c.client.Apply(context.Background(), req)
 }
+
+// ApplyV2 applies the rule to the content of the proto.
+func (c *GRPCClient) ApplyV2(req *proto.ApplyV2Request) (*proto.ApplyV2Response, error) {
+	return c.client.ApplyV2(context.Background(), req)
+}
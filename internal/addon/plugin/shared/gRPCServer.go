@@ -21,3 +21,8 @@ func (s *GRPCServer) ListRules(_ context.Context, req *proto.ListRulesRequest) (
 func (s *GRPCServer) Apply(_ context.Context, req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
 	return s.server.Apply(req)
 }
+
+// ApplyV2 applies the rule to the content of the proto.
+func (s *GRPCServer) ApplyV2(_ context.Context, req *proto.ApplyV2Request) (*proto.ApplyV2Response, error) {
+	return s.server.ApplyV2(req)
+}
@@ -2,8 +2,14 @@ package shared
 
 import "github.com/yoheimuta/protolint/internal/addon/plugin/proto"
 
+// ProtocolVersion is the highest version of the RuleSetService protocol which this package speaks.
+// The host and the plugin agree on it through ListRules, so that the plugins built with the older
+// version keep working without the change of the handshake.
+const ProtocolVersion = 2
+
 // RuleSet is the interface that we're exposing as a plugin.
 type RuleSet interface {
 	ListRules(*proto.ListRulesRequest) (*proto.ListRulesResponse, error)
 	Apply(*proto.ApplyRequest) (*proto.ApplyResponse, error)
+	ApplyV2(*proto.ApplyV2Request) (*proto.ApplyV2Response, error)
 }
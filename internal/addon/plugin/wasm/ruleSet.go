@@ -0,0 +1,204 @@
+// Package wasm runs the plugins compiled to WASI modules.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	gproto "google.golang.org/protobuf/proto"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+)
+
+// Ext is the extension of the WASI modules.
+const Ext = ".wasm"
+
+// CacheDirEnv is the environment variable naming the directory to keep the compiled code in.
+// Without it, the code is only cached in memory.
+const CacheDirEnv = "PROTOLINT_WASM_CACHE_DIR"
+
+var (
+	cacheMu sync.Mutex
+	caches  = make(map[string]wazero.CompilationCache)
+)
+
+// compilationCache returns the cache of the compiled code shared among the runtimes.
+// It's kept in the directory given by CacheDirEnv if any, because compiling a large module takes seconds.
+func compilationCache() wazero.CompilationCache {
+	dir := os.Getenv(CacheDirEnv)
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if c, ok := caches[dir]; ok {
+		return c
+	}
+	c := wazero.NewCompilationCache()
+	if 0 < len(dir) {
+		if d, err := wazero.NewCompilationCacheWithDir(dir); err == nil {
+			c = d
+		}
+	}
+	caches[dir] = c
+	return c
+}
+
+// IsModule reports whether the path is of a WASI module.
+func IsModule(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), Ext)
+}
+
+// RuleSet is the rule set provided by a WASI module. It implements shared.RuleSet.
+// The module is run once for each call with the request on the stdin and the response on the stdout,
+// so that the calls can be made concurrently.
+type RuleSet struct {
+	path    string
+	args    []string
+	stderr  io.Writer
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+
+	// mu guards listRules, which the rules are initialized with on each call.
+	mu        sync.RWMutex
+	listRules *proto.ListRulesRequest
+}
+
+// NewRuleSet compiles the WASI module at the path. The args are passed to the module after the path.
+// The stderr of the module is discarded unless verbose.
+func NewRuleSet(
+	path string,
+	args []string,
+	verbose bool,
+) (*RuleSet, error) {
+	bin, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCompilationCache(compilationCache()))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	module, err := runtime.CompileModule(ctx, bin)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to compile the plugin %s, err=%w", path, err)
+	}
+
+	stderr := io.Discard
+	if verbose {
+		stderr = os.Stderr
+	}
+	return &RuleSet{
+		path:    path,
+		args:    append([]string{path}, args...),
+		stderr:  stderr,
+		runtime: runtime,
+		module:  module,
+	}, nil
+}
+
+// Close releases the runtime of the module and the code compiled for it.
+// The rule set can't be used after that.
+func (s *RuleSet) Close() error {
+	return s.runtime.Close(context.Background())
+}
+
+// ListRules implements shared.RuleSet.
+func (s *RuleSet) ListRules(req *proto.ListRulesRequest) (*proto.ListRulesResponse, error) {
+	s.mu.Lock()
+	s.listRules = req
+	s.mu.Unlock()
+
+	var resp proto.ListRulesResponse
+	if err := s.call(&proto.WasmCall{ListRules: req}, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Apply implements shared.RuleSet.
+// The module reads the file by itself, so it can read the files under the working directory,
+// which is mounted as the root. The file out of it can't be linted.
+func (s *RuleSet) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, req.Path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("the plugin %s can't read %s out of the working directory %s", s.path, req.Path, root)
+	}
+	guestReq := &proto.ApplyRequest{
+		Id:   req.Id,
+		Path: "/" + filepath.ToSlash(rel),
+	}
+	fsConfig := wazero.NewFSConfig().WithReadOnlyDirMount(root, "/")
+
+	var resp proto.ApplyResponse
+	if err := s.call(&proto.WasmCall{ListRules: s.lastListRules(), Apply: guestReq}, &resp, fsConfig); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ApplyV2 implements shared.RuleSet.
+// The module reads no file, since the request has the content.
+func (s *RuleSet) ApplyV2(req *proto.ApplyV2Request) (*proto.ApplyV2Response, error) {
+	var resp proto.ApplyV2Response
+	if err := s.call(&proto.WasmCall{ListRules: s.lastListRules(), ApplyV2: req}, &resp, nil); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (s *RuleSet) lastListRules() *proto.ListRulesRequest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listRules
+}
+
+// call runs the module with the call, and decodes its stdout into resp.
+// The module can only read the directories mounted by fsConfig, and no file when it's nil.
+func (s *RuleSet) call(call *proto.WasmCall, resp gproto.Message, fsConfig wazero.FSConfig) error {
+	in, err := gproto.Marshal(call)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	config := wazero.NewModuleConfig().
+		// The empty name allows the concurrent instances.
+		WithName("").
+		WithArgs(s.args...).
+		WithStdin(bytes.NewReader(in)).
+		WithStdout(&stdout).
+		WithStderr(io.MultiWriter(&stderr, s.stderr)).
+		WithSysWalltime().
+		WithSysNanotime()
+	if fsConfig != nil {
+		config = config.WithFSConfig(fsConfig)
+	}
+
+	ctx := context.Background()
+	module, err := s.runtime.InstantiateModule(ctx, s.module, config)
+	if module != nil {
+		_ = module.Close(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run the plugin %s, err=%w, stderr=%s", s.path, err, strings.TrimSpace(stderr.String()))
+	}
+	if err := gproto.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("invalid response of the plugin %s, err=%w", s.path, err)
+	}
+	return nil
+}
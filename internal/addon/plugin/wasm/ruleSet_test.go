@@ -0,0 +1,196 @@
+package wasm_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+	"github.com/yoheimuta/protolint/internal/addon/plugin/wasm"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+)
+
+// buildExamplePlugin compiles the example plugin to a WASI module. It needs no network access
+// because the dependencies are the same as the ones of this module.
+func buildExamplePlugin(t *testing.T) string {
+	if testing.Short() {
+		t.Skip("skipping building the WASI module in short mode")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("not found the go command")
+	}
+
+	path := filepath.Join(t.TempDir(), "plugin_example.wasm")
+	cmd := exec.Command(gobin, "build", "-o", path, ".")
+	cmd.Dir = setting_test.ExamplePath("plugin")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build the example plugin, err=%v, out=%s", err, out)
+	}
+	return path
+}
+
+func TestRuleSet(t *testing.T) {
+	path := buildExamplePlugin(t)
+	cacheDir := t.TempDir()
+	t.Setenv(wasm.CacheDirEnv, cacheDir)
+	ruleSet, err := wasm.NewRuleSet(path, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := os.ReadDir(cacheDir); err != nil || len(entries) == 0 {
+		t.Errorf("got the cache entries %v, err=%v, but want the compiled code", entries, err)
+	}
+	defer func() {
+		_ = ruleSet.Close()
+	}()
+
+	const content = `syntax = "proto3";
+
+// Foo is a test.
+enum Foo {
+  A = 0;
+}
+`
+	// The old protocol only reads the files under the working directory.
+	dir := t.TempDir()
+	t.Chdir(dir)
+	protoPath := filepath.Join(dir, "a.proto")
+	outsidePath := filepath.Join(t.TempDir(), "a.proto")
+	for _, path := range []string{protoPath, outsidePath} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	allow, err := structpb.NewValue(map[string]interface{}{
+		"allow": []interface{}{"Foo"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		inputFixMode     bool
+		inputRuleConfigs map[string]*structpb.Value
+		inputID          string
+		inputV1          bool
+		inputPath        string
+		wantMessages     []string
+		wantExistErr     bool
+	}{
+		{
+			name:    "the content is linted",
+			inputID: "ENUM_NAMES_LOWER_SNAKE_CASE",
+			wantMessages: []string{
+				`Enum name "Foo" must be underscore_separated_names`,
+			},
+		},
+		{
+			name:    "the file is linted by the old protocol",
+			inputID: "ENUM_NAMES_LOWER_SNAKE_CASE",
+			inputV1: true,
+			wantMessages: []string{
+				`Enum name "Foo" must be underscore_separated_names`,
+			},
+		},
+		{
+			name:         "the file out of the working directory isn't read by the old protocol",
+			inputID:      "ENUM_NAMES_LOWER_SNAKE_CASE",
+			inputV1:      true,
+			inputPath:    outsidePath,
+			wantExistErr: true,
+		},
+		{
+			name:    "the rule is configured",
+			inputID: "ENUM_NAMES_LOWER_SNAKE_CASE",
+			inputRuleConfigs: map[string]*structpb.Value{
+				"ENUM_NAMES_LOWER_SNAKE_CASE": allow,
+			},
+		},
+		{
+			name:         "the rule is generated in the fix mode",
+			inputFixMode: true,
+			inputID:      "SIMPLE",
+			wantMessages: []string{
+				"Custom Rule, verbose=false, fixMode=true",
+			},
+		},
+		{
+			name:         "not found rule",
+			inputID:      "NOT_FOUND",
+			wantExistErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			list, err := ruleSet.ListRules(&proto.ListRulesRequest{
+				FixMode:         test.inputFixMode,
+				ProtocolVersion: 2,
+				RuleConfigs:     test.inputRuleConfigs,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, r := range list.Rules {
+				ids = append(ids, r.Id)
+			}
+			sort.Strings(ids)
+			wantIDs := []string{"ENUMS_HAVE_COMMENT", "ENUM_NAMES_LOWER_SNAKE_CASE", "SIMPLE"}
+			if !reflect.DeepEqual(ids, wantIDs) || list.ProtocolVersion != 2 {
+				t.Errorf("got %v and the version %d, but want %v and 2", ids, list.ProtocolVersion, wantIDs)
+			}
+
+			var gotMessages []string
+			if test.inputV1 {
+				path := protoPath
+				if test.inputPath != "" {
+					path = test.inputPath
+				}
+				resp, err := ruleSet.Apply(&proto.ApplyRequest{Id: test.inputID, Path: path})
+				if test.wantExistErr {
+					if err == nil {
+						t.Errorf("got err nil, but want err")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, f := range resp.Failures {
+					gotMessages = append(gotMessages, f.Message)
+				}
+			} else {
+				resp, err := ruleSet.ApplyV2(&proto.ApplyV2Request{
+					Id:      test.inputID,
+					Path:    "/not/found/a.proto",
+					Content: []byte(content),
+				})
+				if test.wantExistErr {
+					if err == nil {
+						t.Errorf("got err nil, but want err")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, f := range resp.Failures {
+					gotMessages = append(gotMessages, f.Message)
+				}
+			}
+			if !reflect.DeepEqual(gotMessages, test.wantMessages) {
+				t.Errorf("got %v, but want %v", gotMessages, test.wantMessages)
+			}
+		})
+	}
+}
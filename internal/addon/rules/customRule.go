@@ -0,0 +1,386 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/expr"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/strs"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// customRuleTargets maps the targets of the custom rules to their labels in the messages.
+var customRuleTargets = map[string]string{
+	"message":    "Message",
+	"field":      "Field",
+	"enum":       "Enum",
+	"enum_value": "Enum value",
+	"service":    "Service",
+	"rpc":        "RPC",
+	"oneof":      "Oneof",
+	"package":    "Package",
+	"option":     "Option",
+}
+
+// customRuleAutoDisableTargets are the targets which the auto-disable can put the comments on.
+// See CustomRuleSupportsAutoDisable.
+var customRuleAutoDisableTargets = map[string]bool{
+	"message":    true,
+	"field":      true,
+	"enum":       true,
+	"enum_value": true,
+	"service":    true,
+	"rpc":        true,
+}
+
+// customRuleWordPositions maps the word positions to the verbs in the messages.
+var customRuleWordPositions = map[string]string{
+	"first": "start with",
+	"last":  "end with",
+	"any":   "contain",
+}
+
+// CustomRuleMessageData is the data given to the message template of a custom rule.
+type CustomRuleMessageData struct {
+	// ID is the rule ID.
+	ID string
+	// Target is the target of the rule, like message.
+	Target string
+	// Name is the name of the element.
+	Name string
+	// Reason is the default message, like `Message name "FooDto" must not end with the word "Dto".`.
+	Reason string
+}
+
+// CustomRule verifies the names of the elements with the checks declared in the configuration,
+// and the elements with the expressions of the expr package.
+type CustomRule struct {
+	RuleWithSeverity
+	id           string
+	purpose      string
+	target       string
+	pattern      *regexp.Regexp
+	notPattern   *regexp.Regexp
+	allowWords   []string
+	denyWords    []string
+	wordPosition string
+	when         *expr.Program
+	expr         *expr.Program
+	message      *template.Template
+
+	autoDisableType autodisable.PlacementType
+}
+
+// NewCustomRule creates a new CustomRule. It returns an error if the declaration is invalid.
+// The auto-disable is ignored for the targets which CustomRuleSupportsAutoDisable rejects.
+func NewCustomRule(
+	declared config.CustomRule,
+	autoDisableType autodisable.PlacementType,
+) (CustomRule, error) {
+	fail := func(format string, a ...interface{}) (CustomRule, error) {
+		return CustomRule{}, fmt.Errorf("invalid custom rule %q: %s", declared.ID, fmt.Sprintf(format, a...))
+	}
+
+	if declared.ID == "" {
+		return fail("id is required")
+	}
+	label, ok := customRuleTargets[declared.Target]
+	if !ok {
+		return fail("target %q must be one of %s", declared.Target, sortedKeysOf(customRuleTargets))
+	}
+	if declared.Pattern == "" && declared.NotPattern == "" && len(declared.AllowWords) == 0 && len(declared.DenyWords) == 0 &&
+		declared.Expr == "" {
+		return fail("one of pattern, not_pattern, allow_words, deny_words and expr is required")
+	}
+
+	r := CustomRule{
+		RuleWithSeverity: RuleWithSeverity{severity: declared.Severity},
+		id:               declared.ID,
+		purpose:          declared.Purpose,
+		target:           declared.Target,
+		allowWords:       declared.AllowWords,
+		denyWords:        declared.DenyWords,
+		wordPosition:     declared.WordPosition,
+	}
+	if CustomRuleSupportsAutoDisable(declared.Target) {
+		r.autoDisableType = autoDisableType
+	}
+	if r.purpose == "" {
+		r.purpose = fmt.Sprintf("Verifies the %s names with the custom rule declared in the configuration.", strings.ToLower(label))
+	}
+	if r.wordPosition == "" {
+		r.wordPosition = "any"
+	}
+	if _, ok := customRuleWordPositions[r.wordPosition]; !ok {
+		return fail("word_position %q must be one of %s", r.wordPosition, sortedKeysOf(customRuleWordPositions))
+	}
+
+	var err error
+	if declared.Pattern != "" {
+		if r.pattern, err = regexp.Compile(declared.Pattern); err != nil {
+			return fail("pattern: %v", err)
+		}
+	}
+	if declared.NotPattern != "" {
+		if r.notPattern, err = regexp.Compile(declared.NotPattern); err != nil {
+			return fail("not_pattern: %v", err)
+		}
+	}
+	vars, _ := expr.ElementVars(declared.Target)
+	if declared.When != "" {
+		if r.when, err = expr.Compile(declared.When, vars); err != nil {
+			return fail("when: %v", err)
+		}
+	}
+	if declared.Expr != "" {
+		if r.expr, err = expr.Compile(declared.Expr, vars); err != nil {
+			return fail("expr: %v", err)
+		}
+	}
+	if declared.Message != "" {
+		r.message, err = template.New(declared.ID).Option("missingkey=error").Parse(declared.Message)
+		if err != nil {
+			return fail("message: %v", err)
+		}
+		if err := r.message.Execute(&bytes.Buffer{}, CustomRuleMessageData{}); err != nil {
+			return fail("message: %v", err)
+		}
+	}
+	return r, nil
+}
+
+// CustomRuleSupportsAutoDisable reports whether the auto-disable can put the comments on the target.
+// The package, option and oneof targets aren't supported like the builtin rules.
+func CustomRuleSupportsAutoDisable(target string) bool {
+	return customRuleAutoDisableTargets[target]
+}
+
+func sortedKeysOf(m map[string]string) string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return "[" + strings.Join(keys, ", ") + "]"
+}
+
+// ID returns the ID of this rule.
+func (r CustomRule) ID() string {
+	return r.id
+}
+
+// Purpose returns the purpose of this rule.
+func (r CustomRule) Purpose() string {
+	return r.purpose
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+// The custom rules are enabled by default like the official ones, because they are declared to be applied.
+func (r CustomRule) IsOfficial() bool {
+	return true
+}
+
+// Apply applies the rule to the proto.
+func (r CustomRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := &customRuleVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		rule:           r,
+	}
+	if r.when != nil || r.expr != nil {
+		v.elements = expr.Elements(proto)
+	}
+	return visitor.RunVisitorAutoDisable(v, proto, r.ID(), r.autoDisableType)
+}
+
+// reasons returns the default messages of the checks which the element violates.
+// The attributes of the element are given when the rule has the expressions.
+func (r CustomRule) reasons(name string, element map[string]interface{}) ([]string, error) {
+	label := customRuleTargets[r.target]
+	var reasons []string
+	if r.when != nil {
+		ok, err := r.when.Eval(element)
+		if err != nil || !ok {
+			return nil, err
+		}
+	}
+	if r.expr != nil {
+		ok, err := r.expr.Eval(element)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("%s %q must satisfy `%s`.", label, name, r.expr))
+		}
+	}
+	if r.pattern != nil && !r.pattern.MatchString(name) {
+		reasons = append(reasons, fmt.Sprintf("%s name %q must match %q.", label, name, r.pattern))
+	}
+	if r.notPattern != nil && r.notPattern.MatchString(name) {
+		reasons = append(reasons, fmt.Sprintf("%s name %q must not match %q.", label, name, r.notPattern))
+	}
+	if len(r.allowWords) == 0 && len(r.denyWords) == 0 {
+		return reasons, nil
+	}
+
+	words := splitNameWords(name)
+	switch {
+	case len(words) == 0:
+	case r.wordPosition == "first":
+		words = words[:1]
+	case r.wordPosition == "last":
+		words = words[len(words)-1:]
+	}
+	verb := customRuleWordPositions[r.wordPosition]
+
+	if len(r.allowWords) != 0 && findWord(words, r.allowWords) == "" {
+		reasons = append(reasons, fmt.Sprintf("%s name %q must %s one of the words [%s].", label, name, verb, strings.Join(r.allowWords, ", ")))
+	}
+	if denied := findWord(words, r.denyWords); denied != "" {
+		reasons = append(reasons, fmt.Sprintf("%s name %q must not %s the word %q.", label, name, verb, denied))
+	}
+	return reasons, nil
+}
+
+// findWord returns the first one of the candidates which is in words, ignoring the case.
+func findWord(words []string, candidates []string) string {
+	for _, c := range candidates {
+		for _, w := range words {
+			if strings.EqualFold(w, c) {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
+// splitNameWords splits the name into the words. The dots, the underscores and the parentheses
+// separate the words, and so does the case of the CamelCase names.
+func splitNameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if camel := strs.SplitCamelCaseWord(part); camel != nil {
+			words = append(words, camel...)
+			continue
+		}
+		words = append(words, part)
+	}
+	return words
+}
+
+type customRuleVisitor struct {
+	*visitor.BaseAddVisitor
+	rule CustomRule
+	// elements are the attributes of the elements, which are set only when the rule has the expressions.
+	elements map[parser.Visitee]map[string]interface{}
+}
+
+func (v *customRuleVisitor) check(target string, element parser.Visitee, pos meta.Position, name string) {
+	if target != v.rule.target {
+		return
+	}
+	attributes, ok := v.elements[element]
+	if v.elements != nil && !ok {
+		return
+	}
+	reasons, err := v.rule.reasons(name, attributes)
+	if err != nil {
+		// Report the element which the expressions fail to evaluate over, and keep checking the others.
+		v.AddFailuref(pos, "%s %q can't be checked: %v", customRuleTargets[target], name, err)
+		return
+	}
+	for _, reason := range reasons {
+		message := reason
+		if v.rule.message != nil {
+			var b bytes.Buffer
+			err := v.rule.message.Execute(&b, CustomRuleMessageData{
+				ID:     v.rule.id,
+				Target: target,
+				Name:   name,
+				Reason: reason,
+			})
+			if err == nil {
+				message = b.String()
+			}
+		}
+		v.AddFailuref(pos, "%s", message)
+	}
+}
+
+// VisitPackage checks the package.
+func (v *customRuleVisitor) VisitPackage(p *parser.Package) bool {
+	v.check("package", p, p.Meta.Pos, p.Name)
+	return false
+}
+
+// VisitOption checks the option.
+func (v *customRuleVisitor) VisitOption(o *parser.Option) bool {
+	v.check("option", o, o.Meta.Pos, o.OptionName)
+	return false
+}
+
+// VisitMessage checks the message.
+func (v *customRuleVisitor) VisitMessage(m *parser.Message) bool {
+	v.check("message", m, m.Meta.Pos, m.MessageName)
+	return true
+}
+
+// VisitField checks the field.
+func (v *customRuleVisitor) VisitField(f *parser.Field) bool {
+	v.check("field", f, f.Meta.Pos, f.FieldName)
+	return false
+}
+
+// VisitMapField checks the field.
+func (v *customRuleVisitor) VisitMapField(f *parser.MapField) bool {
+	v.check("field", f, f.Meta.Pos, f.MapName)
+	return false
+}
+
+// VisitOneof checks the oneof.
+func (v *customRuleVisitor) VisitOneof(o *parser.Oneof) bool {
+	v.check("oneof", o, o.Meta.Pos, o.OneofName)
+	return true
+}
+
+// VisitOneofField checks the field.
+func (v *customRuleVisitor) VisitOneofField(f *parser.OneofField) bool {
+	v.check("field", f, f.Meta.Pos, f.FieldName)
+	return false
+}
+
+// VisitEnum checks the enum.
+func (v *customRuleVisitor) VisitEnum(e *parser.Enum) bool {
+	v.check("enum", e, e.Meta.Pos, e.EnumName)
+	return true
+}
+
+// VisitEnumField checks the enum value.
+func (v *customRuleVisitor) VisitEnumField(f *parser.EnumField) bool {
+	v.check("enum_value", f, f.Meta.Pos, f.Ident)
+	return false
+}
+
+// VisitService checks the service.
+func (v *customRuleVisitor) VisitService(s *parser.Service) bool {
+	v.check("service", s, s.Meta.Pos, s.ServiceName)
+	return true
+}
+
+// VisitRPC checks the rpc.
+func (v *customRuleVisitor) VisitRPC(r *parser.RPC) bool {
+	v.check("rpc", r, r.Meta.Pos, r.RPCName)
+	return true
+}
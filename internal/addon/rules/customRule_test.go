@@ -0,0 +1,438 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testCustomRuleProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "customRule", name)
+}
+
+func TestNewCustomRule(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputRule            config.CustomRule
+		inputAutoDisableType autodisable.PlacementType
+		wantExistErr         bool
+	}{
+		{
+			name: "a valid rule",
+			inputRule: config.CustomRule{
+				ID:      "RPC_NAMES_GET",
+				Target:  "rpc",
+				Pattern: "^Get",
+				Message: "{{.Name}}: {{.Reason}}",
+			},
+		},
+		{
+			name: "an error for the missing id",
+			inputRule: config.CustomRule{
+				Target:  "rpc",
+				Pattern: "^Get",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for the unknown target",
+			inputRule: config.CustomRule{
+				ID:      "RPC_NAMES_GET",
+				Target:  "method",
+				Pattern: "^Get",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for no checks",
+			inputRule: config.CustomRule{
+				ID:     "RPC_NAMES_GET",
+				Target: "rpc",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for the invalid pattern",
+			inputRule: config.CustomRule{
+				ID:      "RPC_NAMES_GET",
+				Target:  "rpc",
+				Pattern: "^(Get",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for the unknown word position",
+			inputRule: config.CustomRule{
+				ID:           "RPC_NAMES_GET",
+				Target:       "rpc",
+				AllowWords:   []string{"Get"},
+				WordPosition: "second",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "a valid rule with the expressions",
+			inputRule: config.CustomRule{
+				ID:     "TIMESTAMP_FIELDS_END_WITH_TIME",
+				Target: "field",
+				When:   `type == "google.protobuf.Timestamp"`,
+				Expr:   `name.endsWith("_time")`,
+			},
+		},
+		{
+			name: "an error for the unknown attribute in the expression",
+			inputRule: config.CustomRule{
+				ID:     "STREAMING_RPCS",
+				Target: "rpc",
+				Expr:   `label == "repeated"`,
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for the when expression which is not bool",
+			inputRule: config.CustomRule{
+				ID:     "TIMESTAMP_FIELDS_END_WITH_TIME",
+				Target: "field",
+				When:   `type`,
+				Expr:   `name.endsWith("_time")`,
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "an error for the message with an unknown field",
+			inputRule: config.CustomRule{
+				ID:      "RPC_NAMES_GET",
+				Target:  "rpc",
+				Pattern: "^Get",
+				Message: "{{.Unknown}}",
+			},
+			wantExistErr: true,
+		},
+		{
+			name: "a valid rule with the auto-disable",
+			inputRule: config.CustomRule{
+				ID:      "RPC_NAMES_GET",
+				Target:  "rpc",
+				Pattern: "^Get",
+			},
+			inputAutoDisableType: autodisable.Next,
+		},
+		{
+			name: "a valid rule ignoring the auto-disable for the target which it doesn't support",
+			inputRule: config.CustomRule{
+				ID:      "PACKAGE_VERSIONED",
+				Target:  "package",
+				Pattern: `\.v\d+$`,
+			},
+			inputAutoDisableType: autodisable.ThisThenNext,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rules.NewCustomRule(test.inputRule, test.inputAutoDisableType)
+			if test.wantExistErr != (err != nil) {
+				t.Errorf("got err %v, but want err %t", err, test.wantExistErr)
+			}
+		})
+	}
+}
+
+func TestCustomRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputRule     config.CustomRule
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for the names matching the pattern",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				ID:      "PACKAGE_VERSIONED",
+				Target:  "package",
+				Pattern: `\.v\d+$`,
+			},
+		},
+		{
+			name:          "a failure for the rpc name not starting with the allowed words",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				ID:           "RPC_NAMES_START_WITH_VERB",
+				Target:       "rpc",
+				AllowWords:   []string{"Get", "List"},
+				WordPosition: "first",
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("names.proto"),
+						Offset:   354,
+						Line:     23,
+						Column:   3,
+					},
+					"RPC_NAMES_START_WITH_VERB",
+					string(rule.SeverityError),
+					`RPC name "FetchFoo" must start with one of the words [Get, List].`,
+				),
+			},
+		},
+		{
+			name:          "no failures for the enum value with the denied word and the disable comment",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				CustomizableSeverityOption: config.CustomizableSeverityOption{
+					Severity: rule.SeverityWarning,
+				},
+				ID:        "NO_DTO",
+				Target:    "enum_value",
+				DenyWords: []string{"dto"},
+			},
+		},
+		{
+			name:          "a failure with the message template",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				CustomizableSeverityOption: config.CustomizableSeverityOption{
+					Severity: rule.SeverityWarning,
+				},
+				ID:           "MESSAGE_NAMES_NO_DTO",
+				Target:       "message",
+				DenyWords:    []string{"Dto"},
+				WordPosition: "last",
+				Message:      "{{.Name}} must not be a DTO: {{.Reason}}",
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("names.proto"),
+						Offset:   74,
+						Line:     7,
+						Column:   1,
+					},
+					"MESSAGE_NAMES_NO_DTO",
+					string(rule.SeverityWarning),
+					`FooDto must not be a DTO: Message name "FooDto" must not end with the word "Dto".`,
+				),
+			},
+		},
+		{
+			name:          "failures for the fields matching the not_pattern",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				ID:         "FIELD_NAMES_NO_SUFFIX",
+				Target:     "field",
+				NotPattern: `_(name|map)$`,
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("names.proto"),
+						Offset:   93,
+						Line:     8,
+						Column:   3,
+					},
+					"FIELD_NAMES_NO_SUFFIX",
+					string(rule.SeverityError),
+					`Field name "user_name" must not match "_(name|map)$".`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("names.proto"),
+						Offset:   117,
+						Line:     9,
+						Column:   3,
+					},
+					"FIELD_NAMES_NO_SUFFIX",
+					string(rule.SeverityError),
+					`Field name "labels_map" must not match "_(name|map)$".`,
+				),
+			},
+		},
+		{
+			name:          "a failure for the option name",
+			inputFilename: "names.proto",
+			inputRule: config.CustomRule{
+				ID:        "NO_JAVA_OPTIONS",
+				Target:    "option",
+				DenyWords: []string{"java"},
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("names.proto"),
+						Offset:   37,
+						Line:     5,
+						Column:   1,
+					},
+					"NO_JAVA_OPTIONS",
+					string(rule.SeverityError),
+					`Option name "java_package" must not contain the word "java".`,
+				),
+			},
+		},
+		{
+			name:          "a failure for the timestamp field not ending with _time",
+			inputFilename: "expressions.proto",
+			inputRule: config.CustomRule{
+				ID:     "TIMESTAMP_FIELDS_END_WITH_TIME",
+				Target: "field",
+				When:   `type == "google.protobuf.Timestamp"`,
+				Expr:   `name.endsWith("_time")`,
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("expressions.proto"),
+						Offset:   141,
+						Line:     9,
+						Column:   3,
+					},
+					"TIMESTAMP_FIELDS_END_WITH_TIME",
+					string(rule.SeverityError),
+					"Field \"updated\" must satisfy `name.endsWith(\"_time\")`.",
+				),
+			},
+		},
+		{
+			name:          "a failure with the message template for the streaming rpc",
+			inputFilename: "expressions.proto",
+			inputRule: config.CustomRule{
+				ID:      "STREAMING_RPC_NAMES",
+				Target:  "rpc",
+				When:    `client_streaming || server_streaming`,
+				Expr:    `name.contains("Stream")`,
+				Message: "{{.Name}} is a streaming RPC.",
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("expressions.proto"),
+						Offset:   364,
+						Line:     17,
+						Column:   3,
+					},
+					"STREAMING_RPC_NAMES",
+					string(rule.SeverityError),
+					"WatchFoo is a streaming RPC.",
+				),
+			},
+		},
+		{
+			name:          "failures for the fields which the expression fails to evaluate over",
+			inputFilename: "expressions.proto",
+			inputRule: config.CustomRule{
+				ID:     "FIELDS_IN_NESTED_MESSAGES",
+				Target: "field",
+				Expr:   `name.endsWith("_time") || parent.parent.parent.name == ""`,
+			},
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("expressions.proto"),
+						Offset:   141,
+						Line:     9,
+						Column:   3,
+					},
+					"FIELDS_IN_NESTED_MESSAGES",
+					string(rule.SeverityError),
+					"Field \"updated\" can't be checked: failed to evaluate \"name.endsWith(\\\"_time\\\") || parent.parent.parent.name == \\\"\\\"\": column 48: cannot read .name of null",
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("expressions.proto"),
+						Offset:   241,
+						Line:     11,
+						Column:   3,
+					},
+					"FIELDS_IN_NESTED_MESSAGES",
+					string(rule.SeverityError),
+					"Field \"deleted\" can't be checked: failed to evaluate \"name.endsWith(\\\"_time\\\") || parent.parent.parent.name == \\\"\\\"\": column 48: cannot read .name of null",
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testCustomRuleProtoPath("expressions.proto"),
+						Offset:   282,
+						Line:     12,
+						Column:   3,
+					},
+					"FIELDS_IN_NESTED_MESSAGES",
+					string(rule.SeverityError),
+					"Field \"updated_by\" can't be checked: failed to evaluate \"name.endsWith(\\\"_time\\\") || parent.parent.parent.name == \\\"\\\"\": column 48: cannot read .name of null",
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := rules.NewCustomRule(test.inputRule, autodisable.Noop)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			protoPath := testCustomRuleProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
+
+func TestCustomRule_Apply_disable(t *testing.T) {
+	tests := []struct {
+		name               string
+		inputPlacementType autodisable.PlacementType
+		wantFilename       string
+	}{
+		{
+			name:               "insert disable:next comments",
+			inputPlacementType: autodisable.Next,
+			wantFilename:       "disable_next.proto",
+		},
+		{
+			name:               "insert disable:this comments",
+			inputPlacementType: autodisable.ThisThenNext,
+			wantFilename:       "disable_this.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			// The ID names the directory of the test data.
+			r, err := rules.NewCustomRule(config.CustomRule{
+				ID:         "CUSTOM_RULE",
+				Target:     "field",
+				NotPattern: `_(name|map)$`,
+			}, test.inputPlacementType)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			testApplyFix(t, r, "names.proto", test.wantFilename)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+const (
+	// maxFieldNumber is the largest field number.
+	maxFieldNumber = 1<<29 - 1
+	// implementationReservedBegin and implementationReservedEnd are the bounds of the field numbers
+	// reserved for the Protocol Buffers implementation.
+	implementationReservedBegin = 19000
+	implementationReservedEnd   = 19999
+)
+
+// numberedElement is a field or an enum value with its number.
+type numberedElement struct {
+	// kind is either "Field" or "Enum value".
+	kind   string
+	name   string
+	number string
+	// value is the parsed number, and is valid only when ok is true.
+	value int64
+	ok    bool
+	// overflow is true when the number doesn't fit into int64.
+	overflow bool
+	pos      meta.Position
+}
+
+func newNumberedElement(kind, name, number string, pos meta.Position) numberedElement {
+	value, err := strconv.ParseInt(number, 0, 64)
+	return numberedElement{
+		kind:     kind,
+		name:     name,
+		number:   number,
+		value:    value,
+		ok:       err == nil,
+		overflow: errors.Is(err, strconv.ErrRange),
+		pos:      pos,
+	}
+}
+
+// numberRange is an inclusive range of the numbers.
+type numberRange struct {
+	begin int64
+	end   int64
+}
+
+func (r numberRange) contains(n int64) bool {
+	return r.begin <= n && n <= r.end
+}
+
+// numberedScope is a message or an enum, which has its own set of the numbers.
+type numberedScope struct {
+	isEnum     bool
+	allowAlias bool
+	elements   []numberedElement
+	reserved   []numberRange
+	// reservedNames are the names listed in the reserved statements, without the quotes.
+	reservedNames map[string]bool
+	extensions    []numberRange
+}
+
+// maxNumber returns the largest number of the scope, which "max" means in the ranges.
+func (s numberedScope) maxNumber() int64 {
+	if s.isEnum {
+		return math.MaxInt32
+	}
+	return maxFieldNumber
+}
+
+// isReserved reports whether the number is listed in the reserved statements.
+func (s numberedScope) isReserved(n int64) bool {
+	for _, r := range s.reserved {
+		if r.contains(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMessageScope creates a numberedScope from the body of a message or a group.
+// The fields of the oneofs are included, but the ones of the nested messages are not.
+func newMessageScope(body []parser.Visitee) numberedScope {
+	scope := numberedScope{
+		reservedNames: make(map[string]bool),
+	}
+	for _, element := range body {
+		switch e := element.(type) {
+		case *parser.Field:
+			scope.elements = append(scope.elements, newNumberedElement("Field", e.FieldName, e.FieldNumber, e.Meta.Pos))
+		case *parser.MapField:
+			scope.elements = append(scope.elements, newNumberedElement("Field", e.MapName, e.FieldNumber, e.Meta.Pos))
+		case *parser.GroupField:
+			scope.elements = append(scope.elements, newNumberedElement("Field", e.GroupName, e.FieldNumber, e.Meta.Pos))
+		case *parser.Oneof:
+			for _, f := range e.OneofFields {
+				scope.elements = append(scope.elements, newNumberedElement("Field", f.FieldName, f.FieldNumber, f.Meta.Pos))
+			}
+		case *parser.Reserved:
+			scope.addReserved(e)
+		case *parser.Extensions:
+			scope.extensions = append(scope.extensions, scope.parseRanges(e.Ranges)...)
+		}
+	}
+	return scope
+}
+
+// newEnumScope creates a numberedScope from an enum.
+func newEnumScope(enum *parser.Enum) numberedScope {
+	scope := numberedScope{
+		isEnum:        true,
+		reservedNames: make(map[string]bool),
+	}
+	for _, element := range enum.EnumBody {
+		switch e := element.(type) {
+		case *parser.EnumField:
+			scope.elements = append(scope.elements, newNumberedElement("Enum value", e.Ident, e.Number, e.Meta.Pos))
+		case *parser.Reserved:
+			scope.addReserved(e)
+		case *parser.Option:
+			if e.OptionName == "allow_alias" && e.Constant == "true" {
+				scope.allowAlias = true
+			}
+		}
+	}
+	return scope
+}
+
+func (s *numberedScope) addReserved(reserved *parser.Reserved) {
+	s.reserved = append(s.reserved, s.parseRanges(reserved.Ranges)...)
+	for _, name := range reserved.FieldNames {
+		s.reservedNames[strings.Trim(name, `"'`)] = true
+	}
+}
+
+func (s numberedScope) parseRanges(ranges []*parser.Range) []numberRange {
+	var parsed []numberRange
+	for _, r := range ranges {
+		begin, err := strconv.ParseInt(r.Begin, 0, 64)
+		if err != nil {
+			continue
+		}
+		end := begin
+		switch r.End {
+		case "":
+		case "max":
+			end = s.maxNumber()
+		default:
+			end, err = strconv.ParseInt(r.End, 0, 64)
+			if err != nil {
+				continue
+			}
+		}
+		parsed = append(parsed, numberRange{begin: begin, end: end})
+	}
+	return parsed
+}
+
+// usedRanges returns the merged and sorted ranges of the numbers which the elements,
+// the reserved statements and the extensions take.
+func (s numberedScope) usedRanges() []numberRange {
+	var ranges []numberRange
+	for _, e := range s.elements {
+		if e.ok {
+			ranges = append(ranges, numberRange{begin: e.value, end: e.value})
+		}
+	}
+	ranges = append(ranges, s.reserved...)
+	ranges = append(ranges, s.extensions...)
+	if !s.isEnum {
+		ranges = append(ranges, numberRange{begin: implementationReservedBegin, end: implementationReservedEnd})
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].begin < ranges[j].begin
+	})
+
+	var merged []numberRange
+	for _, r := range ranges {
+		last := len(merged) - 1
+		if 0 <= last && r.begin <= merged[last].end+1 {
+			if merged[last].end < r.end {
+				merged[last].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// numberedFailure is a failure found on an element of a numberedScope.
+type numberedFailure struct {
+	pos     meta.Position
+	message string
+}
+
+func newNumberedFailure(e numberedElement, format string, a ...interface{}) numberedFailure {
+	return numberedFailure{
+		pos:     e.pos,
+		message: fmt.Sprintf(format, a...),
+	}
+}
+
+// numberedScopeVisitor checks each message, group and enum with check, and reports the failures
+// when visiting the elements they are found on. It lets the disable comments on the elements work.
+type numberedScopeVisitor struct {
+	*visitor.BaseAddVisitor
+	check   func(scope numberedScope) []numberedFailure
+	pending map[meta.Position][]string
+}
+
+func newNumberedScopeVisitor(
+	ruleID string,
+	severity string,
+	check func(scope numberedScope) []numberedFailure,
+) *numberedScopeVisitor {
+	return &numberedScopeVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(ruleID, severity),
+		check:          check,
+		pending:        make(map[meta.Position][]string),
+	}
+}
+
+func (v *numberedScopeVisitor) checkScope(scope numberedScope) {
+	for _, f := range v.check(scope) {
+		v.pending[f.pos] = append(v.pending[f.pos], f.message)
+	}
+}
+
+func (v *numberedScopeVisitor) flush(pos meta.Position) {
+	for _, message := range v.pending[pos] {
+		v.AddFailuref(pos, "%s", message)
+	}
+	delete(v.pending, pos)
+}
+
+// VisitMessage checks the message.
+func (v *numberedScopeVisitor) VisitMessage(message *parser.Message) bool {
+	v.checkScope(newMessageScope(message.MessageBody))
+	return true
+}
+
+// VisitGroupField reports the failures on the group and checks its body.
+func (v *numberedScopeVisitor) VisitGroupField(group *parser.GroupField) bool {
+	v.flush(group.Meta.Pos)
+	v.checkScope(newMessageScope(group.MessageBody))
+	return true
+}
+
+// VisitEnum checks the enum.
+func (v *numberedScopeVisitor) VisitEnum(enum *parser.Enum) bool {
+	v.checkScope(newEnumScope(enum))
+	return true
+}
+
+// VisitField reports the failures on the field.
+func (v *numberedScopeVisitor) VisitField(field *parser.Field) bool {
+	v.flush(field.Meta.Pos)
+	return false
+}
+
+// VisitMapField reports the failures on the field.
+func (v *numberedScopeVisitor) VisitMapField(field *parser.MapField) bool {
+	v.flush(field.Meta.Pos)
+	return false
+}
+
+// VisitOneofField reports the failures on the field.
+func (v *numberedScopeVisitor) VisitOneofField(field *parser.OneofField) bool {
+	v.flush(field.Meta.Pos)
+	return false
+}
+
+// VisitEnumField reports the failures on the enum value.
+func (v *numberedScopeVisitor) VisitEnumField(field *parser.EnumField) bool {
+	v.flush(field.Meta.Pos)
+	return false
+}
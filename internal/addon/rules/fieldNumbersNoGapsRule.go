@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// defaultFieldNumbersMaxGap is the default number of the unused numbers allowed in a row.
+const defaultFieldNumbersMaxGap = 10
+
+// FieldNumbersNoGapsRule verifies that a message or an enum doesn't leave many numbers unused in a row.
+// The numbers listed in the reserved statements and the extensions explain the gaps, and so do the ones
+// reserved for the Protocol Buffers implementation. The numbers of the fields start from 1, and the ones
+// of the enum values start from 0.
+type FieldNumbersNoGapsRule struct {
+	RuleWithSeverity
+	maxGap int
+}
+
+// NewFieldNumbersNoGapsRule creates a new FieldNumbersNoGapsRule.
+// A maxGap of zero or less means the default, which is 10.
+func NewFieldNumbersNoGapsRule(
+	severity rule.Severity,
+	maxGap int,
+) FieldNumbersNoGapsRule {
+	if maxGap <= 0 {
+		maxGap = defaultFieldNumbersMaxGap
+	}
+	return FieldNumbersNoGapsRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		maxGap:           maxGap,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r FieldNumbersNoGapsRule) ID() string {
+	return "FIELD_NUMBERS_NO_GAPS"
+}
+
+// Purpose returns the purpose of this rule.
+func (r FieldNumbersNoGapsRule) Purpose() string {
+	return "Verifies that large gaps between field numbers are explained by reserved statements."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r FieldNumbersNoGapsRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r FieldNumbersNoGapsRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := newNumberedScopeVisitor(r.ID(), string(r.Severity()), r.check)
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+func (r FieldNumbersNoGapsRule) check(scope numberedScope) []numberedFailure {
+	var elements []numberedElement
+	for _, e := range scope.elements {
+		if e.ok {
+			elements = append(elements, e)
+		}
+	}
+	sort.SliceStable(elements, func(i, j int) bool {
+		return elements[i].value < elements[j].value
+	})
+
+	var start int64 = 1
+	if scope.isEnum {
+		start = 0
+	}
+
+	var failures []numberedFailure
+	last := start - 1
+	for _, used := range scope.usedRanges() {
+		if used.end <= last {
+			continue
+		}
+		gap := used.begin - last - 1
+		if int64(r.maxGap) < gap {
+			// The gap is reported on the first element after it.
+			i := sort.Search(len(elements), func(i int) bool {
+				return used.begin <= elements[i].value
+			})
+			if i < len(elements) {
+				e := elements[i]
+				failures = append(failures, newNumberedFailure(
+					e,
+					"%s %q has number %s, leaving %s unused without reserving them.",
+					e.kind, e.name, e.number, describeNumberRange(last+1, used.begin-1),
+				))
+			}
+		}
+		last = used.end
+	}
+	return failures
+}
+
+func describeNumberRange(begin, end int64) string {
+	return fmt.Sprintf("the %d numbers from %d to %d", end-begin+1, begin, end)
+}
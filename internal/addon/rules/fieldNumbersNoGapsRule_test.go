@@ -0,0 +1,117 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testFieldNumbersNoGapsProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "fieldNumbersNoGaps", name)
+}
+
+func TestFieldNumbersNoGapsRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputMaxGap   int
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto without the gaps not explained by reserved statements",
+			inputFilename: "valid.proto",
+		},
+		{
+			name:          "failures for proto with the gaps not explained by reserved statements",
+			inputFilename: "invalid.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNoGapsProtoPath("invalid.proto"),
+						Offset:   36,
+						Line:     4,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NO_GAPS",
+					string(rule.SeverityError),
+					`Field "a" has number 12, leaving the 11 numbers from 1 to 11 unused without reserving them.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNoGapsProtoPath("invalid.proto"),
+						Offset:   91,
+						Line:     7,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NO_GAPS",
+					string(rule.SeverityError),
+					`Field "c" has number 50, leaving the 29 numbers from 21 to 49 unused without reserving them.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNoGapsProtoPath("invalid.proto"),
+						Offset:   127,
+						Line:     9,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_NO_GAPS",
+					string(rule.SeverityError),
+					`Field "d" has number 100, leaving the 49 numbers from 51 to 99 unused without reserving them.`,
+				),
+			},
+		},
+		{
+			name:          "a failure for proto with the gap larger than the configured max",
+			inputMaxGap:   30,
+			inputFilename: "invalid.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNoGapsProtoPath("invalid.proto"),
+						Offset:   127,
+						Line:     9,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_NO_GAPS",
+					string(rule.SeverityError),
+					`Field "d" has number 100, leaving the 49 numbers from 51 to 99 unused without reserving them.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldNumbersNoGapsRule(
+				rule.SeverityError,
+				test.inputMaxGap,
+			)
+
+			protoPath := testFieldNumbersNoGapsProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
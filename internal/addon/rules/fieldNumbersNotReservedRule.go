@@ -0,0 +1,58 @@
+package rules
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// FieldNumbersNotReservedRule verifies that fields and enum values don't reuse a number or a name
+// listed in the reserved statements of their message or enum.
+type FieldNumbersNotReservedRule struct {
+	RuleWithSeverity
+}
+
+// NewFieldNumbersNotReservedRule creates a new FieldNumbersNotReservedRule.
+func NewFieldNumbersNotReservedRule(
+	severity rule.Severity,
+) FieldNumbersNotReservedRule {
+	return FieldNumbersNotReservedRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r FieldNumbersNotReservedRule) ID() string {
+	return "FIELD_NUMBERS_NOT_RESERVED"
+}
+
+// Purpose returns the purpose of this rule.
+func (r FieldNumbersNotReservedRule) Purpose() string {
+	return "Verifies that fields and enum values don't reuse a reserved number or name."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r FieldNumbersNotReservedRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r FieldNumbersNotReservedRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := newNumberedScopeVisitor(r.ID(), string(r.Severity()), checkFieldNumbersNotReserved)
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+func checkFieldNumbersNotReserved(scope numberedScope) []numberedFailure {
+	var failures []numberedFailure
+	for _, e := range scope.elements {
+		if e.ok && scope.isReserved(e.value) {
+			failures = append(failures, newNumberedFailure(e, "%s %q reuses the reserved number %s.", e.kind, e.name, e.number))
+		}
+		if scope.reservedNames[e.name] {
+			failures = append(failures, newNumberedFailure(e, "%s %q reuses the reserved name.", e.kind, e.name))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,139 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testFieldNumbersNotReservedProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "fieldNumbersNotReserved", name)
+}
+
+func TestFieldNumbersNotReservedRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto without fields and enum values reusing the reserved numbers and names",
+			inputFilename: "valid.proto",
+		},
+		{
+			name:          "failures for proto with fields and enum values reusing the reserved numbers and names",
+			inputFilename: "invalid.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   109,
+						Line:     7,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Field "b" reuses the reserved number 2.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   109,
+						Line:     7,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Field "b" reuses the reserved name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   144,
+						Line:     9,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Field "c" reuses the reserved number 5.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   164,
+						Line:     11,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Field "old" reuses the reserved name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   182,
+						Line:     12,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Field "d" reuses the reserved number 1000.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   333,
+						Line:     22,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Enum value "BAR_OLD" reuses the reserved number 2.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersNotReservedProtoPath("invalid.proto"),
+						Offset:   333,
+						Line:     22,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_NOT_RESERVED",
+					string(rule.SeverityError),
+					`Enum value "BAR_OLD" reuses the reserved name.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldNumbersNotReservedRule(rule.SeverityError)
+
+			protoPath := testFieldNumbersNotReservedProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
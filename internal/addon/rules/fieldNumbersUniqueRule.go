@@ -0,0 +1,75 @@
+package rules
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// FieldNumbersUniqueRule verifies that the fields of a message, including the ones in its oneofs,
+// have unique numbers. It also verifies that the values of an enum have unique numbers
+// unless the enum sets the allow_alias option.
+type FieldNumbersUniqueRule struct {
+	RuleWithSeverity
+}
+
+// NewFieldNumbersUniqueRule creates a new FieldNumbersUniqueRule.
+func NewFieldNumbersUniqueRule(
+	severity rule.Severity,
+) FieldNumbersUniqueRule {
+	return FieldNumbersUniqueRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r FieldNumbersUniqueRule) ID() string {
+	return "FIELD_NUMBERS_UNIQUE"
+}
+
+// Purpose returns the purpose of this rule.
+func (r FieldNumbersUniqueRule) Purpose() string {
+	return "Verifies that fields and enum values without allow_alias have unique numbers."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r FieldNumbersUniqueRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r FieldNumbersUniqueRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := newNumberedScopeVisitor(r.ID(), string(r.Severity()), checkFieldNumbersUnique)
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+func checkFieldNumbersUnique(scope numberedScope) []numberedFailure {
+	if scope.allowAlias {
+		return nil
+	}
+
+	var failures []numberedFailure
+	first := make(map[int64]numberedElement)
+	for _, e := range scope.elements {
+		if !e.ok {
+			continue
+		}
+		other, ok := first[e.value]
+		if !ok {
+			first[e.value] = e
+			continue
+		}
+		if scope.isEnum {
+			failures = append(failures, newNumberedFailure(
+				e,
+				"%s %q has number %s, which is already used by %q. Set the allow_alias option to define an alias.",
+				e.kind, e.name, e.number, other.name,
+			))
+		} else {
+			failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which is already used by %q.", e.kind, e.name, e.number, other.name))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,106 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testFieldNumbersUniqueProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "fieldNumbersUnique", name)
+}
+
+func TestFieldNumbersUniqueRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto without fields and enum values with the duplicate numbers",
+			inputFilename: "valid.proto",
+		},
+		{
+			name:          "failures for proto with fields and enum values with the duplicate numbers",
+			inputFilename: "invalid.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersUniqueProtoPath("invalid.proto"),
+						Offset:   71,
+						Line:     6,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_UNIQUE",
+					string(rule.SeverityError),
+					`Field "b" has number 1, which is already used by "a".`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersUniqueProtoPath("invalid.proto"),
+						Offset:   89,
+						Line:     7,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_UNIQUE",
+					string(rule.SeverityError),
+					`Field "c" has number 0x1, which is already used by "a".`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersUniqueProtoPath("invalid.proto"),
+						Offset:   140,
+						Line:     10,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_UNIQUE",
+					string(rule.SeverityError),
+					`Field "e" has number 2, which is already used by "d".`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersUniqueProtoPath("invalid.proto"),
+						Offset:   208,
+						Line:     16,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_UNIQUE",
+					string(rule.SeverityError),
+					`Enum value "BAR_UNO" has number 1, which is already used by "BAR_ONE". Set the allow_alias option to define an alias.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldNumbersUniqueRule(rule.SeverityError)
+
+			protoPath := testFieldNumbersUniqueProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
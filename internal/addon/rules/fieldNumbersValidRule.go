@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"math"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// FieldNumbersValidRule verifies that field numbers are between 1 and 536870911, and out of
+// the range 19000 to 19999 reserved for the Protocol Buffers implementation.
+// It also verifies that enum values are in the int32 range.
+type FieldNumbersValidRule struct {
+	RuleWithSeverity
+}
+
+// NewFieldNumbersValidRule creates a new FieldNumbersValidRule.
+func NewFieldNumbersValidRule(
+	severity rule.Severity,
+) FieldNumbersValidRule {
+	return FieldNumbersValidRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r FieldNumbersValidRule) ID() string {
+	return "FIELD_NUMBERS_VALID"
+}
+
+// Purpose returns the purpose of this rule.
+func (r FieldNumbersValidRule) Purpose() string {
+	return "Verifies that field numbers are in the valid range and out of the implementation-reserved range."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r FieldNumbersValidRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r FieldNumbersValidRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := newNumberedScopeVisitor(r.ID(), string(r.Severity()), checkFieldNumbersValid)
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+func checkFieldNumbersValid(scope numberedScope) []numberedFailure {
+	var failures []numberedFailure
+	for _, e := range scope.elements {
+		switch {
+		case e.overflow:
+			switch {
+			case scope.isEnum:
+				failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which is out of the int32 range.", e.kind, e.name, e.number))
+			case strings.HasPrefix(e.number, "-"):
+				failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which must be positive.", e.kind, e.name, e.number))
+			default:
+				failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which is larger than the max %d.", e.kind, e.name, e.number, maxFieldNumber))
+			}
+		case !e.ok:
+		case scope.isEnum:
+			if e.value < math.MinInt32 || math.MaxInt32 < e.value {
+				failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which is out of the int32 range.", e.kind, e.name, e.number))
+			}
+		case e.value < 1:
+			failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which must be positive.", e.kind, e.name, e.number))
+		case maxFieldNumber < e.value:
+			failures = append(failures, newNumberedFailure(e, "%s %q has number %s, which is larger than the max %d.", e.kind, e.name, e.number, maxFieldNumber))
+		case implementationReservedBegin <= e.value && e.value <= implementationReservedEnd:
+			failures = append(failures, newNumberedFailure(
+				e,
+				"%s %q has number %s, which is in the range %d to %d reserved for the Protocol Buffers implementation.",
+				e.kind, e.name, e.number, implementationReservedBegin, implementationReservedEnd,
+			))
+		}
+	}
+	return failures
+}
@@ -0,0 +1,161 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testFieldNumbersValidProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "fieldNumbersValid", name)
+}
+
+func TestFieldNumbersValidRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto without fields and enum values out of the valid ranges",
+			inputFilename: "valid.proto",
+		},
+		{
+			name:          "failures for proto with fields and enum values out of the valid ranges",
+			inputFilename: "invalid.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   36,
+						Line:     4,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "a" has number 0, which must be positive.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   61,
+						Line:     5,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "b" has number 19000, which is in the range 19000 to 19999 reserved for the Protocol Buffers implementation.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   104,
+						Line:     7,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "d" has number 19999, which is in the range 19000 to 19999 reserved for the Protocol Buffers implementation.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   128,
+						Line:     9,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "e" has number 536870912, which is larger than the max 536870911.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   165,
+						Line:     10,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "F" has number 0x20000000, which is larger than the max 536870911.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   201,
+						Line:     11,
+						Column:   5,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Field "g" has number 19500, which is in the range 19000 to 19999 reserved for the Protocol Buffers implementation.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   348,
+						Line:     19,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Enum value "BAR_TOO_LARGE" has number 2147483648, which is out of the int32 range.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   378,
+						Line:     20,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Enum value "BAR_TOO_SMALL" has number -2147483649, which is out of the int32 range.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldNumbersValidProtoPath("invalid.proto"),
+						Offset:   409,
+						Line:     21,
+						Column:   3,
+					},
+					"FIELD_NUMBERS_VALID",
+					string(rule.SeverityError),
+					`Enum value "BAR_OVERFLOW" has number 9223372036854775808, which is out of the int32 range.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldNumbersValidRule(rule.SeverityError)
+
+			protoPath := testFieldNumbersValidProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
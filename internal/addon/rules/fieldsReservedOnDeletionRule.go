@@ -0,0 +1,297 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/linter/breaking"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// defaultFieldsReservedOnDeletionGitRef is the git ref compared by default.
+const defaultFieldsReservedOnDeletionGitRef = "HEAD"
+
+// FieldsReservedOnDeletionRule verifies that the numbers and the names of the deleted fields and enum values
+// are reserved. The deletions are found by comparing the file with its previous version, which is either
+// the one at a git ref or the .proto files in a snapshot directory. The files without the previous version,
+// like the new ones, are left alone.
+type FieldsReservedOnDeletionRule struct {
+	RuleWithSeverity
+	gitRef   string
+	snapshot *snapshotDirLoader
+	fixMode  bool
+}
+
+// NewFieldsReservedOnDeletionRule creates a new FieldsReservedOnDeletionRule.
+// A non-empty snapshotDir takes precedence over gitRef. An empty gitRef means HEAD.
+func NewFieldsReservedOnDeletionRule(
+	severity rule.Severity,
+	gitRef string,
+	snapshotDir string,
+	fixMode bool,
+) FieldsReservedOnDeletionRule {
+	if gitRef == "" {
+		gitRef = defaultFieldsReservedOnDeletionGitRef
+	}
+	var snapshot *snapshotDirLoader
+	if snapshotDir != "" {
+		snapshot = &snapshotDirLoader{dir: snapshotDir}
+	}
+	return FieldsReservedOnDeletionRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		gitRef:           gitRef,
+		snapshot:         snapshot,
+		fixMode:          fixMode,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r FieldsReservedOnDeletionRule) ID() string {
+	return "FIELDS_RESERVED_ON_DELETION"
+}
+
+// Purpose returns the purpose of this rule.
+func (r FieldsReservedOnDeletionRule) Purpose() string {
+	return "Verifies that the numbers and names of the deleted fields and enum values are reserved."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r FieldsReservedOnDeletionRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r FieldsReservedOnDeletionRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	against, ok, err := r.loadAgainst(proto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the previous version for %s: %w", r.ID(), err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	removals := make(map[meta.Position][]breaking.Removal)
+	for _, removal := range breaking.Removals(against, breaking.NewSnapshot([]*parser.Proto{proto})) {
+		removals[removal.ParentPos] = append(removals[removal.ParentPos], removal)
+	}
+
+	base, err := visitor.NewBaseFixableVisitor(r.ID(), r.fixMode, proto, string(r.Severity()))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &fieldsReservedOnDeletionVisitor{
+		BaseFixableVisitor: base,
+		fixMode:            r.fixMode,
+		quoteNames:         proto.Edition == nil,
+		removals:           removals,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+// CacheKey returns the commit of the git ref or the digest of the snapshot directory,
+// since the previous version changes without the file, like when the deletion is committed.
+func (r FieldsReservedOnDeletionRule) CacheKey() (string, error) {
+	if r.snapshot != nil {
+		digest, err := breaking.DigestDir(r.snapshot.dir)
+		if err != nil {
+			return "", err
+		}
+		return "snapshot_dir=" + digest, nil
+	}
+	commit, err := breaking.ResolveGitRef(r.gitRef)
+	if err != nil {
+		return "", err
+	}
+	return "git_ref=" + commit, nil
+}
+
+func (r FieldsReservedOnDeletionRule) loadAgainst(proto *parser.Proto) (*breaking.Snapshot, bool, error) {
+	if r.snapshot != nil {
+		s, err := r.snapshot.load()
+		return s, err == nil, err
+	}
+	return breaking.LoadGitFile(r.gitRef, proto.Meta.Filename, false)
+}
+
+// snapshotDirLoader loads the snapshot directory once and shares it among the files.
+type snapshotDirLoader struct {
+	dir      string
+	once     sync.Once
+	snapshot *breaking.Snapshot
+	err      error
+}
+
+func (l *snapshotDirLoader) load() (*breaking.Snapshot, error) {
+	l.once.Do(func() {
+		l.snapshot, l.err = breaking.LoadDir(l.dir, false)
+	})
+	return l.snapshot, l.err
+}
+
+type fieldsReservedOnDeletionVisitor struct {
+	*visitor.BaseFixableVisitor
+	fixMode bool
+	// quoteNames is false in Editions, which reserves the names as identifiers.
+	quoteNames bool
+	removals   map[meta.Position][]breaking.Removal
+	insertions []reservedInsertion
+}
+
+// reservedInsertion is the reserved statements to insert into the body of a message or an enum.
+type reservedInsertion struct {
+	meta    meta.Meta
+	numbers []int
+	names   []string
+}
+
+// VisitMessage checks the message.
+func (v *fieldsReservedOnDeletionVisitor) VisitMessage(message *parser.Message) bool {
+	v.check(message.Meta)
+	return true
+}
+
+// VisitGroupField checks the group.
+func (v *fieldsReservedOnDeletionVisitor) VisitGroupField(group *parser.GroupField) bool {
+	v.check(group.Meta)
+	return true
+}
+
+// VisitEnum checks the enum.
+func (v *fieldsReservedOnDeletionVisitor) VisitEnum(enum *parser.Enum) bool {
+	v.check(enum.Meta)
+	return false
+}
+
+func (v *fieldsReservedOnDeletionVisitor) check(m meta.Meta) {
+	removals := v.removals[m.Pos]
+	if len(removals) == 0 {
+		return
+	}
+
+	insertion := reservedInsertion{meta: m}
+	for _, r := range removals {
+		kind, parentKind := "Field", "message"
+		if r.IsEnumValue {
+			kind, parentKind = "Enum value", "enum"
+		}
+		var missing string
+		switch {
+		case !r.NumberReserved && !r.NameReserved:
+			missing = "its number and name"
+		case !r.NumberReserved:
+			missing = "its number"
+		default:
+			missing = "its name"
+		}
+		v.AddFailuref(
+			m.Pos,
+			"%s %q with number %d was deleted from %s %q without reserving %s.",
+			kind, r.Name, r.Number, parentKind, r.Parent, missing,
+		)
+
+		if !r.NumberReserved {
+			insertion.numbers = append(insertion.numbers, r.Number)
+		}
+		if !r.NameReserved {
+			insertion.names = append(insertion.names, r.Name)
+		}
+	}
+	v.insertions = append(v.insertions, insertion)
+}
+
+// Finally inserts the reserved statements.
+func (v *fieldsReservedOnDeletionVisitor) Finally(proto *parser.Proto) error {
+	if !v.fixMode {
+		return nil
+	}
+
+	insertions := append([]reservedInsertion(nil), v.insertions...)
+	sort.Slice(insertions, func(i, j int) bool {
+		return insertions[i].meta.Pos.Line > insertions[j].meta.Pos.Line
+	})
+	v.Fixer.ReplaceAll(func(lines []string) []string {
+		for _, insertion := range insertions {
+			lines = v.insert(lines, insertion)
+		}
+		return lines
+	})
+	return v.BaseFixableVisitor.Finally(proto)
+}
+
+// insert adds the reserved statements after the line with the opening brace.
+// The body on the same line as the opening and closing braces is left alone.
+func (v *fieldsReservedOnDeletionVisitor) insert(lines []string, insertion reservedInsertion) []string {
+	first, last := insertion.meta.Pos.Line-1, insertion.meta.LastPos.Line-1
+	if first < 0 || len(lines) <= last {
+		return lines
+	}
+	brace := -1
+	for i := first; i <= last; i++ {
+		if strings.Contains(lines[i], "{") {
+			brace = i
+			break
+		}
+	}
+	if brace < 0 || brace == last {
+		return lines
+	}
+
+	indent := leadingWhitespace(lines[first]) + "  "
+	for i := brace + 1; i < last; i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			indent = leadingWhitespace(lines[i])
+			break
+		}
+	}
+
+	var statements []string
+	if len(insertion.numbers) != 0 {
+		statements = append(statements, indent+"reserved "+reservedNumbers(insertion.numbers)+";")
+	}
+	if len(insertion.names) != 0 {
+		var names []string
+		for _, name := range insertion.names {
+			if v.quoteNames {
+				name = strconv.Quote(name)
+			}
+			names = append(names, name)
+		}
+		statements = append(statements, indent+"reserved "+strings.Join(names, ", ")+";")
+	}
+
+	var inserted []string
+	inserted = append(inserted, lines[:brace+1]...)
+	inserted = append(inserted, statements...)
+	return append(inserted, lines[brace+1:]...)
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// reservedNumbers formats the sorted numbers, joining the consecutive ones into a range like "2 to 4".
+func reservedNumbers(numbers []int) string {
+	var ranges []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(numbers[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d to %d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
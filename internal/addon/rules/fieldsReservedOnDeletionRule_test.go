@@ -0,0 +1,241 @@
+package rules_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/internal/util_test"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testFieldsReservedOnDeletionProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "fieldsReservedOnDeletion", filepath.FromSlash(name))
+}
+
+func TestFieldsReservedOnDeletionRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto reserving the deleted fields and enum values",
+			inputFilename: "current/reserved.proto",
+		},
+		{
+			name:          "no failures for proto without the previous version",
+			inputFilename: "current/new.proto",
+		},
+		{
+			name:          "failures for proto without reserving the deleted fields and enum values",
+			inputFilename: "current/deleted.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   37,
+						Line:     5,
+						Column:   1,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Field "b" with number 2 was deleted from message "foo.v1.Foo" without reserving its number and name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   37,
+						Line:     5,
+						Column:   1,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Field "c" with number 3 was deleted from message "foo.v1.Foo" without reserving its number.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   37,
+						Line:     5,
+						Column:   1,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Field "d" with number 4 was deleted from message "foo.v1.Foo" without reserving its number and name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   37,
+						Line:     5,
+						Column:   1,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Field "f" with number 6 was deleted from message "foo.v1.Foo" without reserving its number and name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   147,
+						Line:     12,
+						Column:   3,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Field "h" with number 2 was deleted from message "foo.v1.Foo.Bar" without reserving its name.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testFieldsReservedOnDeletionProtoPath("current/deleted.proto"),
+						Offset:   203,
+						Line:     19,
+						Column:   1,
+					},
+					"FIELDS_RESERVED_ON_DELETION",
+					string(rule.SeverityError),
+					`Enum value "STATUS_NG" with number 2 was deleted from enum "foo.v1.Status" without reserving its number and name.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldsReservedOnDeletionRule(
+				rule.SeverityError,
+				"",
+				testFieldsReservedOnDeletionProtoPath("previous"),
+				false,
+			)
+
+			protoPath := testFieldsReservedOnDeletionProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
+
+func TestFieldsReservedOnDeletionRule_Apply_fix(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFilename  string
+	}{
+		{
+			name:          "no fix for proto reserving the deleted fields and enum values",
+			inputFilename: "current/reserved.proto",
+			wantFilename:  "current/reserved.proto",
+		},
+		{
+			name:          "fix for proto without reserving the deleted fields and enum values",
+			inputFilename: "current/deleted.proto",
+			wantFilename:  "current/deletedFixed.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewFieldsReservedOnDeletionRule(
+				rule.SeverityError,
+				"",
+				testFieldsReservedOnDeletionProtoPath("previous"),
+				true,
+			)
+
+			input, err := util_test.NewTestData(testFieldsReservedOnDeletionProtoPath(test.inputFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			want, err := util_test.NewTestData(testFieldsReservedOnDeletionProtoPath(test.wantFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			proto, err := file.NewProtoFile(input.FilePath, input.FilePath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			_, err = rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			got, err := input.Data()
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, want.OriginData) {
+				t.Errorf(
+					"got %s(%v), but want %s(%v)",
+					string(got), got,
+					string(want.OriginData), want.OriginData,
+				)
+			}
+
+			err = input.Restore()
+			if err != nil {
+				t.Errorf("got err %v", err)
+			}
+		})
+	}
+}
+
+func TestFieldsReservedOnDeletionRule_CacheKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.proto")
+	writeProto := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := rules.NewFieldsReservedOnDeletionRule(rule.SeverityError, "", dir, false)
+
+	writeProto("syntax = \"proto3\";\nmessage Foo {\n  string a = 1;\n}\n")
+	before, err := r.CacheKey()
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	writeProto("syntax = \"proto3\";\nmessage Foo {\n  reserved 1;\n}\n")
+	after, err := r.CacheKey()
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if before == after {
+		t.Errorf("got the same key %s, but want the key to change with the snapshot", after)
+	}
+
+	r = rules.NewFieldsReservedOnDeletionRule(rule.SeverityError, "not_found_ref", "", false)
+	if _, err := r.CacheKey(); err == nil {
+		t.Errorf("got err nil, but want err for the invalid git ref")
+	}
+}
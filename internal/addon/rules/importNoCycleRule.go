@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/linter/graph"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// ImportNoCycleRule verifies that the imports don't form a cycle.
+// Each cycle is reported once at the import in the file whose path comes first in the cycle.
+type ImportNoCycleRule struct {
+	RuleWithSeverity
+}
+
+// NewImportNoCycleRule creates a new ImportNoCycleRule.
+func NewImportNoCycleRule(
+	severity rule.Severity,
+) ImportNoCycleRule {
+	return ImportNoCycleRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r ImportNoCycleRule) ID() string {
+	return "IMPORT_NO_CYCLE"
+}
+
+// Purpose returns the purpose of this rule.
+func (r ImportNoCycleRule) Purpose() string {
+	return "Verifies that the imports don't form a cycle."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r ImportNoCycleRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r ImportNoCycleRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r ImportNoCycleRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok {
+		return nil, nil
+	}
+
+	v := &importNoCycleVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		resolver:       resolver,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type importNoCycleVisitor struct {
+	*visitor.BaseAddVisitor
+	resolver *importResolver
+}
+
+func (v importNoCycleVisitor) Finally(*parser.Proto) error {
+	for _, c := range graph.CyclesFrom(v.resolver.symbols, v.resolver.file.Path) {
+		v.AddFailuref(c.Import.Pos, `Found an import cycle: %s.`, strings.Join(c.Files, " -> "))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package rules_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testImportNoCycleProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "importNoCycle", filepath.FromSlash(name))
+}
+
+func TestImportNoCycleRule_ApplyWithSymbols(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto out of any cycle",
+			inputFilename: "b/d.proto",
+		},
+		{
+			name:          "no failures for proto in the cycles which are reported in another file",
+			inputFilename: "b/b.proto",
+		},
+		{
+			name:          "failures for proto whose path comes first in the cycles",
+			inputFilename: "a/a.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testImportNoCycleProtoPath("a/a.proto"),
+						Offset:   32,
+						Line:     5,
+						Column:   1,
+					},
+					"IMPORT_NO_CYCLE",
+					string(rule.SeverityError),
+					`Found an import cycle: a/a.proto -> a/c.proto -> b/b.proto -> a/a.proto.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testImportNoCycleProtoPath("a/a.proto"),
+						Offset:   52,
+						Line:     6,
+						Column:   1,
+					},
+					"IMPORT_NO_CYCLE",
+					string(rule.SeverityError),
+					`Found an import cycle: a/a.proto -> b/b.proto -> a/a.proto.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewImportNoCycleRule(
+				rule.SeverityError,
+			)
+
+			protoPath := testImportNoCycleProtoPath(test.inputFilename)
+			f := file.NewProtoFile(protoPath, protoPath)
+			proto, err := f.Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := symbol.Build([]file.ProtoFile{f}, []string{testImportNoCycleProtoPath("")})
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
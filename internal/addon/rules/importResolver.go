@@ -0,0 +1,232 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// importResolver resolves the references of a file to the files defining them
+// and the imports of the file to the files they make visible.
+type importResolver struct {
+	symbols *symbol.Table
+	file    *symbol.File
+}
+
+// newImportResolver creates a new importResolver of the proto.
+// Without the symbols, only the proto itself and the well-known types are known.
+// It returns false when the proto isn't in the table.
+func newImportResolver(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) (*importResolver, bool) {
+	if symbols == nil {
+		symbols = symbol.NewTable(nil, nil).WithProto(proto)
+	}
+	f, ok := symbols.FileOf(proto)
+	if !ok {
+		return nil, false
+	}
+	return &importResolver{
+		symbols: symbols,
+		file:    f,
+	}, true
+}
+
+type resolvedReference struct {
+	symbol.Reference
+	// definitions are the symbols which the reference refers to,
+	// more than one when the files define the same name.
+	definitions []symbol.Symbol
+}
+
+// resolvedReferences returns the references which are resolved to any definition.
+// The well-known types are resolved even when no file in the table imports them.
+func (r *importResolver) resolvedReferences() []resolvedReference {
+	var refs []resolvedReference
+	for _, ref := range r.file.References {
+		s, ok := r.symbols.Resolve(ref.Scope, ref.Name)
+		if !ok {
+			wk, ok := symbol.LookupWellKnown(ref.Name)
+			if ok {
+				refs = append(refs, resolvedReference{
+					Reference:   ref,
+					definitions: []symbol.Symbol{wk},
+				})
+			}
+			continue
+		}
+		refs = append(refs, resolvedReference{
+			Reference:   ref,
+			definitions: r.symbols.Lookup(s.Name),
+		})
+	}
+	return refs
+}
+
+// provided returns the paths of the files which the import makes visible:
+// the imported file and the ones publicly imported by it recursively.
+func (r *importResolver) provided(imp symbol.Import) map[string]bool {
+	provided := map[string]bool{imp.File: true}
+	var visitPublic func(path string)
+	visitPublic = func(path string) {
+		f, ok := r.symbols.File(path)
+		if !ok {
+			return
+		}
+		for _, i := range f.Imports {
+			if i.IsPublic && i.File != "" && !provided[i.File] {
+				provided[i.File] = true
+				visitPublic(i.File)
+			}
+		}
+	}
+	visitPublic(imp.File)
+	return provided
+}
+
+// isKnown reports whether the import is resolved to a file in the table.
+func (r *importResolver) isKnown(imp symbol.Import) bool {
+	if imp.File == "" {
+		return false
+	}
+	_, ok := r.symbols.File(imp.File)
+	return ok
+}
+
+// importPathOf returns the path to import the file defining the symbol with.
+func (r *importResolver) importPathOf(s symbol.Symbol) string {
+	f, ok := r.symbols.File(s.File)
+	if !ok {
+		if symbol.IsWellKnownImport(s.File) {
+			return s.File
+		}
+		return ""
+	}
+	return f.ImportPath
+}
+
+// importInserter inserts the import statements into the lines of a file,
+// keeping the contiguous imports in the order IMPORTS_SORTED enforces.
+type importInserter struct {
+	proto  *parser.Proto
+	sorter *importSorter
+}
+
+func newImportInserter(proto *parser.Proto) *importInserter {
+	sorter := new(importSorter)
+	for _, v := range proto.ProtoBody {
+		if i, ok := v.(*parser.Import); ok {
+			sorter.add(i)
+		}
+	}
+	return &importInserter{
+		proto:  proto,
+		sorter: sorter,
+	}
+}
+
+// insert returns the lines with the imports of the paths inserted.
+func (ins *importInserter) insert(lines []string, paths []string) []string {
+	quote := `"`
+	if 0 < len(ins.sorter.groups) {
+		first := (*ins.sorter.groups[0])[0]
+		quote = first.Location[:1]
+	}
+
+	before := make(map[int][]string)
+	after := make(map[int][]string)
+	for _, path := range paths {
+		location := quote + path + quote
+		statement := "import " + location + ";"
+
+		if len(ins.sorter.groups) == 0 {
+			line := ins.headerLine()
+			if len(after[line]) == 0 && 0 < line {
+				after[line] = append(after[line], "")
+			}
+			after[line] = insertSorted(after[line], statement)
+			continue
+		}
+
+		g := ins.closestGroup(path)
+		k := len(g)
+		for idx, i := range g {
+			if location < i.Location {
+				k = idx
+				break
+			}
+		}
+		if 0 < k {
+			line := g[k-1].Meta.LastPos.Line
+			after[line] = insertSorted(after[line], statement)
+		} else {
+			line := g[0].Meta.Pos.Line
+			before[line] = insertSorted(before[line], statement)
+		}
+	}
+
+	var fixed []string
+	fixed = append(fixed, after[0]...)
+	for idx, line := range lines {
+		fixed = append(fixed, before[idx+1]...)
+		fixed = append(fixed, line)
+		fixed = append(fixed, after[idx+1]...)
+	}
+	return fixed
+}
+
+// headerLine returns the line of the package statement, or the syntax one when it's missing.
+func (ins *importInserter) headerLine() int {
+	for _, v := range ins.proto.ProtoBody {
+		if p, ok := v.(*parser.Package); ok {
+			return p.Meta.LastPos.Line
+		}
+	}
+	if ins.proto.Syntax != nil {
+		return ins.proto.Syntax.Meta.LastPos.Line
+	}
+	if ins.proto.Edition != nil {
+		return ins.proto.Edition.Meta.LastPos.Line
+	}
+	return 0
+}
+
+// closestGroup returns the group sharing the longest prefix with the path.
+func (ins *importInserter) closestGroup(path string) importGroup {
+	closest := *ins.sorter.groups[0]
+	longest := -1
+	for _, g := range ins.sorter.groups {
+		for _, i := range *g {
+			n := commonPrefixLen(unquoteLocation(i.Location), path)
+			if longest < n {
+				closest = *g
+				longest = n
+			}
+		}
+	}
+	return closest
+}
+
+func insertSorted(statements []string, statement string) []string {
+	for idx, s := range statements {
+		if s != "" && statement < s {
+			return append(statements[:idx], append([]string{statement}, statements[idx:]...)...)
+		}
+	}
+	return append(statements, statement)
+}
+
+func commonPrefixLen(a string, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func unquoteLocation(location string) string {
+	return strings.Trim(location, `"'`)
+}
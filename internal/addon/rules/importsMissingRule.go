@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// ImportsMissingRule verifies that all files defining the referenced types and extensions are imported.
+type ImportsMissingRule struct {
+	RuleWithSeverity
+	fixMode bool
+}
+
+// NewImportsMissingRule creates a new ImportsMissingRule.
+func NewImportsMissingRule(
+	severity rule.Severity,
+	fixMode bool,
+) ImportsMissingRule {
+	return ImportsMissingRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		fixMode:          fixMode,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r ImportsMissingRule) ID() string {
+	return "IMPORTS_MISSING"
+}
+
+// Purpose returns the purpose of this rule.
+func (r ImportsMissingRule) Purpose() string {
+	return "Verifies that all files defining the referenced types are imported."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r ImportsMissingRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r ImportsMissingRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r ImportsMissingRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok {
+		return nil, nil
+	}
+
+	base, err := visitor.NewBaseFixableVisitor(r.ID(), r.fixMode, proto, string(r.Severity()))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &importsMissingVisitor{
+		BaseFixableVisitor: base,
+		fixMode:            r.fixMode,
+		resolver:           resolver,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type importsMissingVisitor struct {
+	*visitor.BaseFixableVisitor
+	fixMode  bool
+	resolver *importResolver
+}
+
+func (v importsMissingVisitor) Finally(proto *parser.Proto) error {
+	visible := v.resolver.symbols.VisibleFiles(v.resolver.file)
+
+	missing := make(map[string]bool)
+	for _, ref := range v.resolver.resolvedReferences() {
+		if isVisibleReference(ref, visible) {
+			continue
+		}
+		definition := ref.definitions[0]
+		importPath := v.resolver.importPathOf(definition)
+		if importPath == "" {
+			continue
+		}
+		if ref.IsOption {
+			v.AddFailuref(ref.Pos, `Extension %q is defined in %q, which is not imported.`, definition.Name, importPath)
+		} else {
+			v.AddFailuref(ref.Pos, `Type %q is defined in %q, which is not imported.`, definition.Name, importPath)
+		}
+		missing[importPath] = true
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for path := range missing {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	inserter := newImportInserter(proto)
+	v.Fixer.ReplaceAll(func(lines []string) []string {
+		return inserter.insert(lines, paths)
+	})
+	if !v.fixMode {
+		return nil
+	}
+	return v.BaseFixableVisitor.Finally(proto)
+}
+
+func isVisibleReference(ref resolvedReference, visible map[string]bool) bool {
+	for _, d := range ref.definitions {
+		if visible[d.File] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/internal/util_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testImportsMissingProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "importsMissing", name)
+}
+
+func TestImportsMissingRule_ApplyWithSymbols(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto importing all types",
+			inputFilename: "imported.proto",
+		},
+		{
+			name:          "failures for proto with missing imports",
+			inputFilename: "missing.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsMissingProtoPath("missing.proto"),
+						Offset:   176,
+						Line:     11,
+						Column:   3,
+					},
+					"IMPORTS_MISSING",
+					string(rule.SeverityError),
+					`Type "google.protobuf.Duration" is defined in "google/protobuf/duration.proto", which is not imported.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsMissingProtoPath("missing.proto"),
+						Offset:   217,
+						Line:     12,
+						Column:   3,
+					},
+					"IMPORTS_MISSING",
+					string(rule.SeverityError),
+					`Extension "opts.tag" is defined in "options.proto", which is not imported.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsMissingProtoPath("missing.proto"),
+						Offset:   258,
+						Line:     13,
+						Column:   3,
+					},
+					"IMPORTS_MISSING",
+					string(rule.SeverityError),
+					`Type "zoo.Zoo" is defined in "zoo.proto", which is not imported.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewImportsMissingRule(
+				rule.SeverityError,
+				false,
+			)
+
+			protoPath := testImportsMissingProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := buildDirSymbols(t, testImportsMissingProtoPath(""))
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
+
+func TestImportsMissingRule_ApplyWithSymbols_fix(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFilename  string
+	}{
+		{
+			name:          "no fix for proto importing all types",
+			inputFilename: "imported.proto",
+			wantFilename:  "imported.proto",
+		},
+		{
+			name:          "fix for proto with missing imports",
+			inputFilename: "missing.proto",
+			wantFilename:  "missingFixed.proto",
+		},
+		{
+			name:          "fix for proto without imports",
+			inputFilename: "noImports.proto",
+			wantFilename:  "noImportsFixed.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewImportsMissingRule(
+				rule.SeverityError,
+				true,
+			)
+
+			input, err := util_test.NewTestData(testImportsMissingProtoPath(test.inputFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			want, err := util_test.NewTestData(testImportsMissingProtoPath(test.wantFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			proto, err := file.NewProtoFile(input.FilePath, input.FilePath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := buildDirSymbols(t, testImportsMissingProtoPath(""))
+
+			_, err = rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			got, err := input.Data()
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, want.OriginData) {
+				t.Errorf(
+					"got %s(%v), but want %s(%v)",
+					string(got), got,
+					string(want.OriginData), want.OriginData,
+				)
+			}
+
+			err = input.Restore()
+			if err != nil {
+				t.Errorf("got err %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// ImportsUnusedRule verifies that all imports are used.
+// The public and weak imports and the ones not found in the include paths are left alone.
+type ImportsUnusedRule struct {
+	RuleWithSeverity
+	fixMode bool
+}
+
+// NewImportsUnusedRule creates a new ImportsUnusedRule.
+func NewImportsUnusedRule(
+	severity rule.Severity,
+	fixMode bool,
+) ImportsUnusedRule {
+	return ImportsUnusedRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		fixMode:          fixMode,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r ImportsUnusedRule) ID() string {
+	return "IMPORTS_UNUSED"
+}
+
+// Purpose returns the purpose of this rule.
+func (r ImportsUnusedRule) Purpose() string {
+	return "Verifies that all imports are used."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r ImportsUnusedRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r ImportsUnusedRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r ImportsUnusedRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok {
+		return nil, nil
+	}
+
+	base, err := visitor.NewBaseFixableVisitor(r.ID(), r.fixMode, proto, string(r.Severity()))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &importsUnusedVisitor{
+		BaseFixableVisitor: base,
+		fixMode:            r.fixMode,
+		resolver:           resolver,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type importsUnusedVisitor struct {
+	*visitor.BaseFixableVisitor
+	fixMode  bool
+	resolver *importResolver
+}
+
+func (v importsUnusedVisitor) Finally(proto *parser.Proto) error {
+	used := make(map[string]bool)
+	for _, ref := range v.resolver.resolvedReferences() {
+		for _, d := range ref.definitions {
+			used[d.File] = true
+		}
+	}
+
+	unused := make(map[int]bool)
+	for _, imp := range v.resolver.file.Imports {
+		if imp.IsPublic || imp.IsWeak || !v.resolver.isKnown(imp) {
+			continue
+		}
+		if isUsedImport(v.resolver.provided(imp), used) {
+			continue
+		}
+		v.AddFailuref(imp.Pos, `Import %q is unused.`, imp.Path)
+		unused[imp.Pos.Line] = true
+	}
+
+	v.Fixer.ReplaceAll(func(lines []string) []string {
+		var fixedLines []string
+		for i, line := range lines {
+			if unused[i+1] && strings.HasPrefix(strings.TrimSpace(line), "import") {
+				continue
+			}
+			fixedLines = append(fixedLines, line)
+		}
+		return fixedLines
+	})
+	if !v.fixMode {
+		return nil
+	}
+	return v.BaseFixableVisitor.Finally(proto)
+}
+
+func isUsedImport(provided map[string]bool, used map[string]bool) bool {
+	for path := range provided {
+		if used[path] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/internal/util_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testImportsUnusedProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "importsUnused", name)
+}
+
+func TestImportsUnusedRule_ApplyWithSymbols(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto using all imports",
+			inputFilename: "used.proto",
+		},
+		{
+			name:          "failures for proto with unused imports",
+			inputFilename: "unused.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsUnusedProtoPath("unused.proto"),
+						Offset:   34,
+						Line:     5,
+						Column:   1,
+					},
+					"IMPORTS_UNUSED",
+					string(rule.SeverityError),
+					`Import "google/protobuf/duration.proto" is unused.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsUnusedProtoPath("unused.proto"),
+						Offset:   117,
+						Line:     7,
+						Column:   1,
+					},
+					"IMPORTS_UNUSED",
+					string(rule.SeverityError),
+					`Import "options.proto" is unused.`,
+				),
+				report.Failuref(
+					meta.Position{
+						Filename: testImportsUnusedProtoPath("unused.proto"),
+						Offset:   141,
+						Line:     8,
+						Column:   1,
+					},
+					"IMPORTS_UNUSED",
+					string(rule.SeverityError),
+					`Import "types.proto" is unused.`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewImportsUnusedRule(
+				rule.SeverityError,
+				false,
+			)
+
+			protoPath := testImportsUnusedProtoPath(test.inputFilename)
+			f := file.NewProtoFile(protoPath, protoPath)
+			proto, err := f.Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := symbol.Build([]file.ProtoFile{f}, []string{testImportsUnusedProtoPath("")})
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
+
+func TestImportsUnusedRule_ApplyWithSymbols_fix(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFilename  string
+	}{
+		{
+			name:          "no fix for proto using all imports",
+			inputFilename: "used.proto",
+			wantFilename:  "used.proto",
+		},
+		{
+			name:          "fix for proto with unused imports",
+			inputFilename: "unused.proto",
+			wantFilename:  "unusedFixed.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewImportsUnusedRule(
+				rule.SeverityError,
+				true,
+			)
+
+			input, err := util_test.NewTestData(testImportsUnusedProtoPath(test.inputFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			want, err := util_test.NewTestData(testImportsUnusedProtoPath(test.wantFilename))
+			if err != nil {
+				t.Errorf("got err %v", err)
+				return
+			}
+
+			f := file.NewProtoFile(input.FilePath, input.FilePath)
+			proto, err := f.Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := symbol.Build([]file.ProtoFile{f}, []string{testImportsUnusedProtoPath("")})
+
+			_, err = rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			got, err := input.Data()
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, want.OriginData) {
+				t.Errorf(
+					"got %s(%v), but want %s(%v)",
+					string(got), got,
+					string(want.OriginData), want.OriginData,
+				)
+			}
+
+			err = input.Restore()
+			if err != nil {
+				t.Errorf("got err %v", err)
+			}
+		})
+	}
+}
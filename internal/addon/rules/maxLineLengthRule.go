@@ -2,13 +2,14 @@ package rules
 
 import (
 	"bufio"
-	"os"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/yoheimuta/go-protoparser/v4/parser"
 	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
 
+	"github.com/yoheimuta/protolint/internal/osutil"
+
 	"github.com/yoheimuta/protolint/linter/disablerule"
 	"github.com/yoheimuta/protolint/linter/report"
 	"github.com/yoheimuta/protolint/linter/rule"
@@ -70,7 +71,7 @@ func (r MaxLineLengthRule) Apply(proto *parser.Proto) (
 	err error,
 ) {
 	fileName := proto.Meta.Filename
-	reader, err := os.Open(fileName)
+	reader, err := osutil.Open(fileName)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// PackageDirectoryMatchRule verifies that the package name matches the directory of the file
+// relative to the root. For example, the files of the package foo.bar.v1 must be in foo/bar/v1.
+// The files out of the root are left alone.
+type PackageDirectoryMatchRule struct {
+	RuleWithSeverity
+	root string
+}
+
+// NewPackageDirectoryMatchRule creates a new PackageDirectoryMatchRule.
+// An empty root means the working directory.
+func NewPackageDirectoryMatchRule(
+	severity rule.Severity,
+	root string,
+) PackageDirectoryMatchRule {
+	if root == "" {
+		root = "."
+	}
+	return PackageDirectoryMatchRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		root:             root,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r PackageDirectoryMatchRule) ID() string {
+	return "PACKAGE_DIRECTORY_MATCH"
+}
+
+// Purpose returns the purpose of this rule.
+func (r PackageDirectoryMatchRule) Purpose() string {
+	return "Verifies that the package name matches the directory of the file."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r PackageDirectoryMatchRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r PackageDirectoryMatchRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	root, err := filepath.Abs(r.root)
+	if err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(proto.Meta.Filename)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return nil, nil
+	}
+
+	v := &packageDirectoryMatchVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		dir:            filepath.ToSlash(dir),
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type packageDirectoryMatchVisitor struct {
+	*visitor.BaseAddVisitor
+	dir string
+}
+
+// VisitPackage checks the package.
+func (v *packageDirectoryMatchVisitor) VisitPackage(p *parser.Package) bool {
+	expected := strings.ReplaceAll(p.Name, ".", "/")
+	if expected != v.dir {
+		v.AddFailuref(
+			p.Meta.Pos,
+			"Package %q must be in the directory %q relative to the root, but is in %q.",
+			p.Name, expected, v.dir,
+		)
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package rules_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testPackageDirectoryMatchProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "packageDirectoryMatch", filepath.FromSlash(name))
+}
+
+func TestPackageDirectoryMatchRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputRoot     string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto in the directory of the package",
+			inputRoot:     testPackageDirectoryMatchProtoPath(""),
+			inputFilename: "foo/v1/match.proto",
+		},
+		{
+			name:          "no failures for proto without the package",
+			inputRoot:     testPackageDirectoryMatchProtoPath(""),
+			inputFilename: "noPackage.proto",
+		},
+		{
+			name:          "no failures for proto out of the root",
+			inputRoot:     testPackageDirectoryMatchProtoPath("foo/v1"),
+			inputFilename: "foo/mismatch.proto",
+		},
+		{
+			name:          "a failure for proto out of the directory of the package",
+			inputRoot:     testPackageDirectoryMatchProtoPath(""),
+			inputFilename: "foo/mismatch.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testPackageDirectoryMatchProtoPath("foo/mismatch.proto"),
+						Offset:   20,
+						Line:     3,
+						Column:   1,
+					},
+					"PACKAGE_DIRECTORY_MATCH",
+					string(rule.SeverityError),
+					`Package "foo.v1" must be in the directory "foo/v1" relative to the root, but is in "foo".`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewPackageDirectoryMatchRule(
+				rule.SeverityError,
+				test.inputRoot,
+			)
+
+			protoPath := testPackageDirectoryMatchProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
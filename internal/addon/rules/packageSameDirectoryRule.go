@@ -0,0 +1,156 @@
+package rules
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// PackageSameDirectoryRule verifies that all files in a directory declare the same package.
+// The package declared by the most files in the directory is expected, and the one of the file
+// whose path comes first wins a tie. Only the files in the symbol table are compared.
+type PackageSameDirectoryRule struct {
+	RuleWithSeverity
+}
+
+// NewPackageSameDirectoryRule creates a new PackageSameDirectoryRule.
+func NewPackageSameDirectoryRule(
+	severity rule.Severity,
+) PackageSameDirectoryRule {
+	return PackageSameDirectoryRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r PackageSameDirectoryRule) ID() string {
+	return "PACKAGE_SAME_DIRECTORY"
+}
+
+// Purpose returns the purpose of this rule.
+func (r PackageSameDirectoryRule) Purpose() string {
+	return "Verifies that all files in a directory declare the same package."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r PackageSameDirectoryRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r PackageSameDirectoryRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r PackageSameDirectoryRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(resolver.file.Path)
+	var files []*symbol.File
+	for _, f := range resolver.symbols.Files() {
+		if !f.IsWellKnown && filepath.Dir(f.Path) == dir {
+			files = append(files, f)
+		}
+	}
+
+	v := &packageSameDirectoryVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		expected: mostCommon(files, func(f *symbol.File) (string, bool) {
+			return f.Package, true
+		}),
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type packageSameDirectoryVisitor struct {
+	*visitor.BaseAddVisitor
+	expected   commonValue
+	hasPackage bool
+}
+
+// VisitPackage checks the package.
+func (v *packageSameDirectoryVisitor) VisitPackage(p *parser.Package) bool {
+	v.hasPackage = true
+	if v.expected.files != nil && p.Name != v.expected.value {
+		v.AddFailuref(
+			p.Meta.Pos,
+			"Package %q differs from %q declared by the other files in the same directory like %q.",
+			p.Name, v.expected.value, filepath.Base(v.expected.files[0]),
+		)
+	}
+	return false
+}
+
+// Finally checks the file without the package.
+func (v *packageSameDirectoryVisitor) Finally(proto *parser.Proto) error {
+	if v.hasPackage || v.expected.value == "" || proto.Syntax == nil {
+		return nil
+	}
+	v.AddFailuref(
+		proto.Syntax.Meta.Pos,
+		"Package is missing while the other files in the same directory like %q declare %q.",
+		filepath.Base(v.expected.files[0]), v.expected.value,
+	)
+	return nil
+}
+
+// commonValue is the value shared by the most files.
+type commonValue struct {
+	value string
+	// files are the paths of the files sharing the value, sorted.
+	files []string
+}
+
+// mostCommon returns the value which the most files share. A tie is won by a non-empty value
+// and then by the one of the file whose path comes first. The files whose value isn't ok
+// are left out of the count.
+func mostCommon(
+	files []*symbol.File,
+	valueOf func(f *symbol.File) (string, bool),
+) commonValue {
+	sorted := make([]*symbol.File, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	var order []string
+	paths := make(map[string][]string)
+	for _, f := range sorted {
+		value, ok := valueOf(f)
+		if !ok {
+			continue
+		}
+		if _, ok := paths[value]; !ok {
+			order = append(order, value)
+		}
+		paths[value] = append(paths[value], f.Path)
+	}
+
+	var common commonValue
+	for _, value := range order {
+		n := len(paths[value])
+		if len(common.files) < n || (len(common.files) == n && common.value == "" && value != "") {
+			common = commonValue{
+				value: value,
+				files: paths[value],
+			}
+		}
+	}
+	return common
+}
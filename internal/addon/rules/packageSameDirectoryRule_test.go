@@ -0,0 +1,110 @@
+package rules_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalsymbol "github.com/yoheimuta/protolint/internal/linter/symbol"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// buildDirSymbols builds the table of all files in the directory.
+func buildDirSymbols(t *testing.T, dir string) *symbol.Table {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fs []file.ProtoFile
+	for _, path := range paths {
+		fs = append(fs, file.NewProtoFile(path, path))
+	}
+	return internalsymbol.Build(fs, []string{dir})
+}
+
+func testPackageSameDirectoryProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "packageSameDirectory", name)
+}
+
+func TestPackageSameDirectoryRule_ApplyWithSymbols(t *testing.T) {
+	symbols := buildDirSymbols(t, testPackageSameDirectoryProtoPath(""))
+
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto declaring the common package",
+			inputFilename: "a.proto",
+		},
+		{
+			name:          "a failure for proto declaring another package",
+			inputFilename: "c.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testPackageSameDirectoryProtoPath("c.proto"),
+						Offset:   20,
+						Line:     3,
+						Column:   1,
+					},
+					"PACKAGE_SAME_DIRECTORY",
+					string(rule.SeverityError),
+					`Package "q" differs from "p" declared by the other files in the same directory like "a.proto".`,
+				),
+			},
+		},
+		{
+			name:          "a failure for proto without the package",
+			inputFilename: "d.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testPackageSameDirectoryProtoPath("d.proto"),
+						Offset:   0,
+						Line:     1,
+						Column:   1,
+					},
+					"PACKAGE_SAME_DIRECTORY",
+					string(rule.SeverityError),
+					`Package is missing while the other files in the same directory like "a.proto" declare "p".`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewPackageSameDirectoryRule(
+				rule.SeverityError,
+			)
+
+			protoPath := testPackageSameDirectoryProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"path/filepath"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// defaultPackageSameFileOptions are the file options which decide the package of the generated code.
+var defaultPackageSameFileOptions = []string{
+	"go_package",
+	"java_package",
+	"java_multiple_files",
+	"csharp_namespace",
+	"objc_class_prefix",
+	"php_namespace",
+	"php_metadata_namespace",
+	"ruby_package",
+	"swift_prefix",
+}
+
+// PackageSameFileOptionsRule verifies that all files of a package share the same file options.
+// The value set by the most files of the package is expected, and the one of the file
+// whose path comes first wins a tie. A missing option is one of the values.
+// Only the files in the symbol table are compared.
+type PackageSameFileOptionsRule struct {
+	RuleWithSeverity
+	options []string
+}
+
+// NewPackageSameFileOptionsRule creates a new PackageSameFileOptionsRule.
+// Empty options mean the ones which decide the package of the generated code, like go_package.
+func NewPackageSameFileOptionsRule(
+	severity rule.Severity,
+	options []string,
+) PackageSameFileOptionsRule {
+	if len(options) == 0 {
+		options = defaultPackageSameFileOptions
+	}
+	return PackageSameFileOptionsRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		options:          options,
+	}
+}
+
+// ID returns the ID of this rule.
+func (r PackageSameFileOptionsRule) ID() string {
+	return "PACKAGE_SAME_FILE_OPTIONS"
+}
+
+// Purpose returns the purpose of this rule.
+func (r PackageSameFileOptionsRule) Purpose() string {
+	return "Verifies that all files of a package share the same file options like go_package."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r PackageSameFileOptionsRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r PackageSameFileOptionsRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r PackageSameFileOptionsRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok || resolver.file.Package == "" {
+		return nil, nil
+	}
+
+	var files []*symbol.File
+	for _, f := range resolver.symbols.Files() {
+		if !f.IsWellKnown && f.Package == resolver.file.Package {
+			files = append(files, f)
+		}
+	}
+
+	v := &packageSameFileOptionsVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		file:           resolver.file,
+		files:          files,
+		options:        r.options,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type packageSameFileOptionsVisitor struct {
+	*visitor.BaseAddVisitor
+	file    *symbol.File
+	files   []*symbol.File
+	options []string
+}
+
+// Finally compares the file options with the ones of the other files.
+func (v *packageSameFileOptionsVisitor) Finally(proto *parser.Proto) error {
+	var pkg *parser.Package
+	options := make(map[string]*parser.Option)
+	for _, b := range proto.ProtoBody {
+		switch e := b.(type) {
+		case *parser.Package:
+			pkg = e
+		case *parser.Option:
+			options[e.OptionName] = e
+		}
+	}
+	if pkg == nil {
+		return nil
+	}
+
+	for _, name := range v.options {
+		expected := mostCommon(v.files, func(f *symbol.File) (string, bool) {
+			return f.Options[name], true
+		})
+		value := v.file.Options[name]
+		if value == expected.value {
+			continue
+		}
+		other := filepath.Base(expected.files[0])
+
+		switch {
+		case value == "":
+			v.AddFailuref(
+				pkg.Meta.Pos,
+				"Option %q is missing, but is %s in the other files of package %q like %q.",
+				name, expected.value, pkg.Name, other,
+			)
+		case expected.value == "":
+			v.AddFailuref(
+				options[name].Meta.Pos,
+				"Option %q is set, but not in the other files of package %q like %q.",
+				name, pkg.Name, other,
+			)
+		default:
+			v.AddFailuref(
+				options[name].Meta.Pos,
+				"Option %q is %s, but %s in the other files of package %q like %q.",
+				name, value, expected.value, pkg.Name, other,
+			)
+		}
+	}
+	return nil
+}
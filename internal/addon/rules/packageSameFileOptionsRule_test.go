@@ -0,0 +1,100 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testPackageSameFileOptionsProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "packageSameFileOptions", name)
+}
+
+func TestPackageSameFileOptionsRule_ApplyWithSymbols(t *testing.T) {
+	symbols := buildDirSymbols(t, testPackageSameFileOptionsProtoPath(""))
+
+	goPackageFailure := report.Failuref(
+		meta.Position{
+			Filename: testPackageSameFileOptionsProtoPath("c.proto"),
+			Offset:   32,
+			Line:     5,
+			Column:   1,
+		},
+		"PACKAGE_SAME_FILE_OPTIONS",
+		string(rule.SeverityError),
+		`Option "go_package" is "example.com/p;p", but "example.com/p" in the other files of package "p" like "a.proto".`,
+	)
+	javaPackageFailure := report.Failuref(
+		meta.Position{
+			Filename: testPackageSameFileOptionsProtoPath("c.proto"),
+			Offset:   20,
+			Line:     3,
+			Column:   1,
+		},
+		"PACKAGE_SAME_FILE_OPTIONS",
+		string(rule.SeverityError),
+		`Option "java_package" is missing, but is "com.example.p" in the other files of package "p" like "a.proto".`,
+	)
+
+	tests := []struct {
+		name          string
+		inputOptions  []string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for proto sharing the common options",
+			inputFilename: "a.proto",
+		},
+		{
+			name:          "no failures for proto alone in the package",
+			inputFilename: "q.proto",
+		},
+		{
+			name:          "failures for proto with the different and missing options",
+			inputFilename: "c.proto",
+			wantFailures:  []report.Failure{goPackageFailure, javaPackageFailure},
+		},
+		{
+			name:          "a failure for proto with the different option to compare",
+			inputOptions:  []string{"java_package"},
+			inputFilename: "c.proto",
+			wantFailures:  []report.Failure{javaPackageFailure},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewPackageSameFileOptionsRule(
+				rule.SeverityError,
+				test.inputOptions,
+			)
+
+			protoPath := testPackageSameFileOptionsProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
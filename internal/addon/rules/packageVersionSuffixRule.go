@@ -0,0 +1,103 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// defaultPackageVersionPattern matches the versions like v1, v1p1, v1beta1 and v2alpha.
+const defaultPackageVersionPattern = `^v\d+(p\d+)?((alpha|beta)\d*)?$`
+
+var (
+	stableVersionRegexp   = regexp.MustCompile(`^v\d+(p\d+)?$`)
+	unstableVersionRegexp = regexp.MustCompile(`^v\d+(p\d+)?(alpha|beta)\d*$`)
+)
+
+// lastPackageComponent returns the last component of the package name, which is its version by convention.
+func lastPackageComponent(pkg string) string {
+	return pkg[strings.LastIndex(pkg, ".")+1:]
+}
+
+// isStablePackage reports whether the package ends in a stable version like v1.
+func isStablePackage(pkg string) bool {
+	return stableVersionRegexp.MatchString(lastPackageComponent(pkg))
+}
+
+// isUnstablePackage reports whether the package ends in an alpha or beta version like v1beta1.
+func isUnstablePackage(pkg string) bool {
+	return unstableVersionRegexp.MatchString(lastPackageComponent(pkg))
+}
+
+// PackageVersionSuffixRule verifies that the package name ends in a version component like v1 or v1beta1.
+type PackageVersionSuffixRule struct {
+	RuleWithSeverity
+	pattern *regexp.Regexp
+}
+
+// NewPackageVersionSuffixRule creates a new PackageVersionSuffixRule.
+// An empty pattern means the default one, which matches the versions like v1, v1p1, v1beta1 and v2alpha.
+// It returns an error when the pattern is invalid.
+func NewPackageVersionSuffixRule(
+	severity rule.Severity,
+	pattern string,
+) (PackageVersionSuffixRule, error) {
+	if pattern == "" {
+		pattern = defaultPackageVersionPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return PackageVersionSuffixRule{}, fmt.Errorf("invalid pattern of PACKAGE_VERSION_SUFFIX: %w", err)
+	}
+	return PackageVersionSuffixRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+		pattern:          re,
+	}, nil
+}
+
+// ID returns the ID of this rule.
+func (r PackageVersionSuffixRule) ID() string {
+	return "PACKAGE_VERSION_SUFFIX"
+}
+
+// Purpose returns the purpose of this rule.
+func (r PackageVersionSuffixRule) Purpose() string {
+	return "Verifies that the package name ends in a version component like v1 or v1beta1."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r PackageVersionSuffixRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r PackageVersionSuffixRule) Apply(proto *parser.Proto) ([]report.Failure, error) {
+	v := &packageVersionSuffixVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		pattern:        r.pattern,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type packageVersionSuffixVisitor struct {
+	*visitor.BaseAddVisitor
+	pattern *regexp.Regexp
+}
+
+// VisitPackage checks the package.
+func (v *packageVersionSuffixVisitor) VisitPackage(p *parser.Package) bool {
+	if !v.pattern.MatchString(lastPackageComponent(p.Name)) {
+		v.AddFailuref(
+			p.Meta.Pos,
+			"Package %q must end in a version component matching %q, like v1 or v1beta1.",
+			p.Name, v.pattern.String(),
+		)
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package rules_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testPackageVersionSuffixProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "packageVersionSuffix", name)
+}
+
+func TestPackageVersionSuffixRule_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputPattern  string
+		inputFilename string
+		wantFailures  []report.Failure
+		wantExistErr  bool
+	}{
+		{
+			name:          "no failures for proto with a stable version",
+			inputFilename: "stable.proto",
+		},
+		{
+			name:          "no failures for proto with a beta version",
+			inputFilename: "beta.proto",
+		},
+		{
+			name:          "no failures for proto with a patch and alpha version",
+			inputFilename: "patch.proto",
+		},
+		{
+			name:          "no failures for proto without the package",
+			inputFilename: "noPackage.proto",
+		},
+		{
+			name:          "a failure for proto without a version",
+			inputFilename: "noVersion.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testPackageVersionSuffixProtoPath("noVersion.proto"),
+						Offset:   20,
+						Line:     3,
+						Column:   1,
+					},
+					"PACKAGE_VERSION_SUFFIX",
+					string(rule.SeverityError),
+					`Package "foo.bar" must end in a version component matching "^v\\d+(p\\d+)?((alpha|beta)\\d*)?$", like v1 or v1beta1.`,
+				),
+			},
+		},
+		{
+			name:          "a failure for proto not matching the configured pattern",
+			inputPattern:  `^v\d+$`,
+			inputFilename: "beta.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testPackageVersionSuffixProtoPath("beta.proto"),
+						Offset:   20,
+						Line:     3,
+						Column:   1,
+					},
+					"PACKAGE_VERSION_SUFFIX",
+					string(rule.SeverityError),
+					`Package "foo.bar.v1beta1" must end in a version component matching "^v\\d+$", like v1 or v1beta1.`,
+				),
+			},
+		},
+		{
+			name:          "an error for the invalid pattern when the rule is created",
+			inputPattern:  `^v(\d+$`,
+			inputFilename: "stable.proto",
+			wantExistErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := rules.NewPackageVersionSuffixRule(
+				rule.SeverityError,
+				test.inputPattern,
+			)
+			if test.wantExistErr {
+				if err == nil {
+					t.Errorf("got err nil, but want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+
+			protoPath := testPackageVersionSuffixProtoPath(test.inputFilename)
+			proto, err := file.NewProtoFile(protoPath, protoPath).Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+
+			got, err := rule.Apply(proto)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
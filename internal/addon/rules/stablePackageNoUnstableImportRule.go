@@ -0,0 +1,90 @@
+package rules
+
+import (
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/visitor"
+)
+
+// StablePackageNoUnstableImportRule verifies that a package with a stable version like v1
+// doesn't import a package with an alpha or beta version like v1beta1.
+// The imports not found in the include paths are left alone.
+type StablePackageNoUnstableImportRule struct {
+	RuleWithSeverity
+}
+
+// NewStablePackageNoUnstableImportRule creates a new StablePackageNoUnstableImportRule.
+func NewStablePackageNoUnstableImportRule(
+	severity rule.Severity,
+) StablePackageNoUnstableImportRule {
+	return StablePackageNoUnstableImportRule{
+		RuleWithSeverity: RuleWithSeverity{severity: severity},
+	}
+}
+
+// ID returns the ID of this rule.
+func (r StablePackageNoUnstableImportRule) ID() string {
+	return "STABLE_PACKAGE_NO_UNSTABLE_IMPORT"
+}
+
+// Purpose returns the purpose of this rule.
+func (r StablePackageNoUnstableImportRule) Purpose() string {
+	return "Verifies that a stable-version package doesn't import an alpha or beta package."
+}
+
+// IsOfficial decides whether or not this rule belongs to the official guide.
+func (r StablePackageNoUnstableImportRule) IsOfficial() bool {
+	return false
+}
+
+// Apply applies the rule to the proto.
+func (r StablePackageNoUnstableImportRule) Apply(
+	proto *parser.Proto,
+) ([]report.Failure, error) {
+	return r.ApplyWithSymbols(proto, nil)
+}
+
+// ApplyWithSymbols applies the rule to the proto with the symbol table.
+func (r StablePackageNoUnstableImportRule) ApplyWithSymbols(
+	proto *parser.Proto,
+	symbols *symbol.Table,
+) ([]report.Failure, error) {
+	resolver, ok := newImportResolver(proto, symbols)
+	if !ok {
+		return nil, nil
+	}
+
+	v := &stablePackageNoUnstableImportVisitor{
+		BaseAddVisitor: visitor.NewBaseAddVisitor(r.ID(), string(r.Severity())),
+		resolver:       resolver,
+	}
+	return visitor.RunVisitor(v, proto, r.ID())
+}
+
+type stablePackageNoUnstableImportVisitor struct {
+	*visitor.BaseAddVisitor
+	resolver *importResolver
+}
+
+// Finally checks the packages of the imported files.
+func (v *stablePackageNoUnstableImportVisitor) Finally(*parser.Proto) error {
+	pkg := v.resolver.file.Package
+	if !isStablePackage(pkg) {
+		return nil
+	}
+	for _, imp := range v.resolver.file.Imports {
+		imported, ok := v.resolver.symbols.File(imp.File)
+		if !ok || !isUnstablePackage(imported.Package) {
+			continue
+		}
+		v.AddFailuref(
+			imp.Pos,
+			"Stable package %q must not import %q of unstable package %q.",
+			pkg, imp.Path, imported.Package,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package rules_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/setting_test"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func testStablePackageNoUnstableImportProtoPath(name string) string {
+	return setting_test.TestDataPath("rules", "stablePackageNoUnstableImport", filepath.FromSlash(name))
+}
+
+func TestStablePackageNoUnstableImportRule_ApplyWithSymbols(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputFilename string
+		wantFailures  []report.Failure
+	}{
+		{
+			name:          "no failures for unstable proto importing unstable one",
+			inputFilename: "foo/v1beta1/unstable.proto",
+		},
+		{
+			name:          "a failure for stable proto importing unstable one",
+			inputFilename: "foo/v1/stable.proto",
+			wantFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: testStablePackageNoUnstableImportProtoPath("foo/v1/stable.proto"),
+						Offset:   64,
+						Line:     6,
+						Column:   1,
+					},
+					"STABLE_PACKAGE_NO_UNSTABLE_IMPORT",
+					string(rule.SeverityError),
+					`Stable package "foo.v1" must not import "foo/v1beta1/beta.proto" of unstable package "foo.v1beta1".`,
+				),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rule := rules.NewStablePackageNoUnstableImportRule(
+				rule.SeverityError,
+			)
+
+			protoPath := testStablePackageNoUnstableImportProtoPath(test.inputFilename)
+			f := file.NewProtoFile(protoPath, protoPath)
+			proto, err := f.Parse(false)
+			if err != nil {
+				t.Errorf("%v", err)
+				return
+			}
+			symbols := symbol.Build([]file.ProtoFile{f}, []string{testStablePackageNoUnstableImportProtoPath("")})
+
+			got, err := rule.ApplyWithSymbols(proto, symbols)
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
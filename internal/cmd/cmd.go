@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"strings"
 
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds/breaking"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds/config"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds/graph"
 	"github.com/yoheimuta/protolint/internal/cmd/subcmds/lint"
 	"github.com/yoheimuta/protolint/internal/cmd/subcmds/list"
 	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/lsp"
 	"github.com/yoheimuta/protolint/mcp"
 )
 
@@ -23,6 +30,10 @@ Usage:
 The commands are:
 	lint     lint protocol buffer files
 	list     list all current lint rules being used
+	breaking detect breaking changes against a git ref or a directory
+	graph    print the import graph of protocol buffer files
+	config   print the config whose extends are resolved, with "config print"
+	lsp      start as an LSP server
 	version  print protolint version
 
 The flags are:
@@ -36,6 +47,10 @@ const (
 	subCmdLint    = "lint"
 	subCmdList    = "list"
 	subCmdVersion = "version"
+	subCmdLSP     = "lsp"
+	subCmdBreak   = "breaking"
+	subCmdGraph   = "graph"
+	subCmdConfig  = "config"
 	mcpFlag       = "--mcp"
 )
 
@@ -85,6 +100,14 @@ func doSub(
 		return doList(args[1:], stdout, stderr)
 	case subCmdVersion:
 		return doVersion(stdout)
+	case subCmdLSP:
+		return doLSP(args[1:], stdout, stderr)
+	case subCmdBreak:
+		return doBreaking(args[1:], stdout, stderr)
+	case subCmdGraph:
+		return doGraph(args[1:], stdout, stderr)
+	case subCmdConfig:
+		return doConfig(args[1:], stdout, stderr)
 	default:
 		return doLint(args, stdout, stderr)
 	}
@@ -106,11 +129,12 @@ func doLint(
 		_, _ = fmt.Fprint(stderr, err)
 		return osutil.ExitInternalFailure
 	}
-	if len(flags.Args()) < 1 {
+	if len(flags.Args()) < 1 && !flags.Stdin {
 		_, _ = fmt.Fprintln(stderr, "protolint lint requires at least one argument. See Usage.")
 		_, _ = fmt.Fprint(stderr, help)
 		return osutil.ExitInternalFailure
 	}
+	flags.Version = version + "(" + revision + ")"
 
 	subCmd, err := lint.NewCmdLint(
 		flags,
@@ -126,6 +150,11 @@ func doLint(
 		}
 		return osutil.ExitInternalFailure
 	}
+	if flags.Watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		return subCmd.Watch(ctx, flags.WatchInterval)
+	}
 	return subCmd.Run()
 }
 
@@ -161,3 +190,78 @@ func doMCP(
 	server := mcp.NewServer(stdout, stderr)
 	return server.Run()
 }
+
+func doLSP(
+	args []string,
+	stdout io.Writer,
+	stderr io.Writer,
+) osutil.ExitCode {
+	flags, err := lsp.NewFlags(args)
+	if err != nil {
+		_, _ = fmt.Fprint(stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	flags.Version = version + "(" + revision + ")"
+	server := lsp.NewServer(flags, os.Stdin, stdout, stderr)
+	return server.Run()
+}
+
+func doBreaking(
+	args []string,
+	stdout io.Writer,
+	stderr io.Writer,
+) osutil.ExitCode {
+	flags, err := breaking.NewFlags(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	subCmd := breaking.NewCmdBreaking(
+		flags,
+		stdout,
+		stderr,
+	)
+	return subCmd.Run()
+}
+
+func doGraph(
+	args []string,
+	stdout io.Writer,
+	stderr io.Writer,
+) osutil.ExitCode {
+	flags, err := graph.NewFlags(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	subCmd := graph.NewCmdGraph(
+		flags,
+		stdout,
+		stderr,
+	)
+	return subCmd.Run()
+}
+
+func doConfig(
+	args []string,
+	stdout io.Writer,
+	stderr io.Writer,
+) osutil.ExitCode {
+	if len(args) < 1 || args[0] != "print" {
+		_, _ = fmt.Fprintln(stderr, "protolint config requires the print command. See Usage.")
+		_, _ = fmt.Fprint(stderr, help)
+		return osutil.ExitInternalFailure
+	}
+
+	flags, err := config.NewFlags(args[1:])
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	subCmd := config.NewCmdConfigPrint(
+		flags,
+		stdout,
+		stderr,
+	)
+	return subCmd.Run()
+}
@@ -0,0 +1,80 @@
+package breaking
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yoheimuta/protolint/internal/linter/breaking"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/linter/report"
+	"github.com/yoheimuta/protolint/internal/osutil"
+)
+
+// CmdBreaking is a command to detect the breaking changes.
+type CmdBreaking struct {
+	stdout io.Writer
+	stderr io.Writer
+	flags  Flags
+}
+
+// NewCmdBreaking creates a new CmdBreaking.
+func NewCmdBreaking(
+	flags Flags,
+	stdout io.Writer,
+	stderr io.Writer,
+) *CmdBreaking {
+	return &CmdBreaking{
+		flags:  flags,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Run compares the proto files with the previous version and reports the breaking changes.
+func (c *CmdBreaking) Run() osutil.ExitCode {
+	current, against, err := c.load()
+	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+
+	failures := breaking.Check(against, current, c.flags.Category)
+
+	output := report.WriteToConsole
+	if len(c.flags.OutputFilePath) != 0 {
+		output = c.flags.OutputFilePath
+	}
+	err = report.NewReporterWithOutput(c.flags.Reporter, output).ReportWithFallback(c.stderr, failures)
+	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+
+	if 0 < len(failures) {
+		return osutil.ExitLintFailure
+	}
+	return osutil.ExitSuccess
+}
+
+func (c *CmdBreaking) load() (*breaking.Snapshot, *breaking.Snapshot, error) {
+	protoSet, err := file.NewProtoSet(c.flags.FilePaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := breaking.LoadFiles(protoSet.ProtoFiles(), c.flags.Verbose)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var against *breaking.Snapshot
+	if info, statErr := os.Stat(c.flags.Against); statErr == nil && info.IsDir() {
+		against, err = breaking.LoadDir(c.flags.Against, c.flags.Verbose)
+	} else {
+		against, err = breaking.LoadGitRef(c.flags.Against, c.flags.FilePaths, c.flags.Verbose)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, against, nil
+}
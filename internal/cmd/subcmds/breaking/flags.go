@@ -0,0 +1,90 @@
+package breaking
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds/lint"
+	"github.com/yoheimuta/protolint/internal/linter/breaking"
+	"github.com/yoheimuta/protolint/internal/linter/report"
+	"github.com/yoheimuta/protolint/internal/linter/report/reporters"
+)
+
+// Flags represents a set of breaking flag parameters.
+type Flags struct {
+	*flag.FlagSet
+
+	FilePaths      []string
+	Against        string
+	Category       breaking.Category
+	Reporter       report.Reporter
+	OutputFilePath string
+	Verbose        bool
+}
+
+// NewFlags creates a new Flags.
+func NewFlags(
+	args []string,
+) (Flags, error) {
+	f := Flags{
+		FlagSet:  flag.NewFlagSet("breaking", flag.ExitOnError),
+		Reporter: reporters.PlainReporter{},
+	}
+	var reporter string
+	var category string
+
+	f.StringVar(
+		&f.Against,
+		"against",
+		"",
+		"git ref or path/to/the_directory of the previous version to compare with",
+	)
+	f.StringVar(
+		&category,
+		"category",
+		breaking.Source.String(),
+		`compatibility to check. "wire" checks the binary encoding, "json" adds the JSON encoding, and "source"(default) adds the generated code`,
+	)
+	f.StringVar(
+		&reporter,
+		"reporter",
+		"",
+		`formatter to output results in the specific format. Available reporters are "plain"(default), "junit", "json", "sarif", and "unix".`,
+	)
+	f.StringVar(
+		&f.OutputFilePath,
+		"output_file",
+		"",
+		"path/to/output.txt",
+	)
+	f.BoolVar(
+		&f.Verbose,
+		"v",
+		false,
+		"verbose output that includes parsing process details",
+	)
+
+	_ = f.Parse(args)
+
+	if len(f.Against) == 0 {
+		return Flags{}, fmt.Errorf("protolint breaking requires -against")
+	}
+	c, err := breaking.ParseCategory(category)
+	if err != nil {
+		return Flags{}, err
+	}
+	f.Category = c
+	if len(reporter) != 0 {
+		r, err := lint.GetReporter(reporter)
+		if err != nil {
+			return Flags{}, err
+		}
+		f.Reporter = r
+	}
+
+	f.FilePaths = f.Args()
+	if len(f.FilePaths) == 0 {
+		f.FilePaths = []string{"."}
+	}
+	return f, nil
+}
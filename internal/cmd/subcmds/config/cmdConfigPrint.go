@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/osutil"
+)
+
+// CmdConfigPrint is a command to print the resolved config.
+type CmdConfigPrint struct {
+	stdout io.Writer
+	stderr io.Writer
+	flags  Flags
+}
+
+// NewCmdConfigPrint creates a new CmdConfigPrint.
+func NewCmdConfigPrint(
+	flags Flags,
+	stdout io.Writer,
+	stderr io.Writer,
+) *CmdConfigPrint {
+	return &CmdConfigPrint{
+		flags:  flags,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Run prints the config whose extends are resolved, which protolint lints with.
+func (c *CmdConfigPrint) Run() osutil.ExitCode {
+	err := c.run()
+	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	return osutil.ExitSuccess
+}
+
+func (c *CmdConfigPrint) run() error {
+	// Load the config first to report the invalid one.
+	externalConfig, err := config.GetExternalConfig(c.flags.ConfigPath, c.flags.ConfigDirPath)
+	if err != nil {
+		return err
+	}
+	if externalConfig == nil {
+		return fmt.Errorf("not found any config file")
+	}
+
+	lint, err := config.ResolveLint(externalConfig.SourcePath)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(map[string]interface{}{"lint": lint})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.stdout, "# Resolved from %s\n%s", externalConfig.SourcePath, data)
+	return err
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"flag"
+)
+
+// Flags represents a set of config flag parameters.
+type Flags struct {
+	*flag.FlagSet
+
+	ConfigPath    string
+	ConfigDirPath string
+}
+
+// NewFlags creates a new Flags.
+func NewFlags(
+	args []string,
+) (Flags, error) {
+	f := Flags{
+		FlagSet: flag.NewFlagSet("config print", flag.ExitOnError),
+	}
+
+	f.StringVar(
+		&f.ConfigPath,
+		"config_path",
+		"",
+		"path/to/protolint.yaml to print. Note that if both are set, config_dir_path is ignored.",
+	)
+	f.StringVar(
+		&f.ConfigDirPath,
+		"config_dir_path",
+		"",
+		"path/to/the_directory_including_protolint.yaml to print",
+	)
+
+	_ = f.Parse(args)
+	return f, nil
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/linter/graph"
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/osutil"
+)
+
+// CmdGraph is a command to print the import graph.
+type CmdGraph struct {
+	stdout io.Writer
+	stderr io.Writer
+	flags  Flags
+}
+
+// NewCmdGraph creates a new CmdGraph.
+func NewCmdGraph(
+	flags Flags,
+	stdout io.Writer,
+	stderr io.Writer,
+) *CmdGraph {
+	return &CmdGraph{
+		flags:  flags,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Run prints the import graph of the proto files.
+func (c *CmdGraph) Run() osutil.ExitCode {
+	protoSet, err := file.NewProtoSet(c.flags.FilePaths)
+	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	fs := protoSet.ProtoFiles()
+
+	var paths []string
+	for _, f := range fs {
+		paths = append(paths, f.Path())
+	}
+	g := graph.New(symbol.Build(fs, c.flags.IncludePaths), paths, c.flags.Level)
+
+	output := c.stdout
+	if len(c.flags.OutputFilePath) != 0 {
+		out, err := os.Create(c.flags.OutputFilePath)
+		if err != nil {
+			_, _ = fmt.Fprintln(c.stderr, err)
+			return osutil.ExitInternalFailure
+		}
+		defer func() {
+			_ = out.Close()
+		}()
+		output = out
+	}
+	if err := c.flags.Writer(output, g); err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	return osutil.ExitSuccess
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"flag"
+
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter/graph"
+)
+
+// Flags represents a set of graph flag parameters.
+type Flags struct {
+	*flag.FlagSet
+
+	FilePaths      []string
+	Level          graph.Level
+	Writer         graph.Writer
+	IncludePaths   []string
+	OutputFilePath string
+}
+
+// NewFlags creates a new Flags.
+func NewFlags(
+	args []string,
+) (Flags, error) {
+	f := Flags{
+		FlagSet: flag.NewFlagSet("graph", flag.ExitOnError),
+	}
+	var level string
+	var format string
+	var ipf subcmds.IncludePathFlag
+
+	f.StringVar(
+		&level,
+		"level",
+		"file",
+		`granularity of the nodes. Available levels are "file"(default) and "package"`,
+	)
+	f.StringVar(
+		&format,
+		"format",
+		"dot",
+		`format to output the graph in. Available formats are "dot"(default) and "json"`,
+	)
+	f.Var(
+		&ipf,
+		"I",
+		"directory to search the imports in, which can be given more than once. Without it, the working directory and the ancestor directories of each file are searched",
+	)
+	f.Var(
+		&ipf,
+		"proto_path",
+		"same as -I",
+	)
+	f.StringVar(
+		&f.OutputFilePath,
+		"output_file",
+		"",
+		"path/to/output.dot",
+	)
+
+	_ = f.Parse(args)
+
+	l, err := graph.ParseLevel(level)
+	if err != nil {
+		return Flags{}, err
+	}
+	f.Level = l
+	w, err := graph.GetWriter(format)
+	if err != nil {
+		return Flags{}, err
+	}
+	f.Writer = w
+	f.IncludePaths = ipf
+
+	f.FilePaths = f.Args()
+	if len(f.FilePaths) == 0 {
+		f.FilePaths = []string{"."}
+	}
+	return f, nil
+}
@@ -0,0 +1,32 @@
+package subcmds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IncludePathFlag is the directories to search the imports in, like the -I option of protoc.
+// It can be given more than once, and each value can list the directories separated by the OS path list separator.
+type IncludePathFlag []string
+
+// String implements flag.Value.
+func (f *IncludePathFlag) String() string {
+	return strings.Join(*f, string(filepath.ListSeparator))
+}
+
+// Set implements flag.Value.
+func (f *IncludePathFlag) Set(value string) error {
+	for _, path := range filepath.SplitList(value) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("include path %s is not a directory", path)
+		}
+		*f = append(*f, path)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/yoheimuta/protolint/internal/linter/baseline"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/linter/report"
+)
+
+// writeBaseline records the failures to the file at -write-baseline.
+func (c *CmdLint) writeBaseline(failures []report.Failure) error {
+	err := baseline.New(failures).Write(c.flags.WriteBaselinePath)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stderr, "protolint wrote %d failures to the baseline at %s\n", len(failures), c.flags.WriteBaselinePath)
+	return nil
+}
+
+// applyBaseline drops the failures in the baseline given by -baseline.
+// It warns about the stale entries of the linted files so that they can be removed.
+func (c *CmdLint) applyBaseline(
+	failures []report.Failure,
+	protoFiles []file.ProtoFile,
+) []report.Failure {
+	if c.baseline == nil {
+		return failures
+	}
+
+	var lintedFiles []string
+	for _, f := range protoFiles {
+		lintedFiles = append(lintedFiles, f.DisplayPath())
+	}
+	fs, stale := c.baseline.Filter(failures, lintedFiles)
+	for _, e := range stale {
+		_, _ = fmt.Fprintf(
+			c.stderr,
+			"[%s] the baseline entry of %s is stale because %d failure(s) are no longer found: %s\n",
+			e.File, e.RuleID, e.Count, e.Message,
+		)
+	}
+	return fs
+}
@@ -4,18 +4,28 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"path/filepath"
+	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-plugin"
 
-	"github.com/yoheimuta/go-protoparser/v4/parser"
-
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/diffutil"
+	"github.com/yoheimuta/protolint/internal/linter/baseline"
+	"github.com/yoheimuta/protolint/internal/linter/cache"
 	"github.com/yoheimuta/protolint/internal/linter/config"
 
 	"github.com/yoheimuta/protolint/internal/linter"
 	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalrule "github.com/yoheimuta/protolint/internal/linter/rule"
+	internalsymbol "github.com/yoheimuta/protolint/internal/linter/symbol"
 	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/autodisable"
 	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
 )
 
 // CmdLint is a lint command.
@@ -26,6 +36,9 @@ type CmdLint struct {
 	protoFiles []file.ProtoFile
 	config     CmdLintConfig
 	output     io.Writer
+	stdin      bool
+	flags      Flags
+	baseline   *baseline.Baseline
 }
 
 // NewCmdLint creates a new CmdLint.
@@ -34,9 +47,35 @@ func NewCmdLint(
 	stdout io.Writer,
 	stderr io.Writer,
 ) (*CmdLint, error) {
-	protoSet, err := file.NewProtoSet(flags.FilePaths)
-	if err != nil {
-		return nil, err
+	if flags.Stdin && flags.Watch {
+		return nil, fmt.Errorf("-watch can't be specified with -stdin")
+	}
+	if 0 < len(flags.WriteBaselinePath) && flags.Watch {
+		return nil, fmt.Errorf("-write-baseline can't be specified with -watch")
+	}
+
+	var knownFailures *baseline.Baseline
+	if 0 < len(flags.BaselinePath) && len(flags.WriteBaselinePath) == 0 {
+		b, err := baseline.Load(flags.BaselinePath)
+		if err != nil {
+			return nil, err
+		}
+		knownFailures = b
+	}
+
+	var protoFiles []file.ProtoFile
+	if flags.Stdin {
+		f, err := newStdinProtoFile(flags)
+		if err != nil {
+			return nil, err
+		}
+		protoFiles = []file.ProtoFile{f}
+	} else {
+		protoSet, err := file.NewProtoSet(flags.FilePaths)
+		if err != nil {
+			return nil, err
+		}
+		protoFiles = protoSet.ProtoFiles()
 	}
 
 	externalConfig, err := config.GetExternalConfig(flags.ConfigPath, flags.ConfigDirPath)
@@ -64,85 +103,342 @@ func NewCmdLint(
 		l:          linter.NewLinter(),
 		stdout:     stdout,
 		stderr:     stderr,
-		protoFiles: protoSet.ProtoFiles(),
+		protoFiles: protoFiles,
 		config:     lintConfig,
 		output:     output,
+		stdin:      flags.Stdin,
+		flags:      flags,
+		baseline:   knownFailures,
 	}, nil
 }
 
+// newStdinProtoFile reads stdin and makes it the in-memory content of the file at -stdin_filename.
+func newStdinProtoFile(flags Flags) (file.ProtoFile, error) {
+	if 0 < len(flags.FilePaths) {
+		return file.ProtoFile{}, fmt.Errorf("file paths %v can't be specified with -stdin", flags.FilePaths)
+	}
+
+	f, err := file.NewProtoFileFromPath(flags.StdinFilename)
+	if err != nil {
+		return file.ProtoFile{}, err
+	}
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return file.ProtoFile{}, err
+	}
+	osutil.SetOverlay(f.Path(), content)
+	return f, nil
+}
+
 // Run lints to proto files.
 func (c *CmdLint) Run() osutil.ExitCode {
 	defer plugin.CleanupClients()
+	defer subcmds.ClosePlugins(c.flags.Plugins)
 
-	failures, err := c.run()
+	failures, diff, err := c.run()
 	if err != nil {
 		_, _ = fmt.Fprintln(c.stderr, err)
 		return osutil.ExitInternalFailure
 	}
 
+	_, err = c.stdout.Write(diff)
+	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+
+	if c.stdin {
+		err = c.writeStdinResult()
+		if err != nil {
+			_, _ = fmt.Fprintln(c.stderr, err)
+			return osutil.ExitInternalFailure
+		}
+	}
+
+	if 0 < len(c.flags.WriteBaselinePath) {
+		err = c.writeBaseline(failures)
+		if err != nil {
+			_, _ = fmt.Fprintln(c.stderr, err)
+			return osutil.ExitInternalFailure
+		}
+		return osutil.ExitSuccess
+	}
+	failures = c.applyBaseline(failures, c.protoFiles)
+
 	err = c.config.reporters.ReportWithFallback(c.output, failures)
 	if err != nil {
 		_, _ = fmt.Fprintln(c.stderr, err)
 		return osutil.ExitInternalFailure
 	}
 
-	if 0 < len(failures) {
+	if 0 < len(failures) || 0 < len(diff) {
 		return osutil.ExitLintFailure
 	}
 
 	return osutil.ExitSuccess
 }
 
-func (c *CmdLint) run() ([]report.Failure, error) {
+// writeStdinResult writes the content to stdout when it's possibly modified by -fix or -auto_disable.
+func (c *CmdLint) writeStdinResult() error {
+	content, _ := osutil.RemoveOverlay(c.protoFiles[0].Path())
+	if c.config.diffMode || (!c.config.fixMode && c.config.autoDisableType == autodisable.Noop) {
+		return nil
+	}
+	_, err := c.stdout.Write(content)
+	return err
+}
+
+func (c *CmdLint) run() ([]report.Failure, []byte, error) {
+	// Build the rules once to share them among all files.
+	// This also keeps plugins from receiving concurrent ListRules calls.
+	rules, err := c.newRuleSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rules.close()
+
+	c.l = c.l.WithSymbols(c.buildSymbols(c.protoFiles, rules))
+	lintCache, err := c.config.LoadCache(rules.all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := c.lintFiles(c.protoFiles, rules, lintCache, true)
+
 	var allFailures []report.Failure
+	var diff []byte
+	for _, r := range results {
+		if r.err != nil {
+			return nil, nil, r.err
+		}
+		allFailures = append(allFailures, r.failures...)
+		diff = append(diff, r.diff...)
+	}
 
-	for _, f := range c.protoFiles {
-		failures, err := c.runOneFile(f)
-		if err != nil {
-			return nil, err
+	if lintCache != nil {
+		if err := lintCache.Save(); err != nil {
+			return nil, nil, err
 		}
-		allFailures = append(allFailures, failures...)
 	}
-	return allFailures, nil
+	return allFailures, diff, nil
 }
 
-// ParseError represents the error returned through a parsing exception.
-type ParseError struct {
-	Message string
+// ruleSet is the rules built for a run.
+type ruleSet struct {
+	all internalrule.Rules
+	// fixing is only built to suggest fixes.
+	fixing internalrule.Rules
+	// wasmPlugins are the plugins in the config which some of the rules run on.
+	wasmPlugins []shared.RuleSet
 }
 
-func (p ParseError) Error() string {
-	return p.Message
+// close releases the plugins built for the rules.
+func (r ruleSet) close() {
+	subcmds.ClosePlugins(r.wasmPlugins)
 }
 
-func (c *CmdLint) runOneFile(
+func (c *CmdLint) newRuleSet() (ruleSet, error) {
+	wasmPlugins, err := c.config.BuildWasmPlugins()
+	if err != nil {
+		return ruleSet{}, err
+	}
+	allRules, err := c.config.AllRules(wasmPlugins)
+	if err != nil {
+		subcmds.ClosePlugins(wasmPlugins)
+		return ruleSet{}, err
+	}
+	fixingRules, err := c.config.FixingRules()
+	if err != nil {
+		subcmds.ClosePlugins(wasmPlugins)
+		return ruleSet{}, err
+	}
+	return ruleSet{all: allRules, fixing: fixingRules, wasmPlugins: wasmPlugins}, nil
+}
+
+// buildSymbols builds the symbol table of the files if any rule needs it.
+// It returns nil otherwise.
+func (c *CmdLint) buildSymbols(
+	fs []file.ProtoFile,
+	rules ruleSet,
+) *symbol.Table {
+	var hasApplies []rule.HasApply
+	for _, r := range rules.all {
+		hasApplies = append(hasApplies, r)
+	}
+	if !linter.NeedsSymbols(hasApplies) {
+		return nil
+	}
+	return internalsymbol.Build(fs, c.flags.IncludePaths)
+}
+
+// lintFiles lints the files concurrently and returns the results in the order of the files.
+// With stopOnError, the files after the first error may be left unlinted.
+func (c *CmdLint) lintFiles(
+	fs []file.ProtoFile,
+	rules ruleSet,
+	lintCache *cache.Cache,
+	stopOnError bool,
+) []lintResult {
+	results := make([]lintResult, len(fs))
+	groups := groupByPath(fs)
+
+	jobs := c.config.jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	if len(groups) < jobs {
+		jobs = len(groups)
+	}
+
+	queue := make(chan []int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, idx := range group {
+					if failed.Load() {
+						break
+					}
+					results[idx] = c.lintOneFile(fs[idx], rules, lintCache)
+					if results[idx].err != nil && stopOnError {
+						failed.Store(true)
+					}
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		if failed.Load() {
+			break
+		}
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+type lintResult struct {
+	failures []report.Failure
+	diff     []byte
+	err      error
+}
+
+// groupByPath groups the indexes of the files by their paths.
+// The same file given more than once must be linted sequentially by one worker
+// because the fixer and the auto-disable rewrite it.
+func groupByPath(fs []file.ProtoFile) [][]int {
+	var groups [][]int
+	pathToGroup := make(map[string]int)
+	for i, f := range fs {
+		g, ok := pathToGroup[f.Path()]
+		if !ok {
+			g = len(groups)
+			pathToGroup[f.Path()] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+func (c *CmdLint) lintOneFile(
+	f file.ProtoFile,
+	rules ruleSet,
+	lintCache *cache.Cache,
+) lintResult {
+	if c.config.diffMode {
+		failures, diff, err := c.runOneFileWithDiff(f, rules.all)
+		return lintResult{failures: failures, diff: diff, err: err}
+	}
+	failures, err := c.runOneFileWithCache(f, rules.all, rules.fixing, lintCache)
+	return lintResult{failures: failures, err: err}
+}
+
+// runOneFileWithDiff fixes the in-memory content instead of the file and returns the changes as a unified diff.
+func (c *CmdLint) runOneFileWithDiff(
 	f file.ProtoFile,
+	allRules internalrule.Rules,
+) ([]report.Failure, []byte, error) {
+	original, err := osutil.ReadFile(f.Path())
+	if err != nil {
+		return nil, nil, err
+	}
+	// The content from stdin is already in memory.
+	if !osutil.HasOverlay(f.Path()) {
+		osutil.SetOverlay(f.Path(), original)
+		defer osutil.RemoveOverlay(f.Path())
+	}
+
+	failures, err := c.runOneFile(f, allRules)
+	if err != nil {
+		return nil, nil, err
+	}
+	fixed, err := osutil.ReadFile(f.Path())
+	if err != nil {
+		return nil, nil, err
+	}
+	return failures, diffutil.Unified(f.DisplayPath(), original, fixed), nil
+}
+
+func (c *CmdLint) runOneFileWithCache(
+	f file.ProtoFile,
+	allRules internalrule.Rules,
+	fixingRules internalrule.Rules,
+	lintCache *cache.Cache,
 ) ([]report.Failure, error) {
-	// Gen rules first
-	// If there is no rule, we can skip parse proto file
-	rs, err := c.config.GenRules(f)
+	if lintCache == nil {
+		return c.runOneFileWithSuggestion(f, allRules, fixingRules)
+	}
+
+	data, err := osutil.ReadFile(f.Path())
 	if err != nil {
 		return nil, err
 	}
+	contentHash := cache.HashContent(data)
+	// The results also depend on what the file reads from the other files.
+	if symbols := c.l.Symbols(); symbols != nil {
+		contentHash = cache.Key(contentHash, internalsymbol.FileKey(symbols, f.Path()))
+	}
+	if failures, ok := lintCache.Lookup(f.DisplayPath(), contentHash); ok {
+		return failures, nil
+	}
+
+	failures, err := c.runOneFileWithSuggestion(f, allRules, fixingRules)
+	if err != nil {
+		return nil, err
+	}
+	lintCache.Store(f.DisplayPath(), contentHash, failures)
+	return failures, nil
+}
+
+// runOneFileWithSuggestion attaches the suggested fixes to the failures when fixingRules are given.
+func (c *CmdLint) runOneFileWithSuggestion(
+	f file.ProtoFile,
+	allRules internalrule.Rules,
+	fixingRules internalrule.Rules,
+) ([]report.Failure, error) {
+	failures, err := c.runOneFile(f, allRules)
+	if err != nil || len(failures) == 0 || len(fixingRules) == 0 {
+		return failures, err
+	}
+	return c.l.SuggestFixes(f, c.config.FilterRules(f, fixingRules), failures, c.config.verbose)
+}
+
+func (c *CmdLint) runOneFile(
+	f file.ProtoFile,
+	allRules internalrule.Rules,
+) ([]report.Failure, error) {
+	// Gen rules first
+	// If there is no rule, we can skip parse proto file
+	rs := c.config.FilterRules(f, allRules)
 	if len(rs) == 0 {
 		return []report.Failure{}, nil
 	}
 
-	return c.l.Run(func(p *parser.Proto) (*parser.Proto, error) {
-		// Recreate a protoFile if the previous rule changed the filename.
-		if p != nil && p.Meta.Filename != f.DisplayPath() {
-			newFilename := p.Meta.Filename
-			newBase := filepath.Base(newFilename)
-			f = file.NewProtoFile(filepath.Join(filepath.Dir(f.Path()), newBase), newFilename)
-		}
-
-		proto, err := f.Parse(c.config.verbose)
-		if err != nil {
-			if c.config.verbose {
-				return nil, ParseError{Message: err.Error()}
-			}
-			return nil, ParseError{Message: fmt.Sprintf("%s. Use -v for more details", err)}
-		}
-		return proto, nil
-	}, rs)
+	_, failures, err := c.l.RunFile(f, rs, c.config.verbose)
+	return failures, err
 }
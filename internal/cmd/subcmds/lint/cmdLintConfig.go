@@ -1,11 +1,18 @@
 package lint
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/addon/rules"
 	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter/cache"
 	"github.com/yoheimuta/protolint/internal/linter/config"
 	"github.com/yoheimuta/protolint/internal/linter/file"
 	"github.com/yoheimuta/protolint/internal/linter/report"
+	internalrule "github.com/yoheimuta/protolint/internal/linter/rule"
 	"github.com/yoheimuta/protolint/linter/autodisable"
 	"github.com/yoheimuta/protolint/linter/rule"
 )
@@ -14,10 +21,17 @@ import (
 type CmdLintConfig struct {
 	external        config.ExternalConfig
 	fixMode         bool
+	diffMode        bool
+	suggestFixes    bool
 	autoDisableType autodisable.PlacementType
 	verbose         bool
 	reporters       report.ReportersWithOutput
 	plugins         []shared.RuleSet
+	pluginValues    []string
+	jobs            int
+	useCache        bool
+	cacheLocation   string
+	version         string
 }
 
 // NewCmdLintConfig creates a new CmdLintConfig.
@@ -40,37 +54,102 @@ func NewCmdLintConfig(
 
 	return CmdLintConfig{
 		external:        externalConfig,
-		fixMode:         flags.FixMode,
+		fixMode:         flags.FixMode || flags.DiffMode,
+		diffMode:        flags.DiffMode,
+		suggestFixes:    flags.SuggestFixes && !flags.FixMode && !flags.DiffMode,
 		autoDisableType: flags.AutoDisableType,
 		verbose:         flags.Verbose,
 		reporters:       reporters,
 		plugins:         flags.Plugins,
+		pluginValues:    flags.PluginValues,
+		jobs:            flags.Jobs,
+		useCache:        flags.UseCache,
+		cacheLocation:   flags.CacheLocation,
+		version:         flags.Version,
 	}
 }
 
-// GenRules generates rules which are applied to the filename path.
-func (c CmdLintConfig) GenRules(
-	f file.ProtoFile,
-) ([]rule.HasApply, error) {
-	allRules, err := subcmds.NewAllRules(c.external.Lint.RulesOption, c.fixMode, c.autoDisableType, c.verbose, c.plugins)
-	if err != nil {
-		return nil, err
+// BuildWasmPlugins builds the plugins listed in the config.
+// The caller must release them with subcmds.ClosePlugins.
+func (c CmdLintConfig) BuildWasmPlugins() ([]shared.RuleSet, error) {
+	return subcmds.BuildWasmPlugins(c.external.WasmPlugins(), c.verbose)
+}
+
+// AllRules generates all rules available in this run, including the plugin ones.
+// The wasmPlugins are the ones built by BuildWasmPlugins.
+// It returns an error if -auto_disable is given for a custom rule whose target it can't put the comments on.
+func (c CmdLintConfig) AllRules(wasmPlugins []shared.RuleSet) (internalrule.Rules, error) {
+	if c.autoDisableType != autodisable.Noop {
+		for _, r := range c.external.Lint.CustomRules {
+			if !rules.CustomRuleSupportsAutoDisable(r.Target) {
+				return nil, fmt.Errorf("custom rule %q: -auto_disable doesn't support the target %q. Remove the flag or disable the rule with the comments", r.ID, r.Target)
+			}
+		}
 	}
+	plugins := append(append([]shared.RuleSet(nil), c.plugins...), wasmPlugins...)
+	return subcmds.NewAllRules(c.external.Lint.RulesOption, c.external.Lint.CustomRules, c.external.Lint.Plugins, c.fixMode, c.autoDisableType, c.verbose, plugins)
+}
+
+// FixingRules generates the built-in rules in fix mode to suggest fixes.
+// It returns nil unless the fixes are suggested in this run.
+func (c CmdLintConfig) FixingRules() (internalrule.Rules, error) {
+	if !c.suggestFixes {
+		return nil, nil
+	}
+	// The plugins are left out because they share the server with the rules in lint mode.
+	return subcmds.NewAllRules(c.external.Lint.RulesOption, c.external.Lint.CustomRules, c.external.Lint.Plugins, true, autodisable.Noop, c.verbose, nil)
+}
 
-	var defaultRuleIDs []string
-	if c.external.Lint.Rules.AllDefault {
-		defaultRuleIDs = allRules.IDs()
-	} else {
-		defaultRuleIDs = allRules.Default().IDs()
+// LoadCache loads the lint cache if it's available in this run.
+// It returns nil otherwise.
+// The keys of the enabled rules implementing HasCacheKey and the digest of the plugins are a part of the cache key,
+// and the cache is off when any of them fails.
+// The symbol table isn't, because each file is looked up with what it reads from the other files.
+func (c CmdLintConfig) LoadCache(
+	allRules internalrule.Rules,
+) (*cache.Cache, error) {
+	// The cached failures can't fix nor disable the problems.
+	if !c.useCache || c.fixMode || c.autoDisableType != autodisable.Noop {
+		return nil, nil
 	}
 
-	var hasApplies []rule.HasApply
+	lint, err := json.Marshal(c.external.Lint)
+	if err != nil {
+		return nil, err
+	}
+	var ruleIDs []string
+	for _, r := range allRules {
+		ruleIDs = append(ruleIDs, fmt.Sprintf("%s:%s", r.ID(), r.Severity()))
+	}
+	parts := []string{c.version, string(lint), strings.Join(ruleIDs, ","), fmt.Sprintf("suggest_fixes=%t", c.suggestFixes)}
+	if plugins := append(append([]string(nil), c.pluginValues...), c.external.WasmPlugins()...); 0 < len(plugins) {
+		digest, err := subcmds.PluginsDigest(plugins)
+		if err != nil {
+			// The results of the plugins can't be reused without knowing which binaries they run.
+			return nil, nil
+		}
+		parts = append(parts, "plugins="+digest)
+	}
 	for _, r := range allRules {
-		if c.external.ShouldSkipRule(r.ID(), f.DisplayPath(), defaultRuleIDs) {
+		h, ok := r.(internalrule.HasCacheKey)
+		if !ok || !c.external.EnablesRule(r.ID(), allRules) {
 			continue
 		}
-		hasApplies = append(hasApplies, r)
+		ruleKey, err := h.CacheKey()
+		if err != nil {
+			// The results can't be reused without knowing what they depend on.
+			return nil, nil
+		}
+		parts = append(parts, r.ID()+"="+ruleKey)
 	}
+	key := cache.Key(parts...)
+	return cache.Load(c.cacheLocation, key), nil
+}
 
-	return hasApplies, nil
+// FilterRules selects the rules among allRules which are applied to the filename path.
+func (c CmdLintConfig) FilterRules(
+	f file.ProtoFile,
+	allRules internalrule.Rules,
+) []rule.HasApply {
+	return c.external.SelectRules(f.DisplayPath(), allRules)
 }
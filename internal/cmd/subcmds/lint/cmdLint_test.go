@@ -0,0 +1,425 @@
+package lint_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds/lint"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+)
+
+var failurePath = regexp.MustCompile(`^\[(.+):\d+:\d+\] `)
+
+// runLint runs the lint command with the args, and returns the exit code, stdout and stderr.
+func runLint(t *testing.T, args ...string) (osutil.ExitCode, string, string) {
+	flags, err := lint.NewFlags(args)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd, err := lint.NewCmdLint(flags, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	code := cmd.Run()
+	return code, stdout.String(), stderr.String()
+}
+
+// reportedPaths returns the paths of the reported failures, merging the consecutive ones.
+func reportedPaths(output string) []string {
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		m := failurePath.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if len(paths) == 0 || paths[len(paths)-1] != m[1] {
+			paths = append(paths, m[1])
+		}
+	}
+	return paths
+}
+
+// displayPath returns the path shown in the output for the file in the testdata.
+func displayPath(t *testing.T, elem ...string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(wd, setting_test.TestDataPath(elem...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
+
+func TestCmdLint_Run_jobs(t *testing.T) {
+	configPath := "-config_path=" + setting_test.TestDataPath("lint", "protolint.yaml")
+
+	tests := []struct {
+		name       string
+		inputPaths []string
+		wantPaths  []string
+	}{
+		{
+			name:       "the files in the directory",
+			inputPaths: []string{setting_test.TestDataPath("lint")},
+			wantPaths: []string{
+				displayPath(t, "lint", "a.proto"),
+				displayPath(t, "lint", "b.proto"),
+				displayPath(t, "lint", "c.proto"),
+				displayPath(t, "lint", "sub", "d.proto"),
+			},
+		},
+		{
+			name: "the files in the given order",
+			inputPaths: []string{
+				setting_test.TestDataPath("lint", "c.proto"),
+				setting_test.TestDataPath("lint", "valid.proto"),
+				setting_test.TestDataPath("lint", "a.proto"),
+				setting_test.TestDataPath("lint", "b.proto"),
+			},
+			wantPaths: []string{
+				displayPath(t, "lint", "c.proto"),
+				displayPath(t, "lint", "a.proto"),
+				displayPath(t, "lint", "b.proto"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			wantCode, wantStdout, wantStderr := runLint(t, append([]string{configPath, "-jobs=1"}, test.inputPaths...)...)
+			if wantCode != osutil.ExitLintFailure {
+				t.Errorf("got exit code %v, but want %v", wantCode, osutil.ExitLintFailure)
+			}
+			if got := reportedPaths(wantStderr); !reflect.DeepEqual(got, test.wantPaths) {
+				t.Errorf("got %v, but want %v", got, test.wantPaths)
+			}
+
+			for _, jobs := range []string{"-jobs=2", "-jobs=8"} {
+				// Repeat to make the different schedules likely.
+				for i := 0; i < 5; i++ {
+					code, stdout, stderr := runLint(t, append([]string{configPath, jobs}, test.inputPaths...)...)
+					if code != wantCode || stdout != wantStdout || stderr != wantStderr {
+						t.Errorf("got %v, %q and %q with %s, but want %v, %q and %q", code, stdout, stderr, jobs, wantCode, wantStdout, wantStderr)
+					}
+				}
+			}
+		})
+	}
+}
+
+// setStdin makes the content the stdin until the test ends.
+func setStdin(t *testing.T, content string) {
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = f.Close()
+	})
+}
+
+func TestCmdLint_Run_stdin(t *testing.T) {
+	configPath := "-config_path=" + setting_test.TestDataPath("lint", "protolint.yaml")
+	invalidPath := setting_test.TestDataPath("lint", "a.proto")
+	invalid, err := os.ReadFile(invalidPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := os.ReadFile(setting_test.TestDataPath("lint", "valid.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		inputArgs  []string
+		inputStdin []byte
+		wantCode   osutil.ExitCode
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "the failures are reported with -stdin_filename",
+			inputArgs:  []string{"-stdin", "-stdin_filename=" + invalidPath},
+			inputStdin: invalid,
+			wantCode:   osutil.ExitLintFailure,
+			wantStderr: `[` + displayPath(t, "lint", "a.proto") + `:4:5] Found an incorrect indentation style "    ". "  " is correct.
+[` + displayPath(t, "lint", "a.proto") + `:3:1] Enum name "enumName" must be UpperCamelCase like "EnumName"
+`,
+		},
+		{
+			name:       "the file of -stdin_filename doesn't have to exist",
+			inputArgs:  []string{"-stdin", "-stdin_filename=" + setting_test.TestDataPath("lint", "not_found.proto")},
+			inputStdin: valid,
+			wantCode:   osutil.ExitSuccess,
+		},
+		{
+			name:       "the fixed content is written to stdout with -fix",
+			inputArgs:  []string{"-stdin", "-fix", "-stdin_filename=" + invalidPath},
+			inputStdin: invalid,
+			wantCode:   osutil.ExitLintFailure,
+			wantStdout: `syntax = "proto3";
+
+enum EnumName {
+  ENUM_NAME_UNSPECIFIED = 0;
+}
+`,
+			wantStderr: `[` + displayPath(t, "lint", "a.proto") + `:4:5] Found an incorrect indentation style "    ". "  " is correct.
+[` + displayPath(t, "lint", "a.proto") + `:3:1] Enum name "enumName" must be UpperCamelCase like "EnumName"
+`,
+		},
+		{
+			name:       "the unchanged content is written to stdout with -fix",
+			inputArgs:  []string{"-stdin", "-fix", "-stdin_filename=" + invalidPath},
+			inputStdin: valid,
+			wantCode:   osutil.ExitSuccess,
+			wantStdout: string(valid),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			setStdin(t, string(test.inputStdin))
+
+			code, stdout, stderr := runLint(t, append([]string{configPath}, test.inputArgs...)...)
+			if code != test.wantCode {
+				t.Errorf("got exit code %v, but want %v", code, test.wantCode)
+			}
+			if stdout != test.wantStdout {
+				t.Errorf("got stdout %q, but want %q", stdout, test.wantStdout)
+			}
+			if stderr != test.wantStderr {
+				t.Errorf("got stderr %q, but want %q", stderr, test.wantStderr)
+			}
+
+			got, err := os.ReadFile(invalidPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, invalid) {
+				t.Errorf("got the file changed to %q, but want no change", got)
+			}
+		})
+	}
+}
+
+func TestNewCmdLint_stdin(t *testing.T) {
+	for _, args := range [][]string{
+		{"-stdin", setting_test.TestDataPath("lint", "a.proto")},
+		{"-stdin", "-watch"},
+	} {
+		flags, err := lint.NewFlags(args)
+		if err != nil {
+			t.Fatalf("got err %v, but want nil", err)
+		}
+		if _, err := lint.NewCmdLint(flags, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+			t.Errorf("got err nil with %v, but want err", args)
+		}
+	}
+}
+
+// syncBuffer is a buffer which the watch mode can write while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitForLinted waits until the watch mode lints the n-th time, and returns the output of that time.
+func waitForLinted(t *testing.T, output *syncBuffer, n int) string {
+	const linted = "is watching for changes\n"
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		got := output.String()
+		if strings.Count(got, linted) >= n {
+			chunks := strings.SplitAfter(got, linted)
+			return chunks[n-1]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("got %q, but want it to lint %d times", output.String(), n)
+	return ""
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCmdLint_Watch(t *testing.T) {
+	dir := t.TempDir()
+	protoPath := filepath.Join(dir, "a.proto")
+	configPath := filepath.Join(dir, "protolint.yaml")
+	basePath := filepath.Join(dir, "base.yaml")
+	writeFile(t, configPath, "lint:\n  extends:\n    - base.yaml\n")
+	writeFile(t, basePath, "lint:\n  rules:\n    remove:\n      - FILE_HAS_COMMENT\n")
+	writeFile(t, protoPath, `syntax = "proto3";
+
+enum enumName {
+    ENUM_NAME_UNSPECIFIED = 0;
+}
+`)
+
+	flags, err := lint.NewFlags([]string{"-watch", "-config_path=" + configPath, dir})
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	var stdout bytes.Buffer
+	var stderr syncBuffer
+	cmd, err := lint.NewCmdLint(flags, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan osutil.ExitCode)
+	go func() {
+		done <- cmd.Watch(ctx, 10*time.Millisecond)
+	}()
+
+	const (
+		indent   = "Found an incorrect indentation style"
+		enumName = `Enum name "enumName" must be UpperCamelCase`
+	)
+
+	got := waitForLinted(t, &stderr, 1)
+	if !strings.Contains(got, "linted 1 of 1 files") || !strings.Contains(got, indent) || !strings.Contains(got, enumName) {
+		t.Errorf("got %q on the start, but want the failures of a.proto", got)
+	}
+
+	writeFile(t, protoPath, `syntax = "proto3";
+
+enum enumName {
+  ENUM_NAME_UNSPECIFIED = 0;
+}
+`)
+	got = waitForLinted(t, &stderr, 2)
+	if !strings.Contains(got, "linted 1 of 1 files") || strings.Contains(got, indent) || !strings.Contains(got, enumName) {
+		t.Errorf("got %q after the file changed, but want the failure of the enum name only", got)
+	}
+
+	writeFile(t, basePath, "lint:\n  rules:\n    remove:\n      - FILE_HAS_COMMENT\n      - ENUM_NAMES_UPPER_CAMEL_CASE\n")
+	got = waitForLinted(t, &stderr, 3)
+	if !strings.Contains(got, "protolint reloaded the config file at "+configPath) || !strings.Contains(got, "linted 1 of 1 files") || strings.Contains(got, enumName) {
+		t.Errorf("got %q after the extended config changed, but want no failures with the reloaded config", got)
+	}
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != osutil.ExitSuccess {
+			t.Errorf("got exit code %v, but want %v", code, osutil.ExitSuccess)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("got no exit after the cancel")
+	}
+}
+
+func TestCmdLint_Run_autoDisable(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputRule  string
+		wantCode   osutil.ExitCode
+		wantStderr string
+		wantProto  string
+	}{
+		{
+			name:       "the custom rule inserts the disable comments",
+			inputRule:  "      - id: ENUM_NAMES_NO_LOWER\n        target: enum\n        pattern: ^[A-Z]\n",
+			wantCode:   osutil.ExitLintFailure,
+			wantStderr: "[{path}:3:1] Enum name \"enumName\" must match \"^[A-Z]\".\n",
+			wantProto: `syntax = "proto3";
+
+// protolint:disable:next ENUM_NAMES_NO_LOWER
+enum enumName {
+  ENUM_NAME_UNSPECIFIED = 0;
+}
+`,
+		},
+		{
+			name:       "the custom rule for the target which the auto-disable doesn't support",
+			inputRule:  "      - id: PACKAGE_VERSIONED\n        target: package\n        pattern: \\.v\\d+$\n",
+			wantCode:   osutil.ExitInternalFailure,
+			wantStderr: `custom rule "PACKAGE_VERSIONED": -auto_disable doesn't support the target "package". Remove the flag or disable the rule with the comments` + "\n",
+			wantProto: `syntax = "proto3";
+
+enum enumName {
+  ENUM_NAME_UNSPECIFIED = 0;
+}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "protolint.yaml")
+			protoPath := filepath.Join(dir, "a.proto")
+			writeFile(t, configPath, "lint:\n  rules:\n    no_default: true\n  custom_rules:\n"+test.inputRule)
+			writeFile(t, protoPath, `syntax = "proto3";
+
+enum enumName {
+  ENUM_NAME_UNSPECIFIED = 0;
+}
+`)
+
+			code, _, stderr := runLint(t, "-auto_disable=next", "-config_path="+configPath, protoPath)
+			if code != test.wantCode {
+				t.Errorf("got exit code %v, but want %v", code, test.wantCode)
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			displayPath, err := filepath.Rel(wd, protoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.ReplaceAll(test.wantStderr, "{path}", displayPath); stderr != want {
+				t.Errorf("got stderr %q, but want %q", stderr, want)
+			}
+			got, err := os.ReadFile(protoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.wantProto {
+				t.Errorf("got %q, but want %q", got, test.wantProto)
+			}
+		})
+	}
+}
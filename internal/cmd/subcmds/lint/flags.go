@@ -2,6 +2,8 @@ package lint
 
 import (
 	"flag"
+	"runtime"
+	"time"
 
 	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
 	"github.com/yoheimuta/protolint/linter/autodisable"
@@ -21,13 +23,29 @@ type Flags struct {
 	ConfigPath                string
 	ConfigDirPath             string
 	FixMode                   bool
+	DiffMode                  bool
+	SuggestFixes              bool
 	Reporter                  report.Reporter
 	AutoDisableType           autodisable.PlacementType
 	OutputFilePath            string
 	Verbose                   bool
 	NoErrorOnUnmatchedPattern bool
 	Plugins                   []shared.RuleSet
+	PluginValues              []string
 	AdditionalReporters       reporterStreamFlags
+	Jobs                      int
+	UseCache                  bool
+	CacheLocation             string
+	Stdin                     bool
+	StdinFilename             string
+	Watch                     bool
+	WatchInterval             time.Duration
+	BaselinePath              string
+	WriteBaselinePath         string
+	IncludePaths              []string
+
+	// Version is the protolint version given by the caller, not by the command line.
+	Version string
 }
 
 // NewFlags creates a new Flags.
@@ -43,6 +61,7 @@ func NewFlags(
 	var af autoDisableFlag
 	var pf subcmds.PluginFlag
 	var rfs reporterStreamFlags
+	var ipf subcmds.IncludePathFlag
 
 	f.StringVar(
 		&f.ConfigPath,
@@ -62,6 +81,18 @@ func NewFlags(
 		false,
 		"mode that the command line automatically fix some of the problems",
 	)
+	f.BoolVar(
+		&f.DiffMode,
+		"diff",
+		false,
+		"mode that the command line prints the fixes by -fix as a unified diff without writing files. It exits with a non-zero code if there are any fixes",
+	)
+	f.BoolVar(
+		&f.SuggestFixes,
+		"suggest_fixes",
+		false,
+		"mode that the command line attaches the edits by -fix to the reported problems without writing files. The json, sarif and mcp reporters print them",
+	)
 	f.Var(
 		&rf,
 		"reporter",
@@ -81,7 +112,7 @@ func NewFlags(
 	f.Var(
 		&pf,
 		"plugin",
-		`plugins to provide custom lint rule set. Note that it's necessary to specify it as path format'`,
+		`plugins to provide custom lint rule set. Note that it's necessary to specify it as path format'. The path ending with .wasm is run as a WASI module`,
 	)
 	f.BoolVar(
 		&f.Verbose,
@@ -101,7 +132,78 @@ func NewFlags(
 		"Adds a reporter to the list of reporters to use. The format should be 'name of reporter':'Path-To_output_file'",
 	)
 
+	f.IntVar(
+		&f.Jobs,
+		"jobs",
+		runtime.NumCPU(),
+		"number of files to lint concurrently. Defaults to the number of CPUs",
+	)
+
+	f.BoolVar(
+		&f.UseCache,
+		"cache",
+		false,
+		"only lint the changed files and reuse the results of the others. The cache is not used with -fix or -auto_disable",
+	)
+	f.StringVar(
+		&f.CacheLocation,
+		"cache_location",
+		".protolint-cache",
+		"path/to/the_cache_directory",
+	)
+
+	f.BoolVar(
+		&f.Stdin,
+		"stdin",
+		false,
+		"lint the content read from stdin instead of files. With -fix, the fixed content is written to stdout",
+	)
+	f.StringVar(
+		&f.StdinFilename,
+		"stdin_filename",
+		"stdin.proto",
+		"path/to/the_file.proto to treat stdin as. It decides the config to apply and the filename in the output",
+	)
+
+	f.BoolVar(
+		&f.Watch,
+		"watch",
+		false,
+		"keep running and lint the changed files again. The rules are rebuilt when the config file or a file it extends changes",
+	)
+	f.DurationVar(
+		&f.WatchInterval,
+		"watch_interval",
+		time.Second,
+		"interval to poll the files for changes in -watch mode",
+	)
+
+	f.StringVar(
+		&f.BaselinePath,
+		"baseline",
+		"",
+		"path/to/baseline.json to suppress the failures recorded by -write-baseline. The entries no longer found are reported as stale",
+	)
+	f.StringVar(
+		&f.WriteBaselinePath,
+		"write-baseline",
+		"",
+		"path/to/baseline.json to record the current failures instead of reporting them",
+	)
+
+	f.Var(
+		&ipf,
+		"I",
+		"directory to search the imports in, which can be given more than once. Without it, the working directory and the ancestor directories of each file are searched",
+	)
+	f.Var(
+		&ipf,
+		"proto_path",
+		"same as -I",
+	)
+
 	_ = f.Parse(args)
+	f.IncludePaths = ipf
 	if rf.reporter != nil {
 		f.Reporter = rf.reporter
 	}
@@ -117,6 +219,7 @@ func NewFlags(
 		return Flags{}, err
 	}
 	f.Plugins = plugins
+	f.PluginValues = pf.Values()
 
 	f.FilePaths = f.Args()
 	return f, nil
@@ -0,0 +1,256 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter/cache"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalsymbol "github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/report"
+)
+
+// fileStamp identifies a version of a file without reading it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// watchState is what the watch mode remembers between the polls.
+type watchState struct {
+	rules     ruleSet
+	lintCache *cache.Cache
+
+	configPath string
+	// configStamps are the stamps of the config file and the ones it extends.
+	configStamps map[string]fileStamp
+
+	protoFiles []file.ProtoFile
+	stamps     map[string]fileStamp
+	results    map[string]lintResult
+	// symbolKeys are the keys of what each file reads from the other files if any rule needs the symbol table.
+	symbolKeys map[string]string
+}
+
+// Watch lints the proto files, and then polls them at the interval to lint the changed ones again until ctx is done.
+// Each time some files change, it reports the failures of all files.
+func (c *CmdLint) Watch(
+	ctx context.Context,
+	interval time.Duration,
+) osutil.ExitCode {
+	defer plugin.CleanupClients()
+	defer subcmds.ClosePlugins(c.flags.Plugins)
+
+	state := &watchState{
+		protoFiles: c.protoFiles,
+	}
+	if err := c.loadWatchRules(state); err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
+		return osutil.ExitInternalFailure
+	}
+	defer func() {
+		state.rules.close()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.watchOnce(state); err != nil {
+			_, _ = fmt.Fprintln(c.stderr, err)
+			return osutil.ExitInternalFailure
+		}
+
+		select {
+		case <-ctx.Done():
+			return osutil.ExitSuccess
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadWatchRules builds the rules from the current config and forgets all results.
+// The previous rules are released on success.
+func (c *CmdLint) loadWatchRules(state *watchState) error {
+	rules, err := c.newRuleSet()
+	if err != nil {
+		return err
+	}
+	lintCache, err := c.config.LoadCache(rules.all)
+	if err != nil {
+		rules.close()
+		return err
+	}
+	state.rules.close()
+
+	state.rules = rules
+	state.lintCache = lintCache
+	state.configPath = c.config.external.SourcePath
+	state.configStamps = configStampsOf(state.configPath)
+	state.stamps = make(map[string]fileStamp)
+	state.results = make(map[string]lintResult)
+	state.symbolKeys = make(map[string]string)
+	return nil
+}
+
+// configStampsOf returns the stamps of the config file and the files it extends.
+// The extended files are left out when they can't be resolved, because the config fails to load then.
+func configStampsOf(configPath string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	if len(configPath) == 0 {
+		return stamps
+	}
+	stamps[configPath] = stampOf(configPath)
+	extended, _ := config.ExtendedFiles(configPath)
+	for _, path := range extended {
+		stamps[path] = stampOf(path)
+	}
+	return stamps
+}
+
+// configChanged reports whether the config file or any file it extends changed.
+func (state *watchState) configChanged() bool {
+	for path, stamp := range state.configStamps {
+		if stampOf(path) != stamp {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadConfig loads the config file again. It keeps the current config on failure.
+func (c *CmdLint) reloadConfig(state *watchState) {
+	// Keep the files failing to load from being retried until they change again.
+	stamps := configStampsOf(state.configPath)
+	for path := range state.configStamps {
+		if _, ok := stamps[path]; !ok {
+			stamps[path] = stampOf(path)
+		}
+	}
+	state.configStamps = stamps
+
+	externalConfig, err := config.GetExternalConfig(c.flags.ConfigPath, c.flags.ConfigDirPath)
+	if err == nil && externalConfig == nil {
+		err = fmt.Errorf("not found config file at %s", state.configPath)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "protolint keeps the current config because of the error: %v\n", err)
+		return
+	}
+
+	current := c.config
+	c.config = NewCmdLintConfig(*externalConfig, c.flags)
+	if err := c.loadWatchRules(state); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "protolint keeps the current config because of the error: %v\n", err)
+		c.config = current
+		return
+	}
+	_, _ = fmt.Fprintf(c.stderr, "protolint reloaded the config file at %s\n", externalConfig.SourcePath)
+}
+
+// watchOnce lints the files which changed since the last time and reports all results if any.
+func (c *CmdLint) watchOnce(state *watchState) error {
+	if state.configChanged() {
+		c.reloadConfig(state)
+	}
+
+	// Find the added files in the target directories as well.
+	protoSet, err := file.NewProtoSet(c.flags.FilePaths)
+	if err != nil {
+		// The files may be only missing for a moment, like while an editor is saving them.
+		_, _ = fmt.Fprintln(c.stderr, err)
+	} else {
+		state.protoFiles = protoSet.ProtoFiles()
+	}
+
+	var changed []file.ProtoFile
+	stamps := make(map[string]fileStamp)
+	for _, f := range state.protoFiles {
+		stamp := stampOf(f.Path())
+		stamps[f.Path()] = stamp
+		if _, ok := state.results[f.Path()]; !ok || state.stamps[f.Path()] != stamp {
+			changed = append(changed, f)
+		}
+	}
+	removed := false
+	for path := range state.results {
+		if _, ok := stamps[path]; !ok {
+			delete(state.results, path)
+			removed = true
+		}
+	}
+	state.stamps = stamps
+	if len(changed) == 0 && !removed {
+		return nil
+	}
+
+	// A change of a file can affect the failures of the other files reading it.
+	symbols := c.buildSymbols(state.protoFiles, state.rules)
+	c.l = c.l.WithSymbols(symbols)
+	if symbols != nil {
+		isChanged := make(map[string]bool)
+		for _, f := range changed {
+			isChanged[f.Path()] = true
+		}
+		symbolKeys := make(map[string]string)
+		for _, f := range state.protoFiles {
+			key := internalsymbol.FileKey(symbols, f.Path())
+			symbolKeys[f.Path()] = key
+			if !isChanged[f.Path()] && state.symbolKeys[f.Path()] != key {
+				isChanged[f.Path()] = true
+				changed = append(changed, f)
+			}
+		}
+		state.symbolKeys = symbolKeys
+	}
+
+	results := c.lintFiles(changed, state.rules, state.lintCache, false)
+	for i, f := range changed {
+		state.results[f.Path()] = results[i]
+	}
+
+	var failures []report.Failure
+	var diff []byte
+	for _, f := range state.protoFiles {
+		r, ok := state.results[f.Path()]
+		if !ok {
+			continue
+		}
+		if r.err != nil {
+			_, _ = fmt.Fprintln(c.stderr, r.err)
+			continue
+		}
+		failures = append(failures, r.failures...)
+		diff = append(diff, r.diff...)
+	}
+
+	failures = c.applyBaseline(failures, state.protoFiles)
+
+	if _, err := c.stdout.Write(diff); err != nil {
+		return err
+	}
+	if err := c.config.reporters.ReportWithFallback(c.output, failures); err != nil {
+		return err
+	}
+	if state.lintCache != nil {
+		if err := state.lintCache.Save(); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintf(c.stderr, "protolint linted %d of %d files and is watching for changes\n", len(changed), len(state.protoFiles))
+	return nil
+}
@@ -36,15 +36,34 @@ func NewCmdList(
 
 // Run lists each rule description.
 func (c *CmdList) Run() osutil.ExitCode {
+	defer subcmds.ClosePlugins(c.flags.Plugins)
 	err := c.run()
 	if err != nil {
+		_, _ = fmt.Fprintln(c.stderr, err)
 		return osutil.ExitInternalFailure
 	}
 	return osutil.ExitSuccess
 }
 
 func (c *CmdList) run() error {
-	rules, err := hasIDAndPurposes(c.flags.Plugins)
+	externalConfig, err := config.GetExternalConfig(c.flags.ConfigPath, c.flags.ConfigDirPath)
+	if err != nil {
+		return err
+	}
+	var customRules config.CustomRules
+	plugins := c.flags.Plugins
+	if externalConfig != nil {
+		customRules = externalConfig.Lint.CustomRules
+
+		wasmPlugins, err := subcmds.BuildWasmPlugins(externalConfig.WasmPlugins(), false)
+		if err != nil {
+			return err
+		}
+		defer subcmds.ClosePlugins(wasmPlugins)
+		plugins = append(append([]shared.RuleSet(nil), plugins...), wasmPlugins...)
+	}
+
+	rules, err := hasIDAndPurposes(customRules, plugins)
 	if err != nil {
 		return err
 	}
@@ -68,8 +87,11 @@ type hasIDAndPurpose interface {
 	rule.HasPurpose
 }
 
-func hasIDAndPurposes(plugins []shared.RuleSet) ([]hasIDAndPurpose, error) {
-	rs, err := subcmds.NewAllRules(config.RulesOption{}, false, autodisable.Noop, false, plugins)
+func hasIDAndPurposes(
+	customRules config.CustomRules,
+	plugins []shared.RuleSet,
+) ([]hasIDAndPurpose, error) {
+	rs, err := subcmds.NewAllRules(config.RulesOption{}, customRules, nil, false, autodisable.Noop, false, plugins)
 	if err != nil {
 		return nil, err
 	}
@@ -12,7 +12,9 @@ import (
 type Flags struct {
 	*flag.FlagSet
 
-	Plugins []shared.RuleSet
+	ConfigPath    string
+	ConfigDirPath string
+	Plugins       []shared.RuleSet
 }
 
 // NewFlags creates a new Flags.
@@ -24,10 +26,23 @@ func NewFlags(
 	}
 	var pf subcmds.PluginFlag
 
+	f.StringVar(
+		&f.ConfigPath,
+		"config_path",
+		"",
+		"path/to/protolint.yaml to list the custom rules. Note that if both are set, config_dir_path is ignored.",
+	)
+	f.StringVar(
+		&f.ConfigDirPath,
+		"config_dir_path",
+		"",
+		"path/to/the_directory_including_protolint.yaml to list the custom rules",
+	)
+
 	f.Var(
 		&pf,
 		"plugin",
-		`plugins to provide custom lint rule set. Note that it's necessary to specify it as path format'`,
+		`plugins to provide custom lint rule set. Note that it's necessary to specify it as path format'. The path ending with .wasm is run as a WASI module`,
 	)
 
 	_ = f.Parse(args)
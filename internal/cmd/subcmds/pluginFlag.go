@@ -2,12 +2,16 @@ package subcmds
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/addon/plugin/wasm"
+	"github.com/yoheimuta/protolint/internal/linter/cache"
 
 	"github.com/hashicorp/go-plugin"
 )
@@ -28,11 +32,27 @@ func (f *PluginFlag) Set(value string) error {
 	return nil
 }
 
+// Values returns the plugins given by the flags.
+func (f *PluginFlag) Values() []string {
+	return f.raws
+}
+
 // BuildPlugins builds all plugins.
+// The plugin whose path ends with .wasm is run as a WASI module instead of a command.
 func (f *PluginFlag) BuildPlugins(verbose bool) ([]shared.RuleSet, error) {
 	var plugins []shared.RuleSet
 
 	for _, value := range f.raws {
+		if isWasmPlugin(value) {
+			ruleSet, err := BuildWasmPlugins([]string{value}, verbose)
+			if err != nil {
+				ClosePlugins(plugins)
+				return nil, err
+			}
+			plugins = append(plugins, ruleSet...)
+			continue
+		}
+
 		level := hclog.Warn
 		if verbose {
 			level = hclog.Trace
@@ -66,3 +86,65 @@ func (f *PluginFlag) BuildPlugins(verbose bool) ([]shared.RuleSet, error) {
 	}
 	return plugins, nil
 }
+
+// BuildWasmPlugins builds the plugins compiled to WASI modules.
+// Each value is the path to the module optionally followed by the arguments.
+// The caller must release them with ClosePlugins.
+func BuildWasmPlugins(values []string, verbose bool) ([]shared.RuleSet, error) {
+	var plugins []shared.RuleSet
+	for _, value := range values {
+		fields := strings.Fields(value)
+		if len(fields) == 0 || !wasm.IsModule(fields[0]) {
+			return nil, fmt.Errorf("not found a WASI module in the plugin %q", value)
+		}
+		ruleSet, err := wasm.NewRuleSet(fields[0], fields[1:], verbose)
+		if err != nil {
+			ClosePlugins(plugins)
+			return nil, err
+		}
+		plugins = append(plugins, ruleSet)
+	}
+	return plugins, nil
+}
+
+// ClosePlugins releases the plugins which hold their resources in this process, like the WASI modules.
+// The subprocess plugins are left to plugin.CleanupClients.
+func ClosePlugins(plugins []shared.RuleSet) {
+	for _, p := range plugins {
+		if c, ok := p.(io.Closer); ok {
+			_ = c.Close()
+		}
+	}
+}
+
+// PluginsDigest hashes the plugins together with the executables or the modules they run,
+// so that a rebuilt plugin changes the digest. The executable is the first word of the command
+// searched in PATH. It fails when the file isn't found.
+func PluginsDigest(values []string) (string, error) {
+	var parts []string
+	for _, value := range values {
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		path := fields[0]
+		if !wasm.IsModule(path) {
+			p, err := exec.LookPath(path)
+			if err != nil {
+				return "", err
+			}
+			path = p
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, value, cache.HashContent(content))
+	}
+	return cache.Key(parts...), nil
+}
+
+func isWasmPlugin(value string) bool {
+	fields := strings.Fields(value)
+	return 0 < len(fields) && wasm.IsModule(fields[0])
+}
@@ -1,6 +1,8 @@
 package subcmds
 
 import (
+	"fmt"
+
 	"github.com/yoheimuta/protolint/internal/addon/plugin"
 	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
 	"github.com/yoheimuta/protolint/internal/addon/rules"
@@ -12,26 +14,72 @@ import (
 // NewAllRules creates new all rules.
 func NewAllRules(
 	option config.RulesOption,
+	customRules config.CustomRules,
+	pluginRules config.PluginRules,
 	fixMode bool,
 	autoDisableType autodisable.PlacementType,
 	verbose bool,
 	plugins []shared.RuleSet,
 ) (internalrule.Rules, error) {
-	rs := newAllInternalRules(option, fixMode, autoDisableType)
+	rs, err := newAllInternalRules(option, fixMode, autoDisableType)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := newCustomRules(customRules, rs, autoDisableType)
+	if err != nil {
+		return nil, err
+	}
+	rs = append(rs, cs...)
 
-	es, err := plugin.GetExternalRules(plugins, fixMode, verbose)
+	es, err := plugin.GetExternalRules(plugins, pluginRules, fixMode, verbose)
 	if err != nil {
 		return nil, err
 	}
+	// The custom rules can't share the IDs with the plugin ones, which the config and the comments can't tell apart.
+	for _, e := range es {
+		for _, c := range cs {
+			if e.ID() == c.ID() {
+				return nil, fmt.Errorf("invalid custom rule %q: the id is already used by a plugin", c.ID())
+			}
+		}
+	}
 	rs = append(rs, es...)
 	return rs, nil
 }
 
+// newCustomRules creates the rules declared in the configuration.
+// Their IDs must differ from each other and from the ones of the builtin rules.
+func newCustomRules(
+	customRules config.CustomRules,
+	builtinRules internalrule.Rules,
+	autoDisableType autodisable.PlacementType,
+) (internalrule.Rules, error) {
+	ids := make(map[string]bool)
+	for _, id := range builtinRules.IDs() {
+		ids[id] = true
+	}
+
+	var rs internalrule.Rules
+	for _, c := range customRules {
+		r, err := rules.NewCustomRule(c, autoDisableType)
+		if err != nil {
+			return nil, err
+		}
+		if ids[r.ID()] {
+			return nil, fmt.Errorf("invalid custom rule %q: the id is already used", r.ID())
+		}
+		ids[r.ID()] = true
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
 func newAllInternalRules(
 	option config.RulesOption,
 	fixMode bool,
 	autoDisableType autodisable.PlacementType,
-) internalrule.Rules {
+) (internalrule.Rules, error) {
 	syntaxConsistent := option.SyntaxConsistent
 	fileNamesLowerSnakeCase := option.FileNamesLowerSnakeCase
 	indent := option.Indent
@@ -48,6 +96,14 @@ func newAllInternalRules(
 	enumFieldsHaveComment := option.EnumFieldsHaveComment
 	repeatedFieldNamesPluralized := option.RepeatedFieldNamesPluralized
 
+	packageVersionSuffix, err := rules.NewPackageVersionSuffixRule(
+		option.PackageVersionSuffix.Severity,
+		option.PackageVersionSuffix.Pattern,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return internalrule.Rules{
 		rules.NewFileHasCommentRule(
 			option.FileHasComment.Severity,
@@ -192,5 +248,50 @@ func newAllInternalRules(
 		rules.NewFieldNumbersOrderAscendingRule(
 			option.FieldNumbersOrderAscending.Severity,
 		),
-	}
+		rules.NewImportsUnusedRule(
+			option.ImportsUnused.Severity,
+			fixMode,
+		),
+		rules.NewImportsMissingRule(
+			option.ImportsMissing.Severity,
+			fixMode,
+		),
+		rules.NewImportNoCycleRule(
+			option.ImportNoCycle.Severity,
+		),
+		rules.NewPackageDirectoryMatchRule(
+			option.PackageDirectoryMatch.Severity,
+			option.PackageDirectoryMatch.Root,
+		),
+		rules.NewPackageSameDirectoryRule(
+			option.PackageSameDirectory.Severity,
+		),
+		rules.NewPackageSameFileOptionsRule(
+			option.PackageSameFileOptions.Severity,
+			option.PackageSameFileOptions.Options,
+		),
+		packageVersionSuffix,
+		rules.NewStablePackageNoUnstableImportRule(
+			option.StablePackageNoUnstableImport.Severity,
+		),
+		rules.NewFieldNumbersValidRule(
+			option.FieldNumbersValid.Severity,
+		),
+		rules.NewFieldNumbersNotReservedRule(
+			option.FieldNumbersNotReserved.Severity,
+		),
+		rules.NewFieldNumbersUniqueRule(
+			option.FieldNumbersUnique.Severity,
+		),
+		rules.NewFieldNumbersNoGapsRule(
+			option.FieldNumbersNoGaps.Severity,
+			option.FieldNumbersNoGaps.MaxGap,
+		),
+		rules.NewFieldsReservedOnDeletionRule(
+			option.FieldsReservedOnDeletion.Severity,
+			option.FieldsReservedOnDeletion.GitRef,
+			option.FieldsReservedOnDeletion.SnapshotDir,
+			fixMode,
+		),
+	}, nil
 }
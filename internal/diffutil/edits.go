@@ -0,0 +1,78 @@
+package diffutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Edit represents the replacement of old[Start:End] with NewText.
+type Edit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// Edits returns the edits which turn old into new.
+// Each edit is as small as possible and doesn't split a UTF-8 character.
+func Edits(
+	old []byte,
+	new []byte,
+) []Edit {
+	var es []Edit
+
+	edits := diffLines(splitLines(old), splitLines(new))
+	offset := 0
+	for i := 0; i < len(edits); {
+		if edits[i].kind == editEqual {
+			offset += len(edits[i].line)
+			i++
+			continue
+		}
+
+		start := offset
+		var deleted, inserted strings.Builder
+		for ; i < len(edits) && edits[i].kind != editEqual; i++ {
+			switch edits[i].kind {
+			case editDelete:
+				deleted.WriteString(edits[i].line)
+				offset += len(edits[i].line)
+			case editInsert:
+				inserted.WriteString(edits[i].line)
+			}
+		}
+		es = append(es, trim(start, deleted.String(), inserted.String()))
+	}
+	return es
+}
+
+// trim drops the common prefix and suffix of the deleted and inserted text.
+func trim(
+	start int,
+	deleted string,
+	inserted string,
+) Edit {
+	prefix := 0
+	for prefix < len(deleted) && prefix < len(inserted) && deleted[prefix] == inserted[prefix] {
+		prefix++
+	}
+	for 0 < prefix && prefix < len(deleted) && !utf8.RuneStart(deleted[prefix]) {
+		prefix--
+	}
+	deleted, inserted = deleted[prefix:], inserted[prefix:]
+
+	suffix := 0
+	for suffix < len(deleted) && suffix < len(inserted) &&
+		deleted[len(deleted)-1-suffix] == inserted[len(inserted)-1-suffix] {
+		suffix++
+	}
+	for 0 < suffix && !utf8.RuneStart(deleted[len(deleted)-suffix]) {
+		suffix--
+	}
+	deleted, inserted = deleted[:len(deleted)-suffix], inserted[:len(inserted)-suffix]
+
+	return Edit{
+		Start:   start + prefix,
+		End:     start + prefix + len(deleted),
+		NewText: inserted,
+	}
+}
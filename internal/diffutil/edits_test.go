@@ -0,0 +1,74 @@
+package diffutil_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/diffutil"
+)
+
+func TestEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputOld string
+		inputNew string
+		want     []diffutil.Edit
+	}{
+		{
+			name:     "no edit for the same contents",
+			inputOld: "a\nb\n",
+			inputNew: "a\nb\n",
+		},
+		{
+			name:     "a replaced word",
+			inputOld: "syntax = \"proto3\";\nenum enumName {\n}\n",
+			inputNew: "syntax = \"proto3\";\nenum EnumName {\n}\n",
+			want: []diffutil.Edit{
+				{Start: 24, End: 25, NewText: "E"},
+			},
+		},
+		{
+			name:     "an inserted indentation",
+			inputOld: "enum A {\nB = 0;\n}\n",
+			inputNew: "enum A {\n  B = 0;\n}\n",
+			want: []diffutil.Edit{
+				{Start: 9, End: 9, NewText: "  "},
+			},
+		},
+		{
+			name:     "separate edits for separate changes",
+			inputOld: "1\n2\n3\n",
+			inputNew: "0\n2\n",
+			want: []diffutil.Edit{
+				{Start: 0, End: 1, NewText: "0"},
+				{Start: 4, End: 6},
+			},
+		},
+		{
+			name:     "a multibyte character isn't split",
+			inputOld: "// あ\n",
+			inputNew: "// い\n",
+			want: []diffutil.Edit{
+				{Start: 3, End: 6, NewText: "い"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := diffutil.Edits([]byte(test.inputOld), []byte(test.inputNew))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+
+			applied := test.inputOld
+			for i := len(got) - 1; 0 <= i; i-- {
+				applied = applied[:got[i].Start] + got[i].NewText + applied[got[i].End:]
+			}
+			if applied != test.inputNew {
+				t.Errorf("got applied %q, but want %q", applied, test.inputNew)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package diffutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines around each change.
+const contextLines = 3
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+type edit struct {
+	kind editKind
+	line string
+}
+
+// Unified returns the unified diff between old and new of the file at the path.
+// It returns nil if there is no difference.
+func Unified(
+	path string,
+	old []byte,
+	new []byte,
+) []byte {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+
+	edits := diffLines(splitLines(old), splitLines(new))
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, h := range hunks(edits) {
+		writeHunk(&buf, edits, h)
+	}
+	return buf.Bytes()
+}
+
+// splitLines splits the content into lines, each of which keeps its line ending.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the shortest edit script by the Myers algorithm.
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	var trace [][]int
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if n <= x && m <= y {
+				break search
+			}
+		}
+	}
+
+	var reversed []edit
+	x, y := n, m
+	for d := len(trace) - 1; 0 < d; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for prevX < x && prevY < y {
+			reversed = append(reversed, edit{kind: editEqual, line: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			reversed = append(reversed, edit{kind: editInsert, line: b[y-1]})
+			y--
+		} else {
+			reversed = append(reversed, edit{kind: editDelete, line: a[x-1]})
+			x--
+		}
+	}
+	for 0 < x && 0 < y {
+		reversed = append(reversed, edit{kind: editEqual, line: a[x-1]})
+		x--
+		y--
+	}
+
+	edits := make([]edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// hunk is a range of edits, [start, end).
+type hunk struct {
+	start int
+	end   int
+}
+
+func hunks(edits []edit) []hunk {
+	var hs []hunk
+	for i, e := range edits {
+		if e.kind == editEqual {
+			continue
+		}
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1 + contextLines
+		if len(edits) < end {
+			end = len(edits)
+		}
+		if 0 < len(hs) && start <= hs[len(hs)-1].end {
+			hs[len(hs)-1].end = end
+			continue
+		}
+		hs = append(hs, hunk{start: start, end: end})
+	}
+	return hs
+}
+
+func writeHunk(
+	buf *bytes.Buffer,
+	edits []edit,
+	h hunk,
+) {
+	oldStart, newStart := 1, 1
+	for _, e := range edits[:h.start] {
+		if e.kind != editInsert {
+			oldStart++
+		}
+		if e.kind != editDelete {
+			newStart++
+		}
+	}
+	oldLen, newLen := 0, 0
+	for _, e := range edits[h.start:h.end] {
+		if e.kind != editInsert {
+			oldLen++
+		}
+		if e.kind != editDelete {
+			newLen++
+		}
+	}
+	_, _ = fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+
+	for _, e := range edits[h.start:h.end] {
+		switch e.kind {
+		case editEqual:
+			buf.WriteByte(' ')
+		case editDelete:
+			buf.WriteByte('-')
+		case editInsert:
+			buf.WriteByte('+')
+		}
+		buf.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		// The empty range starts at the line just before the change.
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
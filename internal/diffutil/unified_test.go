@@ -0,0 +1,77 @@
+package diffutil_test
+
+import (
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/diffutil"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputOld string
+		inputNew string
+		want     string
+	}{
+		{
+			name:     "no diff for the same contents",
+			inputOld: "a\nb\n",
+			inputNew: "a\nb\n",
+		},
+		{
+			name:     "a replaced line",
+			inputOld: "syntax = \"proto3\";\nenum enumName {\n}\n",
+			inputNew: "syntax = \"proto3\";\nenum EnumName {\n}\n",
+			want: `--- a/a.proto
++++ b/a.proto
+@@ -1,3 +1,3 @@
+ syntax = "proto3";
+-enum enumName {
++enum EnumName {
+ }
+`,
+		},
+		{
+			name:     "separate hunks for distant changes",
+			inputOld: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			inputNew: "0\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			want: `--- a/a.proto
++++ b/a.proto
+@@ -1,4 +1,4 @@
+-1
++0
+ 2
+ 3
+ 4
+@@ -7,4 +7,3 @@
+ 7
+ 8
+ 9
+-10
+`,
+		},
+		{
+			name:     "a missing newline at the end of file",
+			inputOld: "a\nb",
+			inputNew: "a\nb\n",
+			want: `--- a/a.proto
++++ b/a.proto
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := diffutil.Unified("a.proto", []byte(test.inputOld), []byte(test.inputNew))
+			if string(got) != test.want {
+				t.Errorf("got\n%s\nbut want\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/report"
+)
+
+// Baseline is a set of the known failures, which are suppressed in the later runs.
+//
+// A failure is identified by its rule ID, file and fingerprint. The fingerprint covers
+// the message and the text of the failed line without the line number,
+// so that the failure is still found after the lines above it are added or removed.
+type Baseline struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Entry represents the known failures which share the same identity.
+type Entry struct {
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Fingerprint string `json:"fingerprint"`
+	// Message is only for humans reviewing the baseline.
+	Message string `json:"message"`
+	// Count is the number of the failures. It's more than one when the same line appears repeatedly.
+	Count int `json:"count"`
+}
+
+type key struct {
+	ruleID      string
+	file        string
+	fingerprint string
+}
+
+func (e Entry) key() key {
+	return key{ruleID: e.RuleID, file: e.File, fingerprint: e.Fingerprint}
+}
+
+// fingerprinter computes the fingerprints, reading each file once.
+type fingerprinter struct {
+	lines map[string][]string
+}
+
+func newFingerprinter() *fingerprinter {
+	return &fingerprinter{
+		lines: make(map[string][]string),
+	}
+}
+
+func (f *fingerprinter) entry(failure report.Failure) Entry {
+	pos := failure.Pos()
+	lines, ok := f.lines[pos.Filename]
+	if !ok {
+		// A missing file results in the fingerprint without the line text.
+		data, _ := osutil.ReadFile(pos.Filename)
+		lines = strings.Split(string(data), "\n")
+		f.lines[pos.Filename] = lines
+	}
+	var line string
+	if 0 < pos.Line && pos.Line <= len(lines) {
+		line = strings.TrimSpace(lines[pos.Line-1])
+	}
+
+	h := sha256.New()
+	for _, part := range []string{failure.RuleID(), failure.Message(), line} {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+	return Entry{
+		RuleID:      failure.RuleID(),
+		File:        pos.Filename,
+		Fingerprint: hex.EncodeToString(h.Sum(nil))[:16],
+		Message:     failure.Message(),
+		Count:       1,
+	}
+}
+
+// New creates the baseline of the failures.
+func New(failures []report.Failure) *Baseline {
+	f := newFingerprinter()
+	indexes := make(map[key]int)
+	b := &Baseline{
+		Entries: []Entry{},
+	}
+	for _, failure := range failures {
+		e := f.entry(failure)
+		if i, ok := indexes[e.key()]; ok {
+			b.Entries[i].Count++
+			continue
+		}
+		indexes[e.key()] = len(b.Entries)
+		b.Entries = append(b.Entries, e)
+	}
+
+	// Keep the file stable to make the diff of the updates small.
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		ei, ej := b.Entries[i], b.Entries[j]
+		if ei.File != ej.File {
+			return ei.File < ej.File
+		}
+		if ei.RuleID != ej.RuleID {
+			return ei.RuleID < ej.RuleID
+		}
+		return ei.Fingerprint < ej.Fingerprint
+	})
+	return b
+}
+
+// Load reads the baseline file.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Write writes the baseline file.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Filter returns the failures which aren't in the baseline.
+// It also returns the stale entries, whose failures are no longer found in the linted files.
+// Their Count is the number of the failures not found.
+func (b *Baseline) Filter(
+	failures []report.Failure,
+	lintedFiles []string,
+) ([]report.Failure, []Entry) {
+	remaining := make(map[key]int)
+	for _, e := range b.Entries {
+		remaining[e.key()] += e.Count
+	}
+
+	f := newFingerprinter()
+	fs := []report.Failure{}
+	for _, failure := range failures {
+		k := f.entry(failure).key()
+		if 0 < remaining[k] {
+			remaining[k]--
+			continue
+		}
+		fs = append(fs, failure)
+	}
+
+	linted := make(map[string]bool)
+	for _, file := range lintedFiles {
+		linted[file] = true
+	}
+	var stale []Entry
+	for _, e := range b.Entries {
+		k := e.key()
+		// The entries of the files out of this run aren't known to be stale.
+		if !linted[e.File] || remaining[k] == 0 {
+			continue
+		}
+		e.Count = remaining[k]
+		remaining[k] = 0
+		stale = append(stale, e)
+	}
+	return fs, stale
+}
@@ -0,0 +1,180 @@
+package baseline_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/linter/baseline"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func failureAt(filename string, line int, ruleID string, message string) report.Failure {
+	return report.Failuref(
+		meta.Position{Filename: filename, Line: line, Column: 1},
+		ruleID,
+		string(rule.SeverityError),
+		"%s",
+		message,
+	)
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaseline_Filter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.proto")
+	other := filepath.Join(dir, "b.proto")
+
+	const original = `syntax = "proto3";
+enum enumName {}
+enum enumName {}
+message a {}
+`
+	known := []report.Failure{
+		failureAt(path, 2, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+		failureAt(path, 3, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+		failureAt(path, 4, "MESSAGE_NAMES_UPPER_CAMEL_CASE", "a"),
+	}
+	writeFile(t, path, original)
+	b := baseline.New(known)
+
+	tests := []struct {
+		name             string
+		inputContent     string
+		inputFailures    []report.Failure
+		inputLintedFiles []string
+		wantFailures     []report.Failure
+		wantStaleCounts  map[string]int
+	}{
+		{
+			name:             "suppress all known failures",
+			inputContent:     original,
+			inputFailures:    known,
+			inputLintedFiles: []string{path},
+			wantFailures:     []report.Failure{},
+		},
+		{
+			name: "suppress the known failures after the lines shifted",
+			inputContent: `syntax = "proto3";
+
+package p;
+
+enum enumName {}
+enum enumName {}
+message a {}
+`,
+			inputFailures: []report.Failure{
+				failureAt(path, 5, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+				failureAt(path, 6, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+				failureAt(path, 7, "MESSAGE_NAMES_UPPER_CAMEL_CASE", "a"),
+			},
+			inputLintedFiles: []string{path},
+			wantFailures:     []report.Failure{},
+		},
+		{
+			name: "report a new failure",
+			inputContent: original + `message b {}
+`,
+			inputFailures: append(known[:3:3],
+				failureAt(path, 5, "MESSAGE_NAMES_UPPER_CAMEL_CASE", "b"),
+			),
+			inputLintedFiles: []string{path},
+			wantFailures: []report.Failure{
+				failureAt(path, 5, "MESSAGE_NAMES_UPPER_CAMEL_CASE", "b"),
+			},
+		},
+		{
+			name: "report one more duplicate failure than the count",
+			inputContent: original + `enum enumName {}
+`,
+			inputFailures: append(known[:3:3],
+				failureAt(path, 5, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+			),
+			inputLintedFiles: []string{path},
+			wantFailures: []report.Failure{
+				failureAt(path, 5, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+			},
+		},
+		{
+			name: "report the fixed failures as stale",
+			inputContent: `syntax = "proto3";
+enum EnumName {}
+enum enumName {}
+message A {}
+`,
+			inputFailures: []report.Failure{
+				failureAt(path, 3, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+			},
+			inputLintedFiles: []string{path},
+			wantFailures:     []report.Failure{},
+			wantStaleCounts: map[string]int{
+				"ENUM_NAMES_UPPER_CAMEL_CASE":    1,
+				"MESSAGE_NAMES_UPPER_CAMEL_CASE": 1,
+			},
+		},
+		{
+			name:             "not report the entries of the files out of the run as stale",
+			inputContent:     original,
+			inputLintedFiles: []string{other},
+			wantFailures:     []report.Failure{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			writeFile(t, path, test.inputContent)
+
+			got, stale := b.Filter(test.inputFailures, test.inputLintedFiles)
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+
+			var gotStaleCounts map[string]int
+			for _, e := range stale {
+				if gotStaleCounts == nil {
+					gotStaleCounts = make(map[string]int)
+				}
+				gotStaleCounts[e.RuleID] += e.Count
+			}
+			if !reflect.DeepEqual(gotStaleCounts, test.wantStaleCounts) {
+				t.Errorf("got stale %v, but want %v", gotStaleCounts, test.wantStaleCounts)
+			}
+		})
+	}
+}
+
+func TestBaseline_WriteLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.proto")
+	writeFile(t, path, "enum enumName {}\nenum enumName {}\n")
+
+	b := baseline.New([]report.Failure{
+		failureAt(path, 2, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+		failureAt(path, 1, "ENUM_NAMES_UPPER_CAMEL_CASE", "enumName"),
+	})
+	if len(b.Entries) != 1 || b.Entries[0].Count != 2 {
+		t.Fatalf("got %v, but want one entry with count 2", b.Entries)
+	}
+
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := b.Write(baselinePath); err != nil {
+		t.Fatal(err)
+	}
+	got, err := baseline.Load(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("got %v, but want %v", got, b)
+	}
+}
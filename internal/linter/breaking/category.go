@@ -0,0 +1,43 @@
+// Package breaking detects the changes of the schema which break the compatibility with its previous version.
+package breaking
+
+import (
+	"fmt"
+)
+
+// Category is a kind of the compatibility which a change can break.
+// Each category includes the former ones, so that a change breaking the wire compatibility
+// breaks the JSON and source compatibility too.
+type Category int
+
+// Category constants.
+const (
+	// Wire is the compatibility of the binary encoding.
+	Wire Category = iota
+	// JSON is the compatibility of the JSON encoding.
+	JSON
+	// Source is the compatibility of the code generated from the schema.
+	Source
+)
+
+// String returns the category name.
+func (c Category) String() string {
+	switch c {
+	case Wire:
+		return "wire"
+	case JSON:
+		return "json"
+	default:
+		return "source"
+	}
+}
+
+// ParseCategory returns the category of the name.
+func ParseCategory(name string) (Category, error) {
+	for _, c := range []Category{Wire, JSON, Source} {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+	return Source, fmt.Errorf(`available categories are "wire", "json" and "source", but got %q`, name)
+}
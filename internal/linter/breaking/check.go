@@ -0,0 +1,335 @@
+package breaking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// The IDs of the checks, which are reported as the rule IDs of the failures.
+const (
+	MessageNoDelete        = "MESSAGE_NO_DELETE"
+	FieldNoDelete          = "FIELD_NO_DELETE"
+	FieldNoRenumber        = "FIELD_NO_RENUMBER"
+	FieldSameName          = "FIELD_SAME_NAME"
+	FieldSameJSONName      = "FIELD_SAME_JSON_NAME"
+	FieldSameType          = "FIELD_SAME_TYPE"
+	FieldSameLabel         = "FIELD_SAME_LABEL"
+	FieldSameOneof         = "FIELD_SAME_ONEOF"
+	EnumNoDelete           = "ENUM_NO_DELETE"
+	EnumValueNoDelete      = "ENUM_VALUE_NO_DELETE"
+	EnumValueSameName      = "ENUM_VALUE_SAME_NAME"
+	EnumValueSameNumber    = "ENUM_VALUE_SAME_NUMBER"
+	ServiceNoDelete        = "SERVICE_NO_DELETE"
+	RPCNoDelete            = "RPC_NO_DELETE"
+	RPCSameRequestType     = "RPC_SAME_REQUEST_TYPE"
+	RPCSameResponseType    = "RPC_SAME_RESPONSE_TYPE"
+	RPCSameClientStreaming = "RPC_SAME_CLIENT_STREAMING"
+	RPCSameServerStreaming = "RPC_SAME_SERVER_STREAMING"
+	ReservedRangeNoDelete  = "RESERVED_RANGE_NO_DELETE"
+	ReservedNameNoDelete   = "RESERVED_NAME_NO_DELETE"
+)
+
+type checker struct {
+	against  *Snapshot
+	current  *Snapshot
+	category Category
+
+	failures []report.Failure
+}
+
+// Check returns the failures of the changes from against to current which break the compatibility of the category.
+// A failure is placed at the changed element in current, or at its parent when the element was deleted.
+func Check(
+	against *Snapshot,
+	current *Snapshot,
+	category Category,
+) []report.Failure {
+	c := &checker{
+		against:  against,
+		current:  current,
+		category: category,
+	}
+	c.checkMessages()
+	c.checkEnums()
+	c.checkServices()
+
+	sort.SliceStable(c.failures, func(i, j int) bool {
+		pi, pj := c.failures[i].Pos(), c.failures[j].Pos()
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	return c.failures
+}
+
+func (c *checker) add(
+	pos meta.Position,
+	ruleID string,
+	category Category,
+	format string,
+	args ...interface{},
+) {
+	if c.category < category {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	c.failures = append(c.failures, report.Failuref(
+		pos,
+		ruleID,
+		string(rule.SeverityError),
+		"%s, which breaks %s compatibility.",
+		message,
+		category,
+	))
+}
+
+// deletedPos returns the position to report the deletion of an element.
+// It's the parent in current, or the package statement for a top-level element.
+// The position in against is the last resort.
+func (c *checker) deletedPos(pkg string, parent string, fallback meta.Position) meta.Position {
+	if m, ok := c.current.messages[parent]; ok {
+		return m.pos
+	}
+	if pos, ok := c.current.packages[pkg]; ok {
+		return pos
+	}
+	return fallback
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *checker) checkMessages() {
+	for _, name := range sortedKeys(c.against.messages) {
+		old := c.against.messages[name]
+		cur, ok := c.current.messages[name]
+		if !ok {
+			// Report only the outermost deleted message.
+			if _, parentExists := c.current.messages[old.parent]; old.parent == "" || parentExists {
+				c.add(c.deletedPos(old.pkg, old.parent, old.pos), MessageNoDelete, Source,
+					"Previously present message %q was deleted", name)
+			}
+			continue
+		}
+		c.checkFields(old, cur)
+		c.checkReserved(old.reserved, cur.reserved, cur.pos, "message", name)
+	}
+}
+
+func (c *checker) checkFields(old *message, cur *message) {
+	var numbers []int
+	for n := range old.fields {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	for _, n := range numbers {
+		of := old.fields[n]
+		nf, ok := cur.fields[n]
+		if !ok {
+			c.checkDeletedField(of, cur)
+			continue
+		}
+
+		if of.name != nf.name {
+			c.add(nf.pos, FieldSameName, Source,
+				"Field %d on message %q changed name from %q to %q", n, cur.name, of.name, nf.name)
+		}
+		if of.jsonName != nf.jsonName {
+			c.add(nf.pos, FieldSameJSONName, JSON,
+				"Field %d on message %q changed JSON name from %q to %q", n, cur.name, of.jsonName, nf.jsonName)
+		}
+		if of.typeName != nf.typeName {
+			c.add(nf.pos, FieldSameType, Wire,
+				"Field %d on message %q changed type from %q to %q", n, cur.name, of.typeName, nf.typeName)
+		}
+		if of.label != nf.label {
+			category := Source
+			if of.label == "repeated" || nf.label == "repeated" {
+				category = Wire
+			}
+			c.add(nf.pos, FieldSameLabel, category,
+				"Field %d on message %q changed label from %q to %q", n, cur.name, labelName(of.label), labelName(nf.label))
+		}
+		if of.oneof != nf.oneof {
+			c.add(nf.pos, FieldSameOneof, Wire,
+				"Field %d on message %q moved %s", n, cur.name, oneofMove(of.oneof, nf.oneof))
+		}
+	}
+}
+
+func oneofMove(from string, to string) string {
+	switch {
+	case from == "":
+		return fmt.Sprintf("into oneof %q", to)
+	case to == "":
+		return fmt.Sprintf("out of oneof %q", from)
+	default:
+		return fmt.Sprintf("from oneof %q to oneof %q", from, to)
+	}
+}
+
+func labelName(label string) string {
+	if label == "" {
+		return "singular"
+	}
+	return label
+}
+
+func (c *checker) checkDeletedField(of *field, cur *message) {
+	for _, nf := range cur.fields {
+		if nf.name == of.name {
+			c.add(nf.pos, FieldNoRenumber, Wire,
+				"Field %q on message %q changed number from %d to %d", of.name, cur.name, of.number, nf.number)
+			return
+		}
+	}
+
+	// The deletion is safe for the encoding whose identifier of the field is reserved.
+	category := Wire
+	if cur.reserved.hasNumber(of.number) {
+		category = JSON
+		if cur.reserved.hasName(of.name) {
+			category = Source
+		}
+	}
+	c.add(cur.pos, FieldNoDelete, category,
+		"Previously present field %d with name %q on message %q was deleted", of.number, of.name, cur.name)
+}
+
+func (c *checker) checkEnums() {
+	for _, name := range sortedKeys(c.against.enums) {
+		old := c.against.enums[name]
+		cur, ok := c.current.enums[name]
+		if !ok {
+			if _, parentExists := c.current.messages[old.parent]; old.parent == "" || parentExists {
+				c.add(c.deletedPos(old.pkg, old.parent, old.pos), EnumNoDelete, Source,
+					"Previously present enum %q was deleted", name)
+			}
+			continue
+		}
+		c.checkEnumValues(old, cur)
+		c.checkReserved(old.reserved, cur.reserved, cur.pos, "enum", name)
+	}
+}
+
+func (c *checker) checkEnumValues(old *enum, cur *enum) {
+	for _, valueName := range sortedKeys(old.values) {
+		ov := old.values[valueName]
+		if nv, ok := cur.values[valueName]; ok {
+			if ov.number != nv.number {
+				c.add(nv.pos, EnumValueSameNumber, Wire,
+					"Enum value %q on enum %q changed number from %d to %d", valueName, cur.name, ov.number, nv.number)
+			}
+			continue
+		}
+
+		renamed := false
+		for _, n := range sortedKeys(cur.values) {
+			if nv := cur.values[n]; nv.number == ov.number {
+				c.add(nv.pos, EnumValueSameName, JSON,
+					"Enum value %d on enum %q changed name from %q to %q", ov.number, cur.name, valueName, nv.name)
+				renamed = true
+				break
+			}
+		}
+		if renamed {
+			continue
+		}
+
+		category := Wire
+		if cur.reserved.hasNumber(ov.number) {
+			category = JSON
+			if cur.reserved.hasName(valueName) {
+				category = Source
+			}
+		}
+		c.add(cur.pos, EnumValueNoDelete, category,
+			"Previously present enum value %d with name %q on enum %q was deleted", ov.number, valueName, cur.name)
+	}
+}
+
+func (c *checker) checkReserved(
+	old reserved,
+	cur reserved,
+	pos meta.Position,
+	kind string,
+	name string,
+) {
+	for _, rng := range old.ranges {
+		if !cur.covers(rng) {
+			c.add(pos, ReservedRangeNoDelete, Wire,
+				"Previously present reserved range %s on %s %q was deleted", rangeString(rng), kind, name)
+		}
+	}
+	for _, n := range old.names {
+		if !cur.hasName(n.name) {
+			c.add(pos, ReservedNameNoDelete, JSON,
+				"Previously present reserved name %q on %s %q was deleted", n.name, kind, name)
+		}
+	}
+}
+
+func rangeString(r numberRange) string {
+	switch {
+	case r.begin == r.end:
+		return fmt.Sprint(r.begin)
+	case r.end == maxFieldNumber:
+		return fmt.Sprintf("[%d, max]", r.begin)
+	default:
+		return fmt.Sprintf("[%d, %d]", r.begin, r.end)
+	}
+}
+
+func (c *checker) checkServices() {
+	for _, name := range sortedKeys(c.against.services) {
+		old := c.against.services[name]
+		cur, ok := c.current.services[name]
+		if !ok {
+			c.add(c.deletedPos(old.pkg, "", old.pos), ServiceNoDelete, Wire,
+				"Previously present service %q was deleted", name)
+			continue
+		}
+
+		for _, rpcName := range sortedKeys(old.rpcs) {
+			or := old.rpcs[rpcName]
+			nr, ok := cur.rpcs[rpcName]
+			if !ok {
+				c.add(cur.pos, RPCNoDelete, Wire,
+					"Previously present RPC %q on service %q was deleted", rpcName, name)
+				continue
+			}
+			if or.requestType != nr.requestType {
+				c.add(nr.pos, RPCSameRequestType, Wire,
+					"RPC %q on service %q changed request type from %q to %q", rpcName, name, or.requestType, nr.requestType)
+			}
+			if or.responseType != nr.responseType {
+				c.add(nr.pos, RPCSameResponseType, Wire,
+					"RPC %q on service %q changed response type from %q to %q", rpcName, name, or.responseType, nr.responseType)
+			}
+			if or.clientStreaming != nr.clientStreaming {
+				c.add(nr.pos, RPCSameClientStreaming, Wire,
+					"RPC %q on service %q changed client streaming from %t to %t", rpcName, name, or.clientStreaming, nr.clientStreaming)
+			}
+			if or.serverStreaming != nr.serverStreaming {
+				c.add(nr.pos, RPCSameServerStreaming, Wire,
+					"RPC %q on service %q changed server streaming from %t to %t", rpcName, name, or.serverStreaming, nr.serverStreaming)
+			}
+		}
+	}
+}
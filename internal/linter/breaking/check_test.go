@@ -0,0 +1,399 @@
+package breaking_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/linter/breaking"
+)
+
+func snapshotOf(t *testing.T, contents ...string) *breaking.Snapshot {
+	var protos []*parser.Proto
+	for _, content := range contents {
+		proto, err := protoparser.Parse(strings.NewReader(content), protoparser.WithFilename("a.proto"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		protos = append(protos, proto)
+	}
+	return breaking.NewSnapshot(protos)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		inputAgainst  string
+		inputCurrent  string
+		inputCategory breaking.Category
+		wantRuleIDs   []string
+		wantMessages  []string
+	}{
+		{
+			name: "no changes",
+			inputAgainst: `syntax = "proto3";
+package p;
+message A { string a = 1; }
+`,
+			inputCurrent: `syntax = "proto3";
+package p;
+// Comments and the new elements don't break anything.
+message A {
+  string a = 1;
+  string b = 2;
+}
+message B {}
+`,
+			inputCategory: breaking.Source,
+		},
+		{
+			name: "the relative and absolute type names are the same",
+			inputAgainst: `syntax = "proto3";
+package p.v1;
+message A {
+  message B {}
+  B b = 1;
+  C c = 2;
+}
+message C {}
+`,
+			inputCurrent: `syntax = "proto3";
+package p.v1;
+message A {
+  message B {}
+  .p.v1.A.B b = 1;
+  v1.C c = 2;
+}
+message C {}
+`,
+			inputCategory: breaking.Source,
+		},
+		{
+			name: "deleted field",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 2;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  string a = 1;
+}
+`,
+			inputCategory: breaking.Wire,
+			wantRuleIDs:   []string{breaking.FieldNoDelete},
+			wantMessages: []string{
+				`Previously present field 2 with name "b" on message "A" was deleted, which breaks wire compatibility.`,
+			},
+		},
+		{
+			name: "deleted field with the reserved number only breaks json compatibility",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 2;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  reserved 2;
+  string a = 1;
+}
+`,
+			inputCategory: breaking.Wire,
+		},
+		{
+			name: "deleted field with the reserved number and name only breaks source compatibility",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+  string b = 2;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  reserved 2;
+  reserved "b";
+  string a = 1;
+}
+`,
+			inputCategory: breaking.Source,
+			wantRuleIDs:   []string{breaking.FieldNoDelete},
+		},
+		{
+			name: "renumbered and retyped fields",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+  int32 b = 2;
+  repeated string c = 3;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  string a = 4;
+  int64 b = 2;
+  string c = 3;
+}
+`,
+			inputCategory: breaking.Wire,
+			wantRuleIDs:   []string{breaking.FieldNoRenumber, breaking.FieldSameType, breaking.FieldSameLabel},
+		},
+		{
+			name: "renamed field breaks json and source compatibility",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a_b = 1;
+  string c = 2;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  string a_c = 1;
+  string d = 2 [json_name = "c"];
+}
+`,
+			inputCategory: breaking.Source,
+			wantRuleIDs: []string{
+				breaking.FieldSameName, breaking.FieldSameJSONName,
+				breaking.FieldSameName,
+			},
+		},
+		{
+			name: "field moved into oneof",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  oneof o {
+    string a = 1;
+  }
+}
+`,
+			inputCategory: breaking.Wire,
+			wantRuleIDs:   []string{breaking.FieldSameOneof},
+			wantMessages: []string{
+				`Field 1 on message "A" moved into oneof "o", which breaks wire compatibility.`,
+			},
+		},
+		{
+			name: "deleted messages and enums are reported at the outermost one",
+			inputAgainst: `syntax = "proto3";
+package p;
+message A {
+  message B {
+    enum C { C_UNSPECIFIED = 0; }
+  }
+}
+message D {
+  enum E { E_UNSPECIFIED = 0; }
+}
+`,
+			inputCurrent: `syntax = "proto3";
+package p;
+message D {}
+`,
+			inputCategory: breaking.Source,
+			wantRuleIDs:   []string{breaking.MessageNoDelete, breaking.EnumNoDelete},
+			wantMessages: []string{
+				`Previously present message "p.A" was deleted, which breaks source compatibility.`,
+				`Previously present enum "p.D.E" was deleted, which breaks source compatibility.`,
+			},
+		},
+		{
+			name: "enum value changes",
+			inputAgainst: `syntax = "proto3";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+  E_B = 2;
+  E_C = 3;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+enum E {
+  E_UNSPECIFIED = 0;
+  E_AA = 1;
+  E_B = 4;
+}
+`,
+			inputCategory: breaking.Source,
+			wantRuleIDs: []string{
+				breaking.EnumValueNoDelete,
+				breaking.EnumValueSameName,
+				breaking.EnumValueSameNumber,
+			},
+		},
+		{
+			name: "enum alias keeps the value",
+			inputAgainst: `syntax = "proto3";
+enum E {
+  option allow_alias = true;
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+  E_B = 1;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+enum E {
+  option allow_alias = true;
+  E_UNSPECIFIED = 0;
+  E_A = 1;
+}
+`,
+			inputCategory: breaking.Wire,
+		},
+		{
+			name: "changed RPC signatures",
+			inputAgainst: `syntax = "proto3";
+message A {}
+message B {}
+service S {
+  rpc Get(A) returns (A);
+  rpc Watch(A) returns (stream A);
+  rpc Delete(A) returns (A);
+}
+service T {}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {}
+message B {}
+service S {
+  rpc Get(B) returns (A);
+  rpc Watch(stream A) returns (A);
+}
+`,
+			inputCategory: breaking.Wire,
+			wantRuleIDs: []string{
+				breaking.RPCNoDelete,
+				breaking.RPCSameRequestType,
+				breaking.RPCSameClientStreaming,
+				breaking.RPCSameServerStreaming,
+				breaking.ServiceNoDelete,
+			},
+		},
+		{
+			name: "deleted reserved ranges and names",
+			inputAgainst: `syntax = "proto3";
+message A {
+  reserved 1 to 5, 10 to max;
+  reserved "a";
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  reserved 2, 3;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  reserved 1 to 2, 3 to 5, 10 to 20;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  reserved 2;
+}
+`,
+			inputCategory: breaking.Source,
+			wantRuleIDs: []string{
+				breaking.ReservedRangeNoDelete,
+				breaking.ReservedNameNoDelete,
+				breaking.ReservedRangeNoDelete,
+			},
+			wantMessages: []string{
+				`Previously present reserved range [10, max] on message "A" was deleted, which breaks wire compatibility.`,
+				`Previously present reserved name "a" on message "A" was deleted, which breaks json compatibility.`,
+				`Previously present reserved range 3 on enum "E" was deleted, which breaks wire compatibility.`,
+			},
+		},
+		{
+			name: "source changes are filtered by the json category",
+			inputAgainst: `syntax = "proto3";
+message A {
+  string a = 1;
+}
+message B {}
+`,
+			inputCurrent: `syntax = "proto3";
+message A {
+  string b = 1 [json_name = "a"];
+}
+`,
+			inputCategory: breaking.JSON,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := breaking.Check(
+				snapshotOf(t, test.inputAgainst),
+				snapshotOf(t, test.inputCurrent),
+				test.inputCategory,
+			)
+
+			var gotRuleIDs []string
+			var gotMessages []string
+			for _, f := range got {
+				gotRuleIDs = append(gotRuleIDs, f.RuleID())
+				gotMessages = append(gotMessages, f.Message())
+			}
+			if !reflect.DeepEqual(gotRuleIDs, test.wantRuleIDs) {
+				t.Errorf("got %v, but want %v", gotMessages, test.wantRuleIDs)
+			}
+			if test.wantMessages != nil && !reflect.DeepEqual(gotMessages, test.wantMessages) {
+				t.Errorf("got %v, but want %v", gotMessages, test.wantMessages)
+			}
+		})
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputName string
+		want      breaking.Category
+		wantErr   bool
+	}{
+		{
+			name:      "wire",
+			inputName: "wire",
+			want:      breaking.Wire,
+		},
+		{
+			name:      "json",
+			inputName: "json",
+			want:      breaking.JSON,
+		},
+		{
+			name:      "source",
+			inputName: "source",
+			want:      breaking.Source,
+		},
+		{
+			name:      "unknown",
+			inputName: "file",
+			want:      breaking.Source,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := breaking.ParseCategory(test.inputName)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got err %v, but want err %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %v, but want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package breaking
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+)
+
+// LoadFiles parses the files into a snapshot.
+func LoadFiles(fs []file.ProtoFile, verbose bool) (*Snapshot, error) {
+	var protos []*parser.Proto
+	for _, f := range fs {
+		proto, err := f.Parse(verbose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.DisplayPath(), err)
+		}
+		protos = append(protos, proto)
+	}
+	return NewSnapshot(protos), nil
+}
+
+// LoadDir parses the .proto files under the directory, like a copy of the previous version.
+func LoadDir(dir string, verbose bool) (*Snapshot, error) {
+	protoSet, err := file.NewProtoSet([]string{dir})
+	if err != nil {
+		return nil, err
+	}
+	return LoadFiles(protoSet.ProtoFiles(), verbose)
+}
+
+// LoadGitRef parses the .proto files under the paths at the git ref.
+// The paths are relative to the working directory as well as the ones given to git.
+func LoadGitRef(ref string, paths []string, verbose bool) (*Snapshot, error) {
+	out, err := git(append([]string{"ls-tree", "-r", "--name-only", ref, "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var protos []*parser.Proto
+	for _, path := range strings.Split(string(out), "\n") {
+		if !strings.HasSuffix(path, ".proto") {
+			continue
+		}
+		if !strings.HasPrefix(path, "../") {
+			path = "./" + path
+		}
+		content, err := git("show", ref+":"+path)
+		if err != nil {
+			return nil, err
+		}
+
+		displayPath := ref + ":" + strings.TrimPrefix(path, "./")
+		proto, err := protoparser.Parse(
+			bytes.NewReader(content),
+			protoparser.WithFilename(displayPath),
+			protoparser.WithBodyIncludingComments(true),
+			protoparser.WithDebug(verbose),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", displayPath, err)
+		}
+		protos = append(protos, proto)
+	}
+	return NewSnapshot(protos), nil
+}
+
+// LoadFile parses the file into a snapshot. It returns false when the file doesn't exist.
+func LoadFile(path string, verbose bool) (*Snapshot, bool, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	s, err := LoadFiles([]file.ProtoFile{file.NewProtoFile(path, path)}, verbose)
+	if err != nil {
+		return nil, false, err
+	}
+	return s, true, nil
+}
+
+// LoadGitFile parses the file at the git ref into a snapshot. It returns false when the file doesn't exist
+// at the ref, like a new file, or when the file is out of any git repository.
+func LoadGitFile(ref string, path string, verbose bool) (*Snapshot, bool, error) {
+	dir := filepath.Dir(path)
+	if _, err := git("-C", dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, false, nil
+	}
+	if _, err := git("-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, false, fmt.Errorf("invalid git ref %q: %w", ref, err)
+	}
+
+	object := ref + ":./" + filepath.Base(path)
+	if _, err := git("-C", dir, "cat-file", "-e", object); err != nil {
+		return nil, false, nil
+	}
+	content, err := git("-C", dir, "show", object)
+	if err != nil {
+		return nil, false, err
+	}
+
+	displayPath := ref + ":" + filepath.ToSlash(path)
+	proto, err := protoparser.Parse(
+		bytes.NewReader(content),
+		protoparser.WithFilename(displayPath),
+		protoparser.WithBodyIncludingComments(true),
+		protoparser.WithDebug(verbose),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse %s: %w", displayPath, err)
+	}
+	return NewSnapshot([]*parser.Proto{proto}), true, nil
+}
+
+// ResolveGitRef returns the commit which the git ref points to in the repository of the working directory.
+func ResolveGitRef(ref string) (string, error) {
+	out, err := git("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("invalid git ref %q: %w", ref, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DigestDir hashes the paths and the contents of the .proto files under the directory.
+func DigestDir(dir string) (string, error) {
+	protoSet, err := file.NewProtoSet([]string{dir})
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, f := range protoSet.ProtoFiles() {
+		content, err := os.ReadFile(f.Path())
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(f.Path()), len(content))
+		_, _ = h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func git(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
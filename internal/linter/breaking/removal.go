@@ -0,0 +1,92 @@
+package breaking
+
+import (
+	"sort"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Removal is a field or an enum value deleted from a message or an enum which still exists,
+// leaving its number or its name unreserved.
+type Removal struct {
+	// Parent is the fully-qualified name of the message or the enum.
+	Parent string
+	// ParentPos is the position of the message or the enum in current.
+	ParentPos   meta.Position
+	IsEnumValue bool
+	Name        string
+	Number      int
+	// NumberReserved and NameReserved report whether current reserves the number and the name.
+	NumberReserved bool
+	NameReserved   bool
+}
+
+// Removals returns the fields and the enum values deleted from against to current whose numbers or names
+// are not reserved. A field or an enum value is deleted when neither its number nor its name is used
+// in current, so the renamed and renumbered ones are left out. The deleted messages and enums are left
+// out too. The removals are sorted by the parents and the numbers.
+func Removals(against *Snapshot, current *Snapshot) []Removal {
+	var removals []Removal
+	add := func(r Removal) {
+		if !r.NumberReserved || !r.NameReserved {
+			removals = append(removals, r)
+		}
+	}
+
+	for _, name := range sortedKeys(against.messages) {
+		cur, ok := current.messages[name]
+		if !ok {
+			continue
+		}
+		names := make(map[string]bool)
+		for _, f := range cur.fields {
+			names[f.name] = true
+		}
+		for _, of := range against.messages[name].fields {
+			if _, ok := cur.fields[of.number]; ok || names[of.name] {
+				continue
+			}
+			add(Removal{
+				Parent:         name,
+				ParentPos:      cur.pos,
+				Name:           of.name,
+				Number:         of.number,
+				NumberReserved: cur.reserved.hasNumber(of.number),
+				NameReserved:   cur.reserved.hasName(of.name),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(against.enums) {
+		cur, ok := current.enums[name]
+		if !ok {
+			continue
+		}
+		numbers := make(map[int]bool)
+		for _, v := range cur.values {
+			numbers[v.number] = true
+		}
+		for _, ov := range against.enums[name].values {
+			if _, ok := cur.values[ov.name]; ok || numbers[ov.number] {
+				continue
+			}
+			add(Removal{
+				Parent:         name,
+				ParentPos:      cur.pos,
+				IsEnumValue:    true,
+				Name:           ov.name,
+				Number:         ov.number,
+				NumberReserved: cur.reserved.hasNumber(ov.number),
+				NameReserved:   cur.reserved.hasName(ov.name),
+			})
+		}
+	}
+
+	sort.SliceStable(removals, func(i, j int) bool {
+		if removals[i].Parent != removals[j].Parent {
+			return removals[i].Parent < removals[j].Parent
+		}
+		return removals[i].Number < removals[j].Number
+	})
+	return removals
+}
@@ -0,0 +1,161 @@
+package breaking_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/breaking"
+)
+
+func TestRemovals(t *testing.T) {
+	tests := []struct {
+		name         string
+		inputAgainst string
+		inputCurrent string
+		wantRemovals []string
+	}{
+		{
+			name: "renamed, renumbered and reserved fields are not removals",
+			inputAgainst: `syntax = "proto3";
+package p;
+message A {
+  string a = 1;
+  string b = 2;
+  string c = 3;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  E_ONE = 1;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+package p;
+message A {
+  reserved 3;
+  reserved "c";
+  string a2 = 1;
+  string b = 4;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  E_UNO = 1;
+}
+`,
+		},
+		{
+			name: "deleted fields and enum values without the reservations",
+			inputAgainst: `syntax = "proto3";
+package p;
+message A {
+  string a = 1;
+  string b = 2;
+  string c = 3;
+}
+message B {
+  string a = 1;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+  E_ONE = 1;
+}
+`,
+			inputCurrent: `syntax = "proto3";
+package p;
+message A {
+  reserved 2;
+  reserved "c";
+  string a = 1;
+}
+enum E {
+  E_UNSPECIFIED = 0;
+}
+`,
+			wantRemovals: []string{
+				"p.A b 2 number:true name:false",
+				"p.A c 3 number:false name:true",
+				"p.E E_ONE 1 number:false name:false",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			removals := breaking.Removals(snapshotOf(t, test.inputAgainst), snapshotOf(t, test.inputCurrent))
+
+			var got []string
+			for _, r := range removals {
+				got = append(got, fmt.Sprintf("%s %s %d number:%t name:%t", r.Parent, r.Name, r.Number, r.NumberReserved, r.NameReserved))
+			}
+			if !reflect.DeepEqual(got, test.wantRemovals) {
+				t.Errorf("got %v, but want %v", got, test.wantRemovals)
+			}
+		})
+	}
+}
+
+func TestLoadGitFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.proto")
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	if _, ok, err := breaking.LoadGitFile("HEAD", path, false); ok || err != nil {
+		t.Errorf("got ok %t and err %v out of a git repository, but want false and nil", ok, err)
+	}
+
+	run("init", "-q")
+	if err := os.WriteFile(path, []byte(`syntax = "proto3";
+package p;
+message A {
+  string a = 1;
+  string b = 2;
+}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.proto")
+	run("commit", "-q", "-m", "init")
+	if err := os.WriteFile(path, []byte(`syntax = "proto3";
+package p;
+message A {
+  string a = 1;
+}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	against, ok, err := breaking.LoadGitFile("HEAD", path, false)
+	if !ok || err != nil {
+		t.Fatalf("got ok %t and err %v, but want true and nil", ok, err)
+	}
+	removals := breaking.Removals(against, snapshotOf(t, `syntax = "proto3";
+package p;
+message A {
+  string a = 1;
+}
+`))
+	if len(removals) != 1 || removals[0].Name != "b" {
+		t.Errorf("got %v, but want the removal of b", removals)
+	}
+
+	if _, ok, err := breaking.LoadGitFile("HEAD", filepath.Join(dir, "new.proto"), false); ok || err != nil {
+		t.Errorf("got ok %t and err %v for a new file, but want false and nil", ok, err)
+	}
+	if _, _, err := breaking.LoadGitFile("unknown", path, false); err == nil {
+		t.Errorf("got err nil for an unknown ref, but want err")
+	}
+}
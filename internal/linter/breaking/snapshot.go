@@ -0,0 +1,424 @@
+package breaking
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// maxFieldNumber is the number which "max" means in the reserved ranges.
+const maxFieldNumber = 536870911
+
+// Snapshot is a version of the schema made of the .proto files.
+// The elements are keyed by their fully-qualified names.
+type Snapshot struct {
+	messages map[string]*message
+	enums    map[string]*enum
+	services map[string]*service
+	// packages keeps the position of the first package statement of each package
+	// to report the deletion of the top-level elements.
+	packages map[string]meta.Position
+}
+
+type numberRange struct {
+	begin int
+	end   int
+	pos   meta.Position
+}
+
+type reservedName struct {
+	name string
+	pos  meta.Position
+}
+
+// reserved is the reserved numbers and names of a message or an enum.
+type reserved struct {
+	ranges []numberRange
+	names  []reservedName
+}
+
+func (r reserved) hasNumber(n int) bool {
+	for _, rng := range r.ranges {
+		if rng.begin <= n && n <= rng.end {
+			return true
+		}
+	}
+	return false
+}
+
+func (r reserved) hasName(name string) bool {
+	for _, n := range r.names {
+		if n.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether the range is included by the union of the reserved ranges.
+func (r reserved) covers(target numberRange) bool {
+	ranges := append([]numberRange(nil), r.ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].begin < ranges[j].begin
+	})
+	next := target.begin
+	for _, rng := range ranges {
+		if next < rng.begin {
+			break
+		}
+		if next <= rng.end {
+			next = rng.end + 1
+		}
+		if target.end < next {
+			return true
+		}
+	}
+	return target.end < next
+}
+
+type message struct {
+	name     string
+	pkg      string
+	parent   string
+	pos      meta.Position
+	fields   map[int]*field
+	reserved reserved
+}
+
+type field struct {
+	name     string
+	jsonName string
+	number   int
+	typeName string
+	label    string
+	oneof    string
+	pos      meta.Position
+}
+
+type enum struct {
+	name     string
+	pkg      string
+	parent   string
+	pos      meta.Position
+	values   map[string]*enumValue
+	reserved reserved
+}
+
+type enumValue struct {
+	name   string
+	number int
+	pos    meta.Position
+}
+
+type service struct {
+	name string
+	pkg  string
+	pos  meta.Position
+	rpcs map[string]*rpc
+}
+
+type rpc struct {
+	name            string
+	requestType     string
+	responseType    string
+	clientStreaming bool
+	serverStreaming bool
+	pos             meta.Position
+}
+
+// NewSnapshot creates a new Snapshot of the parsed .proto files.
+func NewSnapshot(protos []*parser.Proto) *Snapshot {
+	s := &Snapshot{
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+		services: make(map[string]*service),
+		packages: make(map[string]meta.Position),
+	}
+	for _, proto := range protos {
+		pkg := packageOf(proto)
+		if p, ok := packageStatementOf(proto); ok {
+			if _, exists := s.packages[pkg]; !exists {
+				s.packages[pkg] = p.Meta.Pos
+			}
+		}
+		s.addBody(pkg, pkg, "", proto.ProtoBody)
+	}
+	s.resolveTypes()
+	return s
+}
+
+func packageStatementOf(proto *parser.Proto) (*parser.Package, bool) {
+	for _, v := range proto.ProtoBody {
+		if p, ok := v.(*parser.Package); ok {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func packageOf(proto *parser.Proto) string {
+	if p, ok := packageStatementOf(proto); ok {
+		return p.Name
+	}
+	return ""
+}
+
+func join(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func (s *Snapshot) addBody(pkg string, scope string, parent string, body []parser.Visitee) {
+	for _, v := range body {
+		switch e := v.(type) {
+		case *parser.Message:
+			s.addMessage(pkg, scope, parent, e.MessageName, e.Meta.Pos, e.MessageBody)
+		case *parser.Enum:
+			s.addEnum(pkg, parent, join(scope, e.EnumName), e)
+		case *parser.Service:
+			s.addService(pkg, join(scope, e.ServiceName), e)
+		}
+	}
+}
+
+func (s *Snapshot) addMessage(
+	pkg string,
+	scope string,
+	parent string,
+	name string,
+	pos meta.Position,
+	body []parser.Visitee,
+) {
+	m := &message{
+		name:   join(scope, name),
+		pkg:    pkg,
+		parent: parent,
+		pos:    pos,
+		fields: make(map[int]*field),
+	}
+	s.messages[m.name] = m
+
+	add := func(f *field) {
+		if f.number != 0 {
+			m.fields[f.number] = f
+		}
+	}
+	for _, v := range body {
+		switch e := v.(type) {
+		case *parser.Field:
+			add(newField(e.FieldName, e.FieldNumber, e.Type, labelOf(e), "", e.FieldOptions, e.Meta.Pos))
+		case *parser.MapField:
+			typeName := "map<" + e.KeyType + ", " + e.Type + ">"
+			add(newField(e.MapName, e.FieldNumber, typeName, "", "", e.FieldOptions, e.Meta.Pos))
+		case *parser.Oneof:
+			for _, f := range e.OneofFields {
+				add(newField(f.FieldName, f.FieldNumber, f.Type, "", e.OneofName, f.FieldOptions, f.Meta.Pos))
+			}
+		case *parser.GroupField:
+			label := "optional"
+			if e.IsRepeated {
+				label = "repeated"
+			} else if e.IsRequired {
+				label = "required"
+			}
+			groupName := join(m.name, e.GroupName)
+			add(newField(strings.ToLower(e.GroupName), e.FieldNumber, "."+groupName, label, "", nil, e.Meta.Pos))
+			s.addMessage(pkg, m.name, m.name, e.GroupName, e.Meta.Pos, e.MessageBody)
+		case *parser.Reserved:
+			m.reserved = appendReserved(m.reserved, e)
+		}
+	}
+	s.addBody(pkg, m.name, m.name, body)
+}
+
+func labelOf(f *parser.Field) string {
+	switch {
+	case f.IsRepeated:
+		return "repeated"
+	case f.IsRequired:
+		return "required"
+	case f.IsOptional:
+		return "optional"
+	default:
+		return ""
+	}
+}
+
+func newField(
+	name string,
+	number string,
+	typeName string,
+	label string,
+	oneof string,
+	options []*parser.FieldOption,
+	pos meta.Position,
+) *field {
+	n, _ := parseNumber(number)
+	jsonName := defaultJSONName(name)
+	for _, o := range options {
+		if o.OptionName == "json_name" {
+			jsonName = unquote(o.Constant)
+		}
+	}
+	return &field{
+		name:     name,
+		jsonName: jsonName,
+		number:   n,
+		typeName: typeName,
+		label:    label,
+		oneof:    oneof,
+		pos:      pos,
+	}
+}
+
+func (s *Snapshot) addEnum(pkg string, parent string, name string, e *parser.Enum) {
+	en := &enum{
+		name:   name,
+		pkg:    pkg,
+		parent: parent,
+		pos:    e.Meta.Pos,
+		values: make(map[string]*enumValue),
+	}
+	for _, v := range e.EnumBody {
+		switch b := v.(type) {
+		case *parser.EnumField:
+			n, _ := parseNumber(b.Number)
+			en.values[b.Ident] = &enumValue{
+				name:   b.Ident,
+				number: n,
+				pos:    b.Meta.Pos,
+			}
+		case *parser.Reserved:
+			en.reserved = appendReserved(en.reserved, b)
+		}
+	}
+	s.enums[name] = en
+}
+
+func (s *Snapshot) addService(pkg string, name string, e *parser.Service) {
+	svc := &service{
+		name: name,
+		pkg:  pkg,
+		pos:  e.Meta.Pos,
+		rpcs: make(map[string]*rpc),
+	}
+	for _, v := range e.ServiceBody {
+		r, ok := v.(*parser.RPC)
+		if !ok || r.RPCRequest == nil || r.RPCResponse == nil {
+			continue
+		}
+		svc.rpcs[r.RPCName] = &rpc{
+			name:            r.RPCName,
+			requestType:     r.RPCRequest.MessageType,
+			responseType:    r.RPCResponse.MessageType,
+			clientStreaming: r.RPCRequest.IsStream,
+			serverStreaming: r.RPCResponse.IsStream,
+			pos:             r.Meta.Pos,
+		}
+	}
+	s.services[name] = svc
+}
+
+func appendReserved(r reserved, e *parser.Reserved) reserved {
+	for _, rng := range e.Ranges {
+		begin, err := parseNumber(rng.Begin)
+		if err != nil {
+			continue
+		}
+		end := begin
+		if rng.End == "max" {
+			end = maxFieldNumber
+		} else if rng.End != "" {
+			end, err = parseNumber(rng.End)
+			if err != nil {
+				continue
+			}
+		}
+		r.ranges = append(r.ranges, numberRange{begin: begin, end: end, pos: e.Meta.Pos})
+	}
+	for _, name := range e.FieldNames {
+		r.names = append(r.names, reservedName{name: unquote(name), pos: e.Meta.Pos})
+	}
+	return r
+}
+
+func parseNumber(s string) (int, error) {
+	n, err := strconv.ParseInt(s, 0, 64)
+	return int(n), err
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"'`)
+}
+
+// defaultJSONName converts the field name to lowerCamelCase like protoc does.
+func defaultJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// resolveTypes makes the type names of the fields and the RPCs fully-qualified,
+// so that the same type written differently is compared as equal.
+func (s *Snapshot) resolveTypes() {
+	for _, m := range s.messages {
+		for _, f := range m.fields {
+			if strings.HasPrefix(f.typeName, "map<") {
+				kv := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(f.typeName, "map<"), ">"), ",", 2)
+				if len(kv) == 2 {
+					f.typeName = "map<" + strings.TrimSpace(kv[0]) + ", " + s.resolve(m.name, strings.TrimSpace(kv[1])) + ">"
+				}
+				continue
+			}
+			f.typeName = s.resolve(m.name, f.typeName)
+		}
+	}
+	for _, svc := range s.services {
+		for _, r := range svc.rpcs {
+			r.requestType = s.resolve(svc.pkg, r.requestType)
+			r.responseType = s.resolve(svc.pkg, r.responseType)
+		}
+	}
+}
+
+// resolve finds the type in the scope and its ancestors.
+// The type defined out of the snapshot, like the scalar types and the imported ones, is kept as is.
+func (s *Snapshot) resolve(scope string, typeName string) string {
+	if strings.HasPrefix(typeName, ".") {
+		return strings.TrimPrefix(typeName, ".")
+	}
+	for {
+		candidate := join(scope, typeName)
+		if _, ok := s.messages[candidate]; ok {
+			return candidate
+		}
+		if _, ok := s.enums[candidate]; ok {
+			return candidate
+		}
+		if scope == "" {
+			return typeName
+		}
+		if i := strings.LastIndex(scope, "."); 0 <= i {
+			scope = scope[:i]
+		} else {
+			scope = ""
+		}
+	}
+}
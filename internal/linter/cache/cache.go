@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/linter/report"
+)
+
+const fileName = "lint.json"
+
+// Cache stores the failures of each linted file so that unchanged files can be skipped.
+//
+// The whole cache is discarded when the key differs from the stored one.
+// The key must cover everything that affects the results other than the file content,
+// like the protolint version, the config and the rule set.
+type Cache struct {
+	path string
+	key  string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	ContentHash string    `json:"content_hash"`
+	Failures    []failure `json:"failures"`
+}
+
+type failure struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Edits    []edit `json:"edits,omitempty"`
+}
+
+type edit struct {
+	Pos     meta.Position `json:"pos"`
+	End     meta.Position `json:"end"`
+	NewText string        `json:"new_text"`
+}
+
+type content struct {
+	Key     string           `json:"key"`
+	Entries map[string]entry `json:"entries"`
+}
+
+// Key hashes the given parts into a cache key.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashContent hashes the file content.
+func HashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Load reads the cache stored in the directory.
+// A missing, unreadable or outdated cache results in an empty one.
+func Load(
+	dir string,
+	key string,
+) *Cache {
+	c := &Cache{
+		path:    filepath.Join(dir, fileName),
+		key:     key,
+		entries: make(map[string]entry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return c
+	}
+	var stored content
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return c
+	}
+	if stored.Key != key || stored.Entries == nil {
+		return c
+	}
+	c.entries = stored.Entries
+	return c
+}
+
+// Lookup returns the cached failures when the file content is unchanged.
+func (c *Cache) Lookup(
+	displayPath string,
+	contentHash string,
+) ([]report.Failure, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[displayPath]
+	c.mu.Unlock()
+	if !ok || e.ContentHash != contentHash {
+		return nil, false
+	}
+
+	fs := []report.Failure{}
+	for _, f := range e.Failures {
+		failure := report.Failuref(
+			meta.Position{
+				Filename: f.Filename,
+				Offset:   f.Offset,
+				Line:     f.Line,
+				Column:   f.Column,
+			},
+			f.RuleID,
+			f.Severity,
+			"%s",
+			f.Message,
+		)
+		if 0 < len(f.Edits) {
+			var edits []report.SuggestedEdit
+			for _, e := range f.Edits {
+				edits = append(edits, report.SuggestedEdit(e))
+			}
+			failure = failure.WithSuggestedEdits(edits...)
+		}
+		fs = append(fs, failure)
+	}
+	return fs, true
+}
+
+// Store records the failures of the file.
+func (c *Cache) Store(
+	displayPath string,
+	contentHash string,
+	failures []report.Failure,
+) {
+	e := entry{
+		ContentHash: contentHash,
+		Failures:    []failure{},
+	}
+	for _, f := range failures {
+		var edits []edit
+		for _, e := range f.SuggestedEdits() {
+			edits = append(edits, edit(e))
+		}
+		e.Failures = append(e.Failures, failure{
+			Filename: f.Pos().Filename,
+			Offset:   f.Pos().Offset,
+			Line:     f.Pos().Line,
+			Column:   f.Pos().Column,
+			RuleID:   f.RuleID(),
+			Severity: f.Severity(),
+			Message:  f.Message(),
+			Edits:    edits,
+		})
+	}
+
+	c.mu.Lock()
+	c.entries[displayPath] = e
+	c.mu.Unlock()
+}
+
+// Save writes the cache to the disk.
+// Entries for files which no longer exist are dropped.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.entries {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(c.entries, path)
+		}
+	}
+
+	data, err := json.Marshal(content{
+		Key:     c.key,
+		Entries: c.entries,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
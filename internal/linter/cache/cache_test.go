@@ -0,0 +1,86 @@
+package cache_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/linter/cache"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+func TestCache(t *testing.T) {
+	failures := []report.Failure{
+		report.Failuref(
+			meta.Position{
+				Filename: "cache_test.go",
+				Offset:   10,
+				Line:     2,
+				Column:   3,
+			},
+			"ENUM_NAMES_UPPER_CAMEL_CASE",
+			string(rule.SeverityWarning),
+			`Enum name "a" must be UpperCamelCase like "A"`,
+		),
+	}
+	hash := cache.HashContent([]byte("content"))
+
+	tests := []struct {
+		name         string
+		inputKey     string
+		inputPath    string
+		inputHash    string
+		wantFailures []report.Failure
+		wantHit      bool
+	}{
+		{
+			name:         "hit for the same key, path and content",
+			inputKey:     "key",
+			inputPath:    "cache_test.go",
+			inputHash:    hash,
+			wantFailures: failures,
+			wantHit:      true,
+		},
+		{
+			name:      "miss for a changed content",
+			inputKey:  "key",
+			inputPath: "cache_test.go",
+			inputHash: cache.HashContent([]byte("changed")),
+		},
+		{
+			name:      "miss for another path",
+			inputKey:  "key",
+			inputPath: "other.go",
+			inputHash: hash,
+		},
+		{
+			name:      "miss for a changed key",
+			inputKey:  "other",
+			inputPath: "cache_test.go",
+			inputHash: hash,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			c := cache.Load(dir, "key")
+			c.Store("cache_test.go", hash, failures)
+			if err := c.Save(); err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			got, ok := cache.Load(dir, test.inputKey).Lookup(test.inputPath, test.inputHash)
+			if ok != test.wantHit {
+				t.Errorf("got hit %v, but want %v", ok, test.wantHit)
+			}
+			if !reflect.DeepEqual(got, test.wantFailures) {
+				t.Errorf("got %v, but want %v", got, test.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+// CustomRule represents a rule declared in the configuration, which checks the names of the target elements
+// or evaluates an expression over them.
+type CustomRule struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// ID is the rule ID, which the disable comments and the rules section refer to.
+	ID string `yaml:"id" json:"id" toml:"id"`
+	// Purpose is the description shown by the list command.
+	Purpose string `yaml:"purpose" json:"purpose" toml:"purpose"`
+	// Target is the kind of the elements to check, like message or rpc.
+	Target string `yaml:"target" json:"target" toml:"target"`
+	// Pattern is the regular expression which the names must match.
+	Pattern string `yaml:"pattern" json:"pattern" toml:"pattern"`
+	// NotPattern is the regular expression which the names must not match.
+	NotPattern string `yaml:"not_pattern" json:"not_pattern" toml:"not_pattern"`
+	// AllowWords are the words one of which the names must have at WordPosition.
+	AllowWords []string `yaml:"allow_words" json:"allow_words" toml:"allow_words"`
+	// DenyWords are the words which the names must not have at WordPosition.
+	DenyWords []string `yaml:"deny_words" json:"deny_words" toml:"deny_words"`
+	// WordPosition is either first, last or any. The default is any.
+	WordPosition string `yaml:"word_position" json:"word_position" toml:"word_position"`
+	// When is the expression which limits the rule to the elements it holds for.
+	When string `yaml:"when" json:"when" toml:"when"`
+	// Expr is the expression which must hold for the elements.
+	Expr string `yaml:"expr" json:"expr" toml:"expr"`
+	// Message is the text/template of the failure message.
+	Message string `yaml:"message" json:"message" toml:"message"`
+}
+
+// CustomRules represents the rules declared in the configuration.
+type CustomRules []CustomRule
+
+// IDs returns the IDs of the rules.
+func (cs CustomRules) IDs() []string {
+	var ids []string
+	for _, c := range cs {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
@@ -0,0 +1,483 @@
+package config
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// builtinPrefix is the prefix of the extends entries which refer to the built-in presets.
+const builtinPrefix = "builtin:"
+
+//go:embed presets/*.yaml
+var presets embed.FS
+
+// Extends represents the configs which the config extends, in the order they are applied.
+// Each one is the path to the config file, which is resolved against the directory of the extending file,
+// or the name of a built-in preset prefixed with "builtin:".
+type Extends []string
+
+// BuiltinPresets returns the names of the built-in presets.
+func BuiltinPresets() []string {
+	entries, err := presets.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), externalConfigFileExtension))
+	}
+	return names
+}
+
+// ResolveLint returns the lint section of the config file, whose extends are resolved.
+// The returned map is what the config is decoded from.
+func ResolveLint(filePath string) (map[string]interface{}, error) {
+	return resolveLint(filePath, nil)
+}
+
+// resolveExtends replaces the lint section of the config with the one whose extends are resolved.
+func (c *ExternalConfig) resolveExtends() error {
+	lint, err := ResolveLint(c.SourcePath)
+	if err != nil {
+		return err
+	}
+	resolved, err := decodeResolvedLint(lint)
+	if err != nil {
+		return fmt.Errorf("failed to decode the config extended by %s: %w", c.SourcePath, err)
+	}
+	c.Lint = resolved
+	return nil
+}
+
+// decodeResolvedLint decodes the lint section whose extends are resolved.
+// It's decoded as YAML regardless of the format of the files. The decoding isn't strict,
+// because each file has been checked by its own loader, which ignores the unknown fields in package.json
+// and pyproject.toml.
+func decodeResolvedLint(lint map[string]interface{}) (Lint, error) {
+	data, err := yaml.Marshal(map[string]interface{}{"lint": lint})
+	if err != nil {
+		return Lint{}, err
+	}
+	var config ExternalConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return Lint{}, err
+	}
+	return config.Lint, nil
+}
+
+// resolveLint loads the lint section of the config, and merges it on the configs it extends.
+// The chain holds the configs being resolved to detect the cycles.
+func resolveLint(
+	name string,
+	chain []string,
+) (map[string]interface{}, error) {
+	if !strings.HasPrefix(name, builtinPrefix) {
+		name = filepath.Clean(name)
+	}
+	for _, c := range chain {
+		if c == name {
+			return nil, fmt.Errorf("found the cycle of extends: %s", strings.Join(append(chain, name), " -> "))
+		}
+	}
+
+	lint, err := loadRawLint(name)
+	if err != nil {
+		return nil, err
+	}
+	return mergeExtends(name, lint, append(chain, name))
+}
+
+// mergeExtends merges the lint section of the config named name on the configs it extends.
+// The relative paths are resolved against the directory of name, which is the working directory when it's empty.
+func mergeExtends(
+	name string,
+	lint map[string]interface{},
+	chain []string,
+) (map[string]interface{}, error) {
+	extends, err := extendsOf(name, lint)
+	if err != nil {
+		return nil, err
+	}
+	delete(lint, "extends")
+
+	merged := map[string]interface{}{}
+	for _, e := range extends {
+		base, err := resolveLint(e, chain)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeLint(merged, base)
+	}
+	return mergeLint(merged, lint), nil
+}
+
+// extendsOf returns the configs which the lint section of the config named name extends.
+// The relative paths are resolved against the directory of name.
+func extendsOf(
+	name string,
+	lint map[string]interface{},
+) ([]string, error) {
+	extends, err := toStrings(lint["extends"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid extends in %s: %w", name, err)
+	}
+	var names []string
+	for _, e := range extends {
+		if strings.TrimSpace(e) == "" || e == builtinPrefix {
+			return nil, fmt.Errorf("invalid extends in %s: %q must be a path or a built-in preset", name, e)
+		}
+		if !strings.HasPrefix(e, builtinPrefix) && !filepath.IsAbs(e) && !strings.HasPrefix(name, builtinPrefix) {
+			e = filepath.Join(filepath.Dir(name), e)
+		}
+		names = append(names, filepath.Clean(e))
+	}
+	return names, nil
+}
+
+// ExtendedFiles returns the paths to the config files which the config file extends directly or indirectly,
+// so that the callers can watch them as well. The built-in presets aren't included.
+func ExtendedFiles(filePath string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{filepath.Clean(filePath): true}
+	queue := []string{filepath.Clean(filePath)}
+	for 0 < len(queue) {
+		name := queue[0]
+		queue = queue[1:]
+
+		lint, err := loadRawLint(name)
+		if err != nil {
+			return nil, err
+		}
+		extends, err := extendsOf(name, lint)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range extends {
+			if strings.HasPrefix(e, builtinPrefix) || seen[e] {
+				continue
+			}
+			seen[e] = true
+			files = append(files, e)
+			queue = append(queue, e)
+		}
+	}
+	return files, nil
+}
+
+// loadRawLint loads the lint section of the config file or the built-in preset as it is written.
+func loadRawLint(name string) (map[string]interface{}, error) {
+	if strings.HasPrefix(name, builtinPrefix) {
+		preset := strings.TrimPrefix(name, builtinPrefix)
+		data, err := presets.ReadFile("presets/" + preset + externalConfigFileExtension)
+		if err != nil {
+			return nil, fmt.Errorf("not found the built-in preset %q. valid presets are [%s]",
+				preset, strings.Join(BuiltinPresets(), ","))
+		}
+		return decodeRawLint(data, "lint", yaml.Unmarshal)
+	}
+
+	// Check the file with the strictness of its loader, since the merged section is decoded leniently.
+	loader, err := getLoaderFromExtension(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loader.LoadExternalConfig(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", name, err)
+	}
+	data, err := loadFileContent(name)
+	if err != nil {
+		return nil, err
+	}
+	var lint map[string]interface{}
+	switch {
+	case strings.HasSuffix(name, externalConfigFileExtension) || strings.HasSuffix(name, externalConfigFileExtension2):
+		lint, err = decodeRawLint(data, "lint", yaml.Unmarshal)
+	case strings.HasSuffix(name, packageJsonFileNameForJsExtension):
+		lint, err = decodeRawLint(data, "protolint", json.Unmarshal)
+	case strings.HasSuffix(name, pyProjectTomlFileNameForPyExtension):
+		var tools map[string]interface{}
+		tools, err = decodeRawLint(data, "tools", toml.Unmarshal)
+		if err == nil {
+			lint, err = toMap(tools["protolint"])
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a valid support file extension", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", name, err)
+	}
+	// The paths of the modules are resolved against the directory of the file, which may not be the extending one.
+	if plugins, ok := lint["wasm_plugins"].([]interface{}); ok {
+		c := ExternalConfig{SourcePath: name}
+		for _, p := range plugins {
+			if s, ok := p.(string); ok {
+				c.Lint.WasmPlugins = append(c.Lint.WasmPlugins, s)
+			}
+		}
+		var resolved []interface{}
+		for _, p := range c.WasmPlugins() {
+			resolved = append(resolved, p)
+		}
+		lint["wasm_plugins"] = resolved
+	}
+	return lint, nil
+}
+
+func decodeRawLint(
+	data []byte,
+	key string,
+	unmarshal func([]byte, interface{}) error,
+) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return toMap(doc[key])
+}
+
+// toMap converts the value decoded from any format to the map whose nested maps have the string keys.
+func toMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return map[string]interface{}{}, nil
+	}
+	n, err := normalizeValue(v, false)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := n.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v must be a map", v)
+	}
+	return m, nil
+}
+
+func toStrings(v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%v must be a list", v)
+	}
+	var ss []string
+	for _, e := range list {
+		s, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v must be a string", e)
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+// mergeLint merges the lint section of the config on the one of its base.
+//
+//   - rules: no_default and all_default are overridden. add and remove are concatenated,
+//     and the config's remove drops the rules from the add of the base, and vice versa.
+//   - rules_option and plugins: the maps are merged recursively, and the other values are overridden.
+//   - ignores: the files of the same rule are concatenated.
+//   - custom_rules: the rule of the same id is overridden.
+//   - files, directories and wasm_plugins: the lists are concatenated.
+//   - the others are overridden.
+func mergeLint(base, lint map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range lint {
+		b, ok := merged[k]
+		if !ok {
+			merged[k] = v
+			continue
+		}
+		switch k {
+		case "rules":
+			merged[k] = mergeRules(b, v)
+		case "rules_option", "plugins":
+			merged[k] = mergeMaps(b, v)
+		case "ignores":
+			merged[k] = mergeIgnores(b, v)
+		case "custom_rules":
+			merged[k] = mergeCustomRules(b, v)
+		case "files", "directories":
+			merged[k] = mergeExcludes(b, v)
+		case "wasm_plugins":
+			merged[k] = mergeLists(b, v)
+		default:
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func mergeRules(base, rules interface{}) interface{} {
+	b, ok1 := base.(map[string]interface{})
+	r, ok2 := rules.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return rules
+	}
+
+	merged := make(map[string]interface{}, len(b))
+	for k, v := range b {
+		merged[k] = v
+	}
+	for k, v := range r {
+		merged[k] = v
+	}
+	add, _ := r["add"].([]interface{})
+	remove, _ := r["remove"].([]interface{})
+	if list := subtractList(mergeLists(b["add"], r["add"]), remove); 0 < len(list) {
+		merged["add"] = list
+	} else {
+		delete(merged, "add")
+	}
+	if list := subtractList(mergeLists(b["remove"], r["remove"]), add); 0 < len(list) {
+		merged["remove"] = list
+	} else {
+		delete(merged, "remove")
+	}
+	return merged
+}
+
+func mergeMaps(base, m interface{}) interface{} {
+	b, ok1 := base.(map[string]interface{})
+	o, ok2 := m.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return m
+	}
+
+	merged := make(map[string]interface{}, len(b))
+	for k, v := range b {
+		merged[k] = v
+	}
+	for k, v := range o {
+		if bv, ok := merged[k]; ok {
+			merged[k] = mergeMaps(bv, v)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func mergeIgnores(base, ignores interface{}) interface{} {
+	b, ok1 := base.([]interface{})
+	i, ok2 := ignores.([]interface{})
+	if !ok1 || !ok2 {
+		return ignores
+	}
+
+	var merged []interface{}
+	byID := make(map[string]map[string]interface{})
+	for _, e := range append(append([]interface{}{}, b...), i...) {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			merged = append(merged, e)
+			continue
+		}
+		id, ok := m["id"].(string)
+		if !ok {
+			merged = append(merged, e)
+			continue
+		}
+		if prev, ok := byID[id]; ok {
+			prev["files"] = mergeLists(prev["files"], m["files"])
+			continue
+		}
+		copied := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			copied[k] = v
+		}
+		byID[id] = copied
+		merged = append(merged, copied)
+	}
+	return merged
+}
+
+func mergeCustomRules(base, rules interface{}) interface{} {
+	b, ok1 := base.([]interface{})
+	r, ok2 := rules.([]interface{})
+	if !ok1 || !ok2 {
+		return rules
+	}
+
+	overridden := make(map[string]bool)
+	for _, e := range r {
+		if m, ok := e.(map[string]interface{}); ok {
+			if id, ok := m["id"].(string); ok {
+				overridden[id] = true
+			}
+		}
+	}
+	var merged []interface{}
+	for _, e := range b {
+		if m, ok := e.(map[string]interface{}); ok {
+			if id, ok := m["id"].(string); ok && overridden[id] {
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	return append(merged, r...)
+}
+
+func mergeExcludes(base, excludes interface{}) interface{} {
+	b, ok1 := base.(map[string]interface{})
+	e, ok2 := excludes.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return excludes
+	}
+
+	merged := make(map[string]interface{}, len(b))
+	for k, v := range b {
+		merged[k] = v
+	}
+	for k, v := range e {
+		if bv, ok := merged[k]; ok {
+			merged[k] = mergeLists(bv, v)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeLists concatenates the lists, dropping the duplicates.
+func mergeLists(base, list interface{}) []interface{} {
+	b, _ := base.([]interface{})
+	l, _ := list.([]interface{})
+
+	var merged []interface{}
+	for _, e := range append(append([]interface{}{}, b...), l...) {
+		if !containsValue(merged, e) {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+func subtractList(list, values []interface{}) []interface{} {
+	var subtracted []interface{}
+	for _, e := range list {
+		if !containsValue(values, e) {
+			subtracted = append(subtracted, e)
+		}
+	}
+	return subtracted
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
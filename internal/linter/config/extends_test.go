@@ -0,0 +1,253 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+)
+
+func TestGetExternalConfig_extends(t *testing.T) {
+	baseIgnores := config.Ignores{
+		{
+			ID: "ENUM_NAMES_UPPER_CAMEL_CASE",
+			Files: []string{
+				"path/to/foo.proto",
+			},
+		},
+	}
+	baseRules := config.Rules{
+		NoDefault: true,
+		Add: []string{
+			"FIELD_NAMES_LOWER_SNAKE_CASE",
+			"MESSAGE_NAMES_UPPER_CAMEL_CASE",
+		},
+		Remove: []string{
+			"RPC_NAMES_UPPER_CAMEL_CASE",
+		},
+	}
+	baseWasmPlugins := config.WasmPlugins{
+		setting_test.TestDataPath("extends", "base", "rules.wasm") + " -go_style=false",
+	}
+
+	for _, test := range []struct {
+		name               string
+		inputFilePath      string
+		wantExternalConfig *config.ExternalConfig
+		// wantRemoved is the rule which must be removed, instead of checking the whole config.
+		wantRemoved  string
+		wantExistErr bool
+	}{
+		{
+			name:          "the config is merged on the one it extends",
+			inputFilePath: setting_test.TestDataPath("extends", "child", "protolint.yaml"),
+			wantExternalConfig: &config.ExternalConfig{
+				SourcePath: setting_test.TestDataPath("extends", "child", "protolint.yaml"),
+				Lint: config.Lint{
+					Ignores: config.Ignores{
+						{
+							ID: "ENUM_NAMES_UPPER_CAMEL_CASE",
+							Files: []string{
+								"path/to/foo.proto",
+								"path/to/bar.proto",
+							},
+						},
+						{
+							ID: "FIELD_NAMES_LOWER_SNAKE_CASE",
+							Files: []string{
+								"path/to/baz.proto",
+							},
+						},
+					},
+					Files: config.Files{
+						Exclude: []string{
+							"path/to/vendor",
+							"path/to/gen",
+						},
+					},
+					Rules: config.Rules{
+						NoDefault: true,
+						Add: []string{
+							"FIELD_NAMES_LOWER_SNAKE_CASE",
+							"RPC_NAMES_UPPER_CAMEL_CASE",
+						},
+						Remove: []string{
+							"MESSAGE_NAMES_UPPER_CAMEL_CASE",
+						},
+					},
+					RulesOption: config.RulesOption{
+						MaxLineLength: config.MaxLineLengthOption{
+							MaxChars: 120,
+							TabChars: 2,
+						},
+						Indent: config.IndentOption{
+							Style: "\t",
+						},
+					},
+					WasmPlugins: baseWasmPlugins,
+				},
+			},
+		},
+		{
+			name:          "the package.json extends the yaml file",
+			inputFilePath: setting_test.TestDataPath("extends", "json", "package.json"),
+			wantExternalConfig: &config.ExternalConfig{
+				SourcePath: setting_test.TestDataPath("extends", "json", "package.json"),
+				Lint: config.Lint{
+					Ignores: baseIgnores,
+					Files: config.Files{
+						Exclude: []string{
+							"path/to/vendor",
+						},
+					},
+					Rules: config.Rules{
+						Add:    baseRules.Add,
+						Remove: baseRules.Remove,
+					},
+					RulesOption: config.RulesOption{
+						MaxLineLength: config.MaxLineLengthOption{
+							MaxChars: 80,
+							TabChars: 2,
+						},
+						Indent: config.IndentOption{
+							Style: "\t",
+						},
+					},
+					WasmPlugins: baseWasmPlugins,
+				},
+			},
+		},
+		{
+			name:          "the unknown fields in package.json are ignored",
+			inputFilePath: setting_test.TestDataPath("extends", "lenient", "package.json"),
+			wantRemoved:   "MAX_LINE_LENGTH",
+		},
+		{
+			name:          "the unknown fields in the yaml file extended are reported",
+			inputFilePath: setting_test.TestDataPath("extends", "strict", "protolint.yaml"),
+			wantExistErr:  true,
+		},
+		{
+			name:          "the empty extends",
+			inputFilePath: setting_test.TestDataPath("extends", "empty", "protolint.yaml"),
+			wantExistErr:  true,
+		},
+		{
+			name:          "the cycle of extends",
+			inputFilePath: setting_test.TestDataPath("extends", "cycle", "a.yaml"),
+			wantExistErr:  true,
+		},
+		{
+			name:          "not found builtin preset",
+			inputFilePath: setting_test.TestDataPath("extends", "invalid", "protolint.yaml"),
+			wantExistErr:  true,
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := config.GetExternalConfig(test.inputFilePath, "")
+			if test.wantExistErr {
+				if err == nil {
+					t.Errorf("got err nil, but want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if test.wantRemoved != "" {
+				if !reflect.DeepEqual(got.Lint.Rules.Remove, []string{test.wantRemoved}) {
+					t.Errorf("got %v, but want [%s]", got.Lint.Rules.Remove, test.wantRemoved)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantExternalConfig) {
+				t.Errorf("got %v, but want %v", got, test.wantExternalConfig)
+			}
+		})
+	}
+}
+
+func TestBuiltinPresets(t *testing.T) {
+	presets := config.BuiltinPresets()
+	if !reflect.DeepEqual(presets, []string{"google", "uber"}) {
+		t.Errorf("got %v, but want [google uber]", presets)
+	}
+
+	for _, preset := range presets {
+		preset := preset
+		t.Run(preset, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "protolint.yaml")
+			content := "lint:\n  extends:\n    - builtin:" + preset + "\n"
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := config.GetExternalConfig(path, "")
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if !got.Lint.Rules.NoDefault || len(got.Lint.Rules.Add) == 0 {
+				t.Errorf("got %v, but want the rules to be listed", got.Lint.Rules)
+			}
+
+			rules, err := subcmds.NewAllRules(got.Lint.RulesOption, nil, nil, false, autodisable.Noop, false, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make(map[string]bool)
+			for _, id := range rules.IDs() {
+				ids[id] = true
+			}
+			for _, id := range got.Lint.Rules.Add {
+				if !ids[id] {
+					t.Errorf("not found the rule %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestExtendedFiles(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		inputFilePath string
+		wantFiles     []string
+	}{
+		{
+			name:          "the file extended",
+			inputFilePath: setting_test.TestDataPath("extends", "child", "protolint.yaml"),
+			wantFiles: []string{
+				setting_test.TestDataPath("extends", "base", "protolint.yaml"),
+			},
+		},
+		{
+			name:          "the built-in preset is left out",
+			inputFilePath: setting_test.TestDataPath("extends", "lenient", "package.json"),
+		},
+		{
+			name:          "the cycle of extends",
+			inputFilePath: setting_test.TestDataPath("extends", "cycle", "a.yaml"),
+			wantFiles: []string{
+				setting_test.TestDataPath("extends", "cycle", "b.yaml"),
+			},
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := config.ExtendedFiles(test.inputFilePath)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if !reflect.DeepEqual(got, test.wantFiles) {
+				t.Errorf("got %v, but want %v", got, test.wantFiles)
+			}
+		})
+	}
+}
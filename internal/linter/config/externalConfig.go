@@ -1,12 +1,21 @@
 package config
 
+import (
+	internalrule "github.com/yoheimuta/protolint/internal/linter/rule"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
 // Lint represents the lint configuration.
 type Lint struct {
+	Extends     Extends `yaml:"extends" json:"extends" toml:"extends"`
 	Ignores     Ignores
 	Files       Files
 	Directories Directories
 	Rules       Rules
 	RulesOption RulesOption `yaml:"rules_option" json:"rules_option" toml:"rules_option"`
+	CustomRules CustomRules `yaml:"custom_rules" json:"custom_rules" toml:"custom_rules"`
+	Plugins     PluginRules `yaml:"plugins" json:"plugins" toml:"plugins"`
+	WasmPlugins WasmPlugins `yaml:"wasm_plugins" json:"wasm_plugins" toml:"wasm_plugins"`
 }
 
 // ExternalConfig represents the external configuration.
@@ -25,5 +34,38 @@ func (c ExternalConfig) ShouldSkipRule(
 	return lint.Ignores.shouldSkipRule(ruleID, displayPath) ||
 		lint.Files.shouldSkipRule(displayPath) ||
 		lint.Directories.shouldSkipRule(displayPath) ||
-		lint.Rules.shouldSkipRule(ruleID, defaultRuleIDs)
+		lint.Rules.shouldSkipRule(ruleID, defaultRuleIDs, lint.CustomRules.IDs())
+}
+
+// SelectRules selects the rules among allRules which are applied to the file.
+func (c ExternalConfig) SelectRules(
+	displayPath string,
+	allRules internalrule.Rules,
+) []rule.HasApply {
+	defaultRuleIDs := c.defaultRuleIDs(allRules)
+
+	var hasApplies []rule.HasApply
+	for _, r := range allRules {
+		if c.ShouldSkipRule(r.ID(), displayPath, defaultRuleIDs) {
+			continue
+		}
+		hasApplies = append(hasApplies, r)
+	}
+	return hasApplies
+}
+
+// EnablesRule reports whether the rules section enables the rule among allRules,
+// regardless of the files it ignores.
+func (c ExternalConfig) EnablesRule(
+	ruleID string,
+	allRules internalrule.Rules,
+) bool {
+	return !c.Lint.Rules.shouldSkipRule(ruleID, c.defaultRuleIDs(allRules), c.Lint.CustomRules.IDs())
+}
+
+func (c ExternalConfig) defaultRuleIDs(allRules internalrule.Rules) []string {
+	if c.Lint.Rules.AllDefault {
+		return allRules.IDs()
+	}
+	return allRules.Default().IDs()
 }
@@ -38,7 +38,33 @@ func GetExternalConfig(
 		return nil, err
 	}
 
-	return reader.LoadExternalConfig()
+	config, err := reader.LoadExternalConfig()
+	if err != nil || config == nil || len(config.Lint.Extends) == 0 {
+		return config, err
+	}
+	if err := config.resolveExtends(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// IsExternalConfigFile reports whether the file at the path can be found as the externalConfig.
+func IsExternalConfigFile(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range []string{
+		externalConfigFileName,
+		externalConfigFileName2,
+	} {
+		for _, ext := range []string{
+			externalConfigFileExtension,
+			externalConfigFileExtension2,
+		} {
+			if base == name+ext {
+				return true
+			}
+		}
+	}
+	return base == packageJsonFileNameForJs || base == pyProjectTomlFileNameForPy
 }
 
 func getLoaderFromExtension(filePath string) (configLoader, error) {
@@ -58,8 +58,13 @@ func TestExternalConfig_ShouldSkipRule(t *testing.T) {
 				},
 				Remove: []string{
 					"RPC_NAMES_UPPER_CAMEL_CASE",
+					"REMOVED_CUSTOM_RULE",
 				},
 			},
+			CustomRules: config.CustomRules{
+				{ID: "DECLARED_CUSTOM_RULE"},
+				{ID: "REMOVED_CUSTOM_RULE"},
+			},
 		},
 	}
 
@@ -98,7 +103,7 @@ func TestExternalConfig_ShouldSkipRule(t *testing.T) {
 		},
 	}
 
-	allRules, err := subcmds.NewAllRules(config.RulesOption{}, false, autodisable.Noop, false, nil)
+	allRules, err := subcmds.NewAllRules(config.RulesOption{}, nil, nil, false, autodisable.Noop, false, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -301,6 +306,19 @@ func TestExternalConfig_ShouldSkipRule(t *testing.T) {
 			inputRuleID:      "FIELD_NAMES_LOWER_SNAKE_CASE",
 			inputDisplayPath: "path/to1/file.proto",
 		},
+		{
+			name:             "not skip the custom rule even with no_default",
+			externalConfig:   noDefaultExternalConfig,
+			inputRuleID:      "DECLARED_CUSTOM_RULE",
+			inputDisplayPath: "path/to/baz.proto",
+		},
+		{
+			name:             "skip the custom rule removed",
+			externalConfig:   noDefaultExternalConfig,
+			inputRuleID:      "REMOVED_CUSTOM_RULE",
+			inputDisplayPath: "path/to/baz.proto",
+			wantSkipRule:     true,
+		},
 		{
 			name:             "not exclude the unix file by referring to a windows path",
 			externalConfig:   noDefaultExternalConfig,
@@ -0,0 +1,8 @@
+package config
+
+// FieldNumbersNoGapsOption represents the option for the FIELD_NUMBERS_NO_GAPS rule.
+type FieldNumbersNoGapsOption struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// MaxGap is the number of the unused numbers allowed in a row.
+	MaxGap int `yaml:"max_gap" json:"max_gap" toml:"max_gap"`
+}
@@ -0,0 +1,10 @@
+package config
+
+// FieldsReservedOnDeletionOption represents the option for the FIELDS_RESERVED_ON_DELETION rule.
+type FieldsReservedOnDeletionOption struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// GitRef is the git ref of the previous version.
+	GitRef string `yaml:"git_ref" json:"git_ref" toml:"git_ref"`
+	// SnapshotDir is the directory with the .proto files of the previous version.
+	SnapshotDir string `yaml:"snapshot_dir" json:"snapshot_dir" toml:"snapshot_dir"`
+}
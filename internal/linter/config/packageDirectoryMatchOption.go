@@ -0,0 +1,9 @@
+package config
+
+// PackageDirectoryMatchOption represents the option for the PACKAGE_DIRECTORY_MATCH rule.
+type PackageDirectoryMatchOption struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// Root is the directory which the directories of the packages are relative to.
+	// The default is the working directory.
+	Root string `yaml:"root" json:"root" toml:"root"`
+}
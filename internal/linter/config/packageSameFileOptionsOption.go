@@ -0,0 +1,8 @@
+package config
+
+// PackageSameFileOptionsOption represents the option for the PACKAGE_SAME_FILE_OPTIONS rule.
+type PackageSameFileOptionsOption struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// Options are the names of the file options to compare. The default is the options for the package of the generated code.
+	Options []string `yaml:"options" json:"options" toml:"options"`
+}
@@ -0,0 +1,8 @@
+package config
+
+// PackageVersionSuffixOption represents the option for the PACKAGE_VERSION_SUFFIX rule.
+type PackageVersionSuffixOption struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// Pattern is the regular expression which the last component of the package name must match.
+	Pattern string `yaml:"pattern" json:"pattern" toml:"pattern"`
+}
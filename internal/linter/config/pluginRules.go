@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+)
+
+// PluginRule represents the option of a rule provided by a plugin.
+type PluginRule struct {
+	CustomizableSeverityOption `yaml:",inline"`
+	// Config is an arbitrary value, which is sent to the plugin as it is.
+	Config interface{} `yaml:"config" json:"config" toml:"config"`
+}
+
+// PluginRules maps the IDs of the plugin rules to their options.
+type PluginRules map[string]PluginRule
+
+// Configs returns the configs of the rules which have them. The values are normalized
+// to the ones decoded from JSON, so that the maps decoded from YAML can be sent to the plugins.
+func (p PluginRules) Configs() (map[string]interface{}, error) {
+	configs := make(map[string]interface{})
+	for id, r := range p {
+		if r.Config == nil {
+			continue
+		}
+		config, err := normalizeValue(r.Config, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config of the plugin rule %q: %w", id, err)
+		}
+		configs[id] = config
+	}
+	return configs, nil
+}
+
+// normalizeValue converts the nested maps decoded from YAML or TOML to the ones with the string keys,
+// and the lists of them to []interface{}. jsonNumbers also converts the integers to float64 like JSON.
+func normalizeValue(v interface{}, jsonNumbers bool) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("the key %v must be a string", k)
+			}
+			n, err := normalizeValue(e, jsonNumbers)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = n
+		}
+		return m, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			n, err := normalizeValue(e, jsonNumbers)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = n
+		}
+		return m, nil
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			n, err := normalizeValue(e, jsonNumbers)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, n)
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			n, err := normalizeValue(e, jsonNumbers)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, n)
+		}
+		return list, nil
+	}
+	if !jsonNumbers {
+		return v, nil
+	}
+	switch v := v.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case nil, bool, float64, string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("the value %v of the type %T is not supported", v, v)
+}
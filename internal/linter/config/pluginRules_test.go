@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPluginRules_Configs(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		inputConfig  []byte
+		wantConfigs  map[string]interface{}
+		wantExistErr bool
+	}{
+		{
+			name: "empty config",
+			inputConfig: []byte(`
+`),
+			wantConfigs: map[string]interface{}{},
+		},
+		{
+			name: "configs and severities",
+			inputConfig: []byte(`
+FOO:
+  severity: warning
+  config:
+    max: 3
+    names:
+      - foo
+      - nested:
+          enabled: true
+BAR:
+  config: bar
+BAZ:
+  severity: note
+`),
+			wantConfigs: map[string]interface{}{
+				"FOO": map[string]interface{}{
+					"max": float64(3),
+					"names": []interface{}{
+						"foo",
+						map[string]interface{}{
+							"nested": map[string]interface{}{
+								"enabled": true,
+							},
+						},
+					},
+				},
+				"BAR": "bar",
+			},
+		},
+		{
+			name: "not string key",
+			inputConfig: []byte(`
+FOO:
+  config:
+    1: one
+`),
+			wantExistErr: true,
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var rules config.PluginRules
+			err := yaml.UnmarshalStrict(test.inputConfig, &rules)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			got, err := rules.Configs()
+			if test.wantExistErr {
+				if err == nil {
+					t.Errorf("got err nil, but want err")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("got err %v, but want nil", err)
+				return
+			}
+			if !reflect.DeepEqual(got, test.wantConfigs) {
+				t.Errorf("got %v, but want %v", got, test.wantConfigs)
+			}
+		})
+	}
+}
@@ -10,14 +10,18 @@ type Rules struct {
 	Remove     []string `yaml:"remove" json:"remove" toml:"remove"`
 }
 
+// shouldSkipRule checks whether the rule is disabled. The declared rules, like the custom ones,
+// are enabled even with no_default, unless they are removed.
 func (r Rules) shouldSkipRule(
 	ruleID string,
 	defaultRuleIDs []string,
+	declaredRuleIDs []string,
 ) bool {
 	var ruleIDs []string
 	if !r.NoDefault {
 		ruleIDs = append(ruleIDs, defaultRuleIDs...)
 	}
+	ruleIDs = append(ruleIDs, declaredRuleIDs...)
 
 	for _, add := range r.Add {
 		ruleIDs = append(ruleIDs, add)
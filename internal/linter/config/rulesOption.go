@@ -33,4 +33,17 @@ type RulesOption struct {
 	RPCNamesUpperCamelCase          CustomizableSeverityOption            `yaml:"rpc_names_upper_camel_case" json:"rpc_names_upper_camel_case" toml:"rpc_names_upper_camel_case"`
 	ServiceNamesUpperCamelCase      CustomizableSeverityOption            `yaml:"service_names_upper_caml_case" json:"service_names_upper_caml_case" toml:"service_names_upper_caml_case"`
 	FieldNumbersOrderAscending      CustomizableSeverityOption            `yaml:"field_numbers_order_ascending" json:"field_numbers_order_ascending" toml:"field_numbers_order_ascending"`
+	ImportsUnused                   CustomizableSeverityOption            `yaml:"imports_unused" json:"imports_unused" toml:"imports_unused"`
+	ImportsMissing                  CustomizableSeverityOption            `yaml:"imports_missing" json:"imports_missing" toml:"imports_missing"`
+	ImportNoCycle                   CustomizableSeverityOption            `yaml:"import_no_cycle" json:"import_no_cycle" toml:"import_no_cycle"`
+	PackageDirectoryMatch           PackageDirectoryMatchOption           `yaml:"package_directory_match" json:"package_directory_match" toml:"package_directory_match"`
+	PackageSameDirectory            CustomizableSeverityOption            `yaml:"package_same_directory" json:"package_same_directory" toml:"package_same_directory"`
+	PackageSameFileOptions          PackageSameFileOptionsOption          `yaml:"package_same_file_options" json:"package_same_file_options" toml:"package_same_file_options"`
+	PackageVersionSuffix            PackageVersionSuffixOption            `yaml:"package_version_suffix" json:"package_version_suffix" toml:"package_version_suffix"`
+	StablePackageNoUnstableImport   CustomizableSeverityOption            `yaml:"stable_package_no_unstable_import" json:"stable_package_no_unstable_import" toml:"stable_package_no_unstable_import"`
+	FieldNumbersValid               CustomizableSeverityOption            `yaml:"field_numbers_valid" json:"field_numbers_valid" toml:"field_numbers_valid"`
+	FieldNumbersNotReserved         CustomizableSeverityOption            `yaml:"field_numbers_not_reserved" json:"field_numbers_not_reserved" toml:"field_numbers_not_reserved"`
+	FieldNumbersUnique              CustomizableSeverityOption            `yaml:"field_numbers_unique" json:"field_numbers_unique" toml:"field_numbers_unique"`
+	FieldNumbersNoGaps              FieldNumbersNoGapsOption              `yaml:"field_numbers_no_gaps" json:"field_numbers_no_gaps" toml:"field_numbers_no_gaps"`
+	FieldsReservedOnDeletion        FieldsReservedOnDeletionOption        `yaml:"fields_reserved_on_deletion" json:"fields_reserved_on_deletion" toml:"fields_reserved_on_deletion"`
 }
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// WasmPlugins represents the plugins compiled to WASI modules, which are loaded in addition to the -plugin flags.
+// Each one is the path to the module optionally followed by the arguments, like the -plugin flag.
+// Unlike the flag, the config can't run the commands because the modules are sandboxed,
+// and can't read the files other than the working directory.
+type WasmPlugins []string
+
+// WasmPlugins returns the plugins whose relative paths are resolved against the directory of the config file.
+func (c ExternalConfig) WasmPlugins() []string {
+	var plugins []string
+	for _, p := range c.Lint.WasmPlugins {
+		fields := strings.Fields(p)
+		if len(fields) == 0 {
+			continue
+		}
+		if !filepath.IsAbs(fields[0]) && 0 < len(c.SourcePath) {
+			fields[0] = filepath.Join(filepath.Dir(c.SourcePath), fields[0])
+		}
+		plugins = append(plugins, strings.Join(fields, " "))
+	}
+	return plugins
+}
@@ -0,0 +1,65 @@
+package config_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+)
+
+func TestExternalConfig_WasmPlugins(t *testing.T) {
+	abs, err := filepath.Abs(filepath.Join("path", "to", "rules.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name            string
+		inputSourcePath string
+		inputPlugins    config.WasmPlugins
+		wantPlugins     []string
+	}{
+		{
+			name: "no plugins",
+		},
+		{
+			name:            "the relative paths are resolved against the config file",
+			inputSourcePath: filepath.Join("proto", ".protolint.yaml"),
+			inputPlugins: config.WasmPlugins{
+				"rules.wasm",
+				"plugins/rules.wasm  -go_style=false",
+				abs,
+				" ",
+			},
+			wantPlugins: []string{
+				filepath.Join("proto", "rules.wasm"),
+				filepath.Join("proto", "plugins", "rules.wasm") + " -go_style=false",
+				abs,
+			},
+		},
+		{
+			name: "the relative paths are kept without the config file",
+			inputPlugins: config.WasmPlugins{
+				"rules.wasm",
+			},
+			wantPlugins: []string{
+				"rules.wasm",
+			},
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := config.ExternalConfig{
+				SourcePath: test.inputSourcePath,
+				Lint: config.Lint{
+					WasmPlugins: test.inputPlugins,
+				},
+			}
+			got := c.WasmPlugins()
+			if !reflect.DeepEqual(got, test.wantPlugins) {
+				t.Errorf("got %v, but want %v", got, test.wantPlugins)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -31,3 +33,30 @@ func (y yamlConfigLoader) LoadExternalConfig() (*ExternalConfig, error) {
 
 	return &config, nil
 }
+
+// ParseYAMLConfig parses the content of protolint.yaml, which isn't read from any file.
+// The relative paths in extends and wasm_plugins are resolved against the working directory.
+func ParseYAMLConfig(data []byte) (*ExternalConfig, error) {
+	var config ExternalConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Lint.Extends) == 0 {
+		return &config, nil
+	}
+
+	raw, err := decodeRawLint(data, "lint", yaml.Unmarshal)
+	if err != nil {
+		return nil, err
+	}
+	lint, err := mergeExtends("", raw, nil)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := decodeResolvedLint(lint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the config extending others: %w", err)
+	}
+	config.Lint = resolved
+	return &config, nil
+}
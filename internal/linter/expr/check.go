@@ -0,0 +1,281 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// stringMethods are the methods of the strings with the types of their arguments and results.
+var stringMethods = map[string]struct {
+	args   []*Type
+	result *Type
+}{
+	"startsWith": {args: []*Type{String}, result: Bool},
+	"endsWith":   {args: []*Type{String}, result: Bool},
+	"contains":   {args: []*Type{String}, result: Bool},
+	"matches":    {args: []*Type{String}, result: Bool},
+	"lowerAscii": {result: String},
+	"upperAscii": {result: String},
+	"size":       {result: Int},
+}
+
+// scope maps the names of the variables to their types.
+type scope map[string]*Type
+
+func (s scope) with(name string, t *Type) scope {
+	child := make(scope, len(s)+1)
+	for k, v := range s {
+		child[k] = v
+	}
+	child[name] = t
+	return child
+}
+
+func (s scope) names() []string {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check returns the type of n, or an error if n is ill-typed.
+func check(n node, s scope) (*Type, error) {
+	switch n := n.(type) {
+	case *literalNode:
+		return n.typ, nil
+	case *identNode:
+		t, ok := s[n.name]
+		if !ok {
+			return nil, undefined(n.p, "variable", n.name, "", s.names())
+		}
+		return t, nil
+	case *listNode:
+		elem := Dyn
+		for _, e := range n.elems {
+			t, err := check(e, s)
+			if err != nil {
+				return nil, err
+			}
+			if !t.equal(elem) {
+				return nil, errorf(e.pos(), "the list mixes %s with %s", elem, t)
+			}
+			if elem.Kind == DynKind {
+				elem = t
+			}
+		}
+		return ListOf(elem), nil
+	case *unaryNode:
+		t, err := check(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		want := Bool
+		if n.op == "-" {
+			want = Int
+		}
+		if t.Kind != want.Kind {
+			return nil, errorf(n.p, "%s expects %s, but got %s", n.op, want, t)
+		}
+		return want, nil
+	case *binaryNode:
+		return checkBinary(n, s)
+	case *condNode:
+		c, err := check(n.c, s)
+		if err != nil {
+			return nil, err
+		}
+		if c.Kind != BoolKind {
+			return nil, errorf(n.c.pos(), "the condition of ?: must be bool, but got %s", c)
+		}
+		t, err := check(n.t, s)
+		if err != nil {
+			return nil, err
+		}
+		f, err := check(n.f, s)
+		if err != nil {
+			return nil, err
+		}
+		if !t.equal(f) {
+			return nil, errorf(n.p, "the branches of ?: have the different types %s and %s", t, f)
+		}
+		return t, nil
+	case *selectNode:
+		x, err := check(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		if x.Kind != ObjectKind {
+			return nil, errorf(n.p, "%s has no attributes, but got .%s", x, n.field)
+		}
+		t, ok := x.Fields[n.field]
+		if !ok {
+			return nil, undefined(n.p, "attribute", n.field, " of "+x.Name, x.fieldNames())
+		}
+		return t, nil
+	case *indexNode:
+		x, err := check(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		i, err := check(n.i, s)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case x.Kind == ListKind && i.Kind == IntKind:
+			return x.Elem, nil
+		case x.Kind == MapKind && i.Kind == StringKind:
+			return x.Elem, nil
+		}
+		return nil, errorf(n.p, "cannot index %s with %s", x, i)
+	case *callNode:
+		return checkCall(n, s)
+	case *macroNode:
+		recv, err := check(n.recv, s)
+		if err != nil {
+			return nil, err
+		}
+		if recv.Kind != ListKind {
+			return nil, errorf(n.p, "%s expects a list, but got %s", n.fn, recv)
+		}
+		body, err := check(n.body, s.with(n.v, recv.Elem))
+		if err != nil {
+			return nil, err
+		}
+		if body.Kind != BoolKind {
+			return nil, errorf(n.body.pos(), "the predicate of %s must be bool, but got %s", n.fn, body)
+		}
+		return Bool, nil
+	}
+	return nil, errorf(n.pos(), "unknown expression")
+}
+
+func undefined(pos int, kind, name, of string, candidates []string) error {
+	msg := fmt.Sprintf("undefined %s %q%s", kind, name, of)
+	if s := suggest(name, candidates); s != "" {
+		msg += fmt.Sprintf("; did you mean %q?", s)
+	} else if len(candidates) != 0 {
+		msg += fmt.Sprintf("; available: %s", strings.Join(candidates, ", "))
+	}
+	return &Error{Pos: pos, Msg: msg}
+}
+
+func checkBinary(n *binaryNode, s scope) (*Type, error) {
+	x, err := check(n.x, s)
+	if err != nil {
+		return nil, err
+	}
+	y, err := check(n.y, s)
+	if err != nil {
+		return nil, err
+	}
+	mismatch := func() (*Type, error) {
+		return nil, errorf(n.p, "%s is not defined for %s and %s", n.op, x, y)
+	}
+	switch n.op {
+	case "&&", "||":
+		if x.Kind != BoolKind || y.Kind != BoolKind {
+			return mismatch()
+		}
+		return Bool, nil
+	case "==", "!=":
+		if !x.equal(y) && x.Kind != NullKind && y.Kind != NullKind {
+			return mismatch()
+		}
+		return Bool, nil
+	case "<", "<=", ">", ">=":
+		if x.Kind != y.Kind || (x.Kind != IntKind && x.Kind != StringKind) {
+			return mismatch()
+		}
+		return Bool, nil
+	case "in":
+		switch {
+		case y.Kind == ListKind && x.equal(y.Elem):
+		case y.Kind == MapKind && x.Kind == StringKind:
+		default:
+			return mismatch()
+		}
+		return Bool, nil
+	case "+":
+		if !x.equal(y) || (x.Kind != IntKind && x.Kind != StringKind && x.Kind != ListKind) {
+			return mismatch()
+		}
+		if x.Kind == ListKind && x.Elem.Kind == DynKind {
+			return y, nil
+		}
+		return x, nil
+	case "-":
+		if x.Kind != IntKind || y.Kind != IntKind {
+			return mismatch()
+		}
+		return Int, nil
+	}
+	return mismatch()
+}
+
+func checkCall(n *callNode, s scope) (*Type, error) {
+	var args []*Type
+	for _, a := range n.args {
+		t, err := check(a, s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, t)
+	}
+
+	if n.recv == nil {
+		if n.fn != "size" {
+			return nil, undefined(n.p, "function", n.fn, "", []string{"size"})
+		}
+		if len(args) != 1 || !sizable(args[0]) {
+			return nil, errorf(n.p, "size expects a string, a list or a map")
+		}
+		return Int, nil
+	}
+
+	recv, err := check(n.recv, s)
+	if err != nil {
+		return nil, err
+	}
+	if recv.Kind != StringKind {
+		if n.fn == "size" && len(args) == 0 && sizable(recv) {
+			return Int, nil
+		}
+		return nil, errorf(n.p, "undefined method %q of %s", n.fn, recv)
+	}
+	m, ok := stringMethods[n.fn]
+	if !ok {
+		var names []string
+		for name := range stringMethods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, undefined(n.p, "method", n.fn, " of string", names)
+	}
+	if len(args) != len(m.args) {
+		return nil, errorf(n.p, "%s expects %d arguments, but got %d", n.fn, len(m.args), len(args))
+	}
+	for i, a := range args {
+		if !a.equal(m.args[i]) {
+			return nil, errorf(n.args[i].pos(), "%s expects %s, but got %s", n.fn, m.args[i], a)
+		}
+	}
+	if n.fn == "matches" {
+		if lit, ok := n.args[0].(*literalNode); ok {
+			re, err := regexp.Compile(lit.value.(string))
+			if err != nil {
+				return nil, errorf(lit.p, "invalid regular expression: %v", err)
+			}
+			n.re = re
+		}
+	}
+	return m.result, nil
+}
+
+func sizable(t *Type) bool {
+	return t.Kind == StringKind || t.Kind == ListKind || t.Kind == MapKind
+}
@@ -0,0 +1,293 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// elementType is the type of any element, which the parents have.
+var elementType = newAnyElementType()
+
+// elementTypes are the types of the elements by their kinds.
+var elementTypes = map[string]*Type{
+	"message": newElementType("message", nil),
+	"field": newElementType("field", map[string]*Type{
+		"type":     String,
+		"key_type": String,
+		"label":    String,
+		"number":   Int,
+	}),
+	"enum": newElementType("enum", nil),
+	"enum_value": newElementType("enum_value", map[string]*Type{
+		"number": Int,
+	}),
+	"service": newElementType("service", nil),
+	"rpc": newElementType("rpc", map[string]*Type{
+		"request_type":     String,
+		"response_type":    String,
+		"client_streaming": Bool,
+		"server_streaming": Bool,
+	}),
+	"oneof":   newElementType("oneof", nil),
+	"package": newElementType("package", nil),
+	"option": newElementType("option", map[string]*Type{
+		"value": String,
+	}),
+}
+
+// newElementType returns the type with the attributes which all the elements have and the specific ones.
+func newElementType(name string, specific map[string]*Type) *Type {
+	fields := make(map[string]*Type)
+	for k, v := range elementType.Fields {
+		fields[k] = v
+	}
+	for k, v := range specific {
+		fields[k] = v
+	}
+	fields["parent"] = elementType
+	return NewObject(name, fields)
+}
+
+// newAnyElementType returns the type with the attributes which all the elements have.
+func newAnyElementType() *Type {
+	t := NewObject("element", map[string]*Type{
+		"kind":      String,
+		"name":      String,
+		"full_name": String,
+		"options":   MapOf(String),
+		"comments":  ListOf(String),
+		"syntax":    String,
+		"package":   String,
+		"file":      String,
+	})
+	t.Fields["parent"] = t
+	return t
+}
+
+// ElementVars returns the types of the variables for the elements of the kind, which are the attributes
+// of the element. It returns false if the kind is unknown.
+//
+// All the elements have kind, name, full_name, options, comments, parent, syntax, package and file.
+// The parent of the top-level elements is the file, whose kind is "file".
+// The fields have type, key_type, label and number, the enum values have number,
+// the rpcs have request_type, response_type, client_streaming and server_streaming,
+// and the options have value.
+func ElementVars(kind string) (map[string]*Type, bool) {
+	t, ok := elementTypes[kind]
+	if !ok {
+		return nil, false
+	}
+	return t.Fields, true
+}
+
+// Elements returns the attributes of the elements of the proto, keyed by the elements.
+// The values are given to Program.Eval for the elements of the kind given to ElementVars.
+func Elements(proto *parser.Proto) map[parser.Visitee]map[string]interface{} {
+	b := &elementsBuilder{
+		syntax:   "proto2",
+		file:     proto.Meta.Filename,
+		elements: make(map[parser.Visitee]map[string]interface{}),
+	}
+	switch {
+	case proto.Edition != nil:
+		b.syntax = "editions"
+	case proto.Syntax != nil:
+		b.syntax = proto.Syntax.ProtobufVersion
+	}
+	for _, v := range proto.ProtoBody {
+		if p, ok := v.(*parser.Package); ok {
+			b.pkg = p.Name
+		}
+	}
+
+	file := b.newElement("file", proto.Meta.Filename, nil, nil, nil)
+	file["full_name"] = b.pkg
+	file["options"] = b.bodyOptions(proto.ProtoBody)
+	b.addBody(proto.ProtoBody, file)
+	return b.elements
+}
+
+type elementsBuilder struct {
+	syntax   string
+	pkg      string
+	file     string
+	elements map[parser.Visitee]map[string]interface{}
+}
+
+func (b *elementsBuilder) newElement(
+	kind string,
+	name string,
+	parent map[string]interface{},
+	comments []*parser.Comment,
+	inline *parser.Comment,
+) map[string]interface{} {
+	fullName := name
+	switch {
+	case parent == nil || kind == "package":
+	case parent["kind"] == "file":
+		if b.pkg != "" {
+			fullName = b.pkg + "." + name
+		}
+	default:
+		fullName = parent["full_name"].(string) + "." + name
+	}
+
+	var lines []interface{}
+	if inline != nil {
+		comments = append(comments[:len(comments):len(comments)], inline)
+	}
+	for _, c := range comments {
+		for _, line := range c.Lines() {
+			if line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*")); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	if lines == nil {
+		lines = []interface{}{}
+	}
+
+	var p interface{}
+	if parent != nil {
+		p = parent
+	}
+	return map[string]interface{}{
+		"kind":      kind,
+		"name":      name,
+		"full_name": fullName,
+		"options":   map[string]interface{}{},
+		"comments":  lines,
+		"parent":    p,
+		"syntax":    b.syntax,
+		"package":   b.pkg,
+		"file":      b.file,
+	}
+}
+
+func (b *elementsBuilder) add(v parser.Visitee, e map[string]interface{}) map[string]interface{} {
+	b.elements[v] = e
+	return e
+}
+
+// bodyOptions returns the option statements in the body.
+func (b *elementsBuilder) bodyOptions(body []parser.Visitee) map[string]interface{} {
+	var options []*parser.Option
+	for _, v := range body {
+		if o, ok := v.(*parser.Option); ok {
+			options = append(options, o)
+		}
+	}
+	return optionsOf(options)
+}
+
+func (b *elementsBuilder) addBody(body []parser.Visitee, parent map[string]interface{}) {
+	for _, v := range body {
+		switch v := v.(type) {
+		case *parser.Package:
+			b.add(v, b.newElement("package", v.Name, parent, v.Comments, v.InlineComment))
+		case *parser.Option:
+			e := b.add(v, b.newElement("option", v.OptionName, parent, v.Comments, v.InlineComment))
+			e["value"] = unquote(v.Constant)
+		case *parser.Message:
+			e := b.add(v, b.newElement("message", v.MessageName, parent, v.Comments, v.InlineComment))
+			e["options"] = b.bodyOptions(v.MessageBody)
+			b.addBody(v.MessageBody, e)
+		case *parser.Field:
+			e := b.add(v, b.newElement("field", v.FieldName, parent, v.Comments, v.InlineComment))
+			label := ""
+			switch {
+			case v.IsRepeated:
+				label = "repeated"
+			case v.IsRequired:
+				label = "required"
+			case v.IsOptional:
+				label = "optional"
+			}
+			b.setField(e, v.Type, "", label, v.FieldNumber, v.FieldOptions)
+		case *parser.MapField:
+			e := b.add(v, b.newElement("field", v.MapName, parent, v.Comments, v.InlineComment))
+			b.setField(e, v.Type, v.KeyType, "", v.FieldNumber, v.FieldOptions)
+		case *parser.Oneof:
+			e := b.add(v, b.newElement("oneof", v.OneofName, parent, v.Comments, v.InlineComment))
+			e["options"] = optionsOf(v.Options)
+			b.addOptions(v.Options, e)
+			for _, f := range v.OneofFields {
+				fe := b.add(f, b.newElement("field", f.FieldName, e, f.Comments, f.InlineComment))
+				b.setField(fe, f.Type, "", "", f.FieldNumber, f.FieldOptions)
+			}
+		case *parser.Enum:
+			e := b.add(v, b.newElement("enum", v.EnumName, parent, v.Comments, v.InlineComment))
+			e["options"] = b.bodyOptions(v.EnumBody)
+			b.addBody(v.EnumBody, e)
+		case *parser.EnumField:
+			e := b.add(v, b.newElement("enum_value", v.Ident, parent, v.Comments, v.InlineComment))
+			e["number"] = parseNumber(v.Number)
+			options := make(map[string]interface{})
+			for _, o := range v.EnumValueOptions {
+				options[o.OptionName] = unquote(o.Constant)
+			}
+			e["options"] = options
+		case *parser.Service:
+			e := b.add(v, b.newElement("service", v.ServiceName, parent, v.Comments, v.InlineComment))
+			e["options"] = b.bodyOptions(v.ServiceBody)
+			b.addBody(v.ServiceBody, e)
+		case *parser.RPC:
+			e := b.add(v, b.newElement("rpc", v.RPCName, parent, v.Comments, v.InlineComment))
+			e["options"] = optionsOf(v.Options)
+			b.addOptions(v.Options, e)
+			e["request_type"] = strings.TrimPrefix(v.RPCRequest.MessageType, ".")
+			e["response_type"] = strings.TrimPrefix(v.RPCResponse.MessageType, ".")
+			e["client_streaming"] = v.RPCRequest.IsStream
+			e["server_streaming"] = v.RPCResponse.IsStream
+		}
+	}
+}
+
+func (b *elementsBuilder) addOptions(options []*parser.Option, parent map[string]interface{}) {
+	for _, o := range options {
+		b.addBody([]parser.Visitee{o}, parent)
+	}
+}
+
+func (b *elementsBuilder) setField(
+	e map[string]interface{},
+	typ, keyType, label, number string,
+	fieldOptions []*parser.FieldOption,
+) {
+	e["type"] = strings.TrimPrefix(typ, ".")
+	e["key_type"] = keyType
+	e["label"] = label
+	e["number"] = parseNumber(number)
+	options := make(map[string]interface{})
+	for _, o := range fieldOptions {
+		options[o.OptionName] = unquote(o.Constant)
+	}
+	e["options"] = options
+}
+
+func optionsOf(options []*parser.Option) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, o := range options {
+		m[o.OptionName] = unquote(o.Constant)
+	}
+	return m
+}
+
+// parseNumber returns the number, or 0 if it is invalid.
+func parseNumber(s string) int64 {
+	n, _ := strconv.ParseInt(s, 0, 64)
+	return n
+}
+
+// unquote removes the quotes of a string constant.
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,257 @@
+package expr
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// eval evaluates the checked node. The runtime errors are limited to the ones which
+// the type checking can't rule out, like an index out of the range or an attribute of the missing parent.
+func eval(n node, vars map[string]interface{}) (interface{}, error) {
+	switch n := n.(type) {
+	case *literalNode:
+		return n.value, nil
+	case *identNode:
+		return vars[n.name], nil
+	case *listNode:
+		list := []interface{}{}
+		for _, e := range n.elems {
+			v, err := eval(e, vars)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case *unaryNode:
+		x, err := eval(n.x, vars)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "-" {
+			return -x.(int64), nil
+		}
+		return !x.(bool), nil
+	case *binaryNode:
+		return evalBinary(n, vars)
+	case *condNode:
+		c, err := eval(n.c, vars)
+		if err != nil {
+			return nil, err
+		}
+		if c.(bool) {
+			return eval(n.t, vars)
+		}
+		return eval(n.f, vars)
+	case *selectNode:
+		x, err := eval(n.x, vars)
+		if err != nil {
+			return nil, err
+		}
+		object, _ := x.(map[string]interface{})
+		if object == nil {
+			return nil, errorf(n.p, "cannot read .%s of null", n.field)
+		}
+		return object[n.field], nil
+	case *indexNode:
+		x, err := eval(n.x, vars)
+		if err != nil {
+			return nil, err
+		}
+		i, err := eval(n.i, vars)
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := x.([]interface{}); ok {
+			index := i.(int64)
+			if index < 0 || int64(len(list)) <= index {
+				return nil, errorf(n.p, "index %d is out of the range of the list of size %d", index, len(list))
+			}
+			return list[index], nil
+		}
+		// A missing key results in the empty string, so that options["deprecated"] == "true" works for any element.
+		v, ok := x.(map[string]interface{})[i.(string)]
+		if !ok {
+			return "", nil
+		}
+		return v, nil
+	case *callNode:
+		return evalCall(n, vars)
+	case *macroNode:
+		recv, err := eval(n.recv, vars)
+		if err != nil {
+			return nil, err
+		}
+		child := make(map[string]interface{}, len(vars)+1)
+		for k, v := range vars {
+			child[k] = v
+		}
+		for _, e := range recv.([]interface{}) {
+			child[n.v] = e
+			v, err := eval(n.body, child)
+			if err != nil {
+				return nil, err
+			}
+			if n.fn == "exists" && v.(bool) {
+				return true, nil
+			}
+			if n.fn == "all" && !v.(bool) {
+				return false, nil
+			}
+		}
+		return n.fn == "all", nil
+	}
+	return nil, errorf(n.pos(), "unknown expression")
+}
+
+func evalBinary(n *binaryNode, vars map[string]interface{}) (interface{}, error) {
+	x, err := eval(n.x, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "&&":
+		if !x.(bool) {
+			return false, nil
+		}
+		return eval(n.y, vars)
+	case "||":
+		if x.(bool) {
+			return true, nil
+		}
+		return eval(n.y, vars)
+	}
+
+	y, err := eval(n.y, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(x, y), nil
+	case "!=":
+		return !reflect.DeepEqual(x, y), nil
+	case "<", "<=", ">", ">=":
+		var c int
+		if xs, ok := x.(string); ok {
+			c = strings.Compare(xs, y.(string))
+		} else {
+			switch xi, yi := x.(int64), y.(int64); {
+			case xi < yi:
+				c = -1
+			case xi > yi:
+				c = 1
+			}
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "in":
+		if m, ok := y.(map[string]interface{}); ok {
+			_, found := m[x.(string)]
+			return found, nil
+		}
+		for _, e := range y.([]interface{}) {
+			if reflect.DeepEqual(x, e) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "+":
+		switch xv := x.(type) {
+		case int64:
+			return xv + y.(int64), nil
+		case string:
+			return xv + y.(string), nil
+		case []interface{}:
+			return append(append([]interface{}(nil), xv...), y.([]interface{})...), nil
+		}
+	case "-":
+		return x.(int64) - y.(int64), nil
+	}
+	return nil, errorf(n.p, "unknown operator %s", n.op)
+}
+
+func evalCall(n *callNode, vars map[string]interface{}) (interface{}, error) {
+	var args []interface{}
+	for _, a := range n.args {
+		v, err := eval(a, vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	if n.recv == nil {
+		return size(n.p, args[0])
+	}
+	recv, err := eval(n.recv, vars)
+	if err != nil {
+		return nil, err
+	}
+	if n.fn == "size" {
+		return size(n.p, recv)
+	}
+	s, ok := recv.(string)
+	if !ok {
+		return nil, errorf(n.p, "cannot call %s of %s", n.fn, typeNameOf(recv))
+	}
+	switch n.fn {
+	case "startsWith":
+		return strings.HasPrefix(s, args[0].(string)), nil
+	case "endsWith":
+		return strings.HasSuffix(s, args[0].(string)), nil
+	case "contains":
+		return strings.Contains(s, args[0].(string)), nil
+	case "matches":
+		re := n.re
+		if re == nil {
+			re, err = regexp.Compile(args[0].(string))
+			if err != nil {
+				return nil, errorf(n.p, "invalid regular expression: %v", err)
+			}
+		}
+		return re.MatchString(s), nil
+	case "lowerAscii":
+		return strings.ToLower(s), nil
+	case "upperAscii":
+		return strings.ToUpper(s), nil
+	}
+	return nil, errorf(n.p, "unknown method %s", n.fn)
+}
+
+func size(p int, v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return int64(len([]rune(v))), nil
+	case []interface{}:
+		return int64(len(v)), nil
+	case map[string]interface{}:
+		return int64(len(v)), nil
+	}
+	return nil, errorf(p, "cannot get the size of %s", typeNameOf(v))
+}
+
+// typeNameOf returns the name of the type of the value in the errors.
+func typeNameOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	}
+	return "map"
+}
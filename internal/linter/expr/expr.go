@@ -0,0 +1,50 @@
+// Package expr implements a small predicate language like CEL, which the custom rules evaluate over the elements.
+//
+// An expression supports the literals like "foo", 'foo', 1, true, null and ["a", "b"], the operators
+// !, -, &&, ||, ==, !=, <, <=, >, >=, in, + and ?:, the member accesses like parent.name and options["deprecated"],
+// the methods of the strings startsWith, endsWith, contains, matches, lowerAscii, upperAscii and size,
+// the function size, and the macros of the lists exists and all like comments.exists(c, c.contains("TODO")).
+//
+// The expressions are type-checked when they are compiled.
+package expr
+
+import (
+	"fmt"
+)
+
+// Program is a compiled predicate.
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile parses and type-checks the source with the types of the variables.
+// The source must evaluate to bool.
+func Compile(source string, vars map[string]*Type) (*Program, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+	t, err := check(root, scope(vars))
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind != BoolKind {
+		return nil, errorf(1, "the expression must evaluate to bool, but got %s", t)
+	}
+	return &Program{source: source, root: root}, nil
+}
+
+// String returns the source.
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval evaluates the predicate with the values of the variables, which must match the types given to Compile.
+func (p *Program) Eval(vars map[string]interface{}) (bool, error) {
+	v, err := eval(p.root, vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate %q: %w", p.source, err)
+	}
+	return v.(bool), nil
+}
@@ -0,0 +1,302 @@
+package expr_test
+
+import (
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/linter/expr"
+)
+
+const testProto = `syntax = "proto3";
+package example.v1;
+
+import "google/protobuf/timestamp.proto";
+
+// Book is a book.
+message Book {
+  option deprecated = true;
+
+  // TODO: rename.
+  google.protobuf.Timestamp created = 1;
+  repeated string tags = 2 [deprecated = true];
+  map<string, int32> counts = 3;
+  oneof source {
+    string url = 4;
+  }
+}
+
+enum Color {
+  COLOR_UNSPECIFIED = 0;
+  COLOR_RED = 1;
+}
+
+service BookService {
+  rpc ListBooks(ListBooksRequest) returns (stream Book);
+}
+`
+
+func parseTestProto(t *testing.T) map[parser.Visitee]map[string]interface{} {
+	proto, err := protoparser.Parse(strings.NewReader(testProto), protoparser.WithFilename("example.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return expr.Elements(proto)
+}
+
+func TestProgram_Eval(t *testing.T) {
+	elements := parseTestProto(t)
+	find := func(kind, name string) map[string]interface{} {
+		for _, e := range elements {
+			if e["kind"] == kind && e["name"] == name {
+				return e
+			}
+		}
+		t.Fatalf("%s %s not found", kind, name)
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		inputKind   string
+		inputName   string
+		inputSource string
+		wantResult  bool
+	}{
+		{
+			name:        "timestamp fields end with _time",
+			inputKind:   "field",
+			inputName:   "created",
+			inputSource: `type != "google.protobuf.Timestamp" || name.endsWith("_time")`,
+		},
+		{
+			name:        "streaming rpcs contain Stream",
+			inputKind:   "rpc",
+			inputName:   "ListBooks",
+			inputSource: `!(client_streaming || server_streaming) || name.contains("Stream")`,
+		},
+		{
+			name:        "label, number and options",
+			inputKind:   "field",
+			inputName:   "tags",
+			inputSource: `label == "repeated" && number == 2 && options["deprecated"] == "true" && "deprecated" in options`,
+			wantResult:  true,
+		},
+		{
+			name:        "missing option is an empty string",
+			inputKind:   "field",
+			inputName:   "created",
+			inputSource: `options["deprecated"] == "" && !("deprecated" in options)`,
+			wantResult:  true,
+		},
+		{
+			name:        "map field",
+			inputKind:   "field",
+			inputName:   "counts",
+			inputSource: `key_type == "string" && type == "int32" && label == ""`,
+			wantResult:  true,
+		},
+		{
+			name:        "comments",
+			inputKind:   "field",
+			inputName:   "created",
+			inputSource: `comments.exists(c, c.startsWith("TODO")) && size(comments) == 1`,
+			wantResult:  true,
+		},
+		{
+			name:        "parent and file",
+			inputKind:   "field",
+			inputName:   "url",
+			inputSource: `parent.kind == "oneof" && parent.parent.name == "Book" && parent.parent.options["deprecated"] == "true" && syntax == "proto3" && file == "example.proto"`,
+			wantResult:  true,
+		},
+		{
+			name:        "full names",
+			inputKind:   "field",
+			inputName:   "url",
+			inputSource: `full_name == "example.v1.Book.source.url" && package == "example.v1" && parent.parent.parent.kind == "file"`,
+			wantResult:  true,
+		},
+		{
+			name:        "enum values",
+			inputKind:   "enum_value",
+			inputName:   "COLOR_RED",
+			inputSource: `name.startsWith(parent.name.upperAscii() + "_") && number > 0 && number in [1, 2]`,
+			wantResult:  true,
+		},
+		{
+			name:        "matches and ternary",
+			inputKind:   "message",
+			inputName:   "Book",
+			inputSource: `name.matches("^[A-Z][a-zA-Z0-9]*$") ? comments.all(c, c.size() > 0) : false`,
+			wantResult:  true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			vars, _ := expr.ElementVars(test.inputKind)
+			program, err := expr.Compile(test.inputSource, vars)
+			if err != nil {
+				t.Fatalf("got err %v", err)
+			}
+			got, err := program.Eval(find(test.inputKind, test.inputName))
+			if err != nil {
+				t.Fatalf("got err %v", err)
+			}
+			if got != test.wantResult {
+				t.Errorf("got %v, but want %v", got, test.wantResult)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name        string
+		inputKind   string
+		inputSource string
+		wantErr     string
+	}{
+		{
+			name:        "misspelled attribute",
+			inputKind:   "field",
+			inputSource: `nmae.endsWith("_time")`,
+			wantErr:     `column 1: undefined variable "nmae"; did you mean "name"?`,
+		},
+		{
+			name:        "attribute of another kind",
+			inputKind:   "message",
+			inputSource: `server_streaming`,
+			wantErr:     `column 1: undefined variable "server_streaming"; available: comments, file, full_name, kind, name, options, package, parent, syntax`,
+		},
+		{
+			name:        "attribute of the parent",
+			inputKind:   "field",
+			inputSource: `parent.type == "string"`,
+			wantErr:     `column 8: undefined attribute "type" of element; available: comments, file, full_name, kind, name, options, package, parent, syntax`,
+		},
+		{
+			name:        "type mismatch",
+			inputKind:   "field",
+			inputSource: `number == "1"`,
+			wantErr:     `column 8: == is not defined for int and string`,
+		},
+		{
+			name:        "not bool",
+			inputKind:   "field",
+			inputSource: `name + "_time"`,
+			wantErr:     `column 1: the expression must evaluate to bool, but got string`,
+		},
+		{
+			name:        "unknown method",
+			inputKind:   "field",
+			inputSource: `name.endWith("_time")`,
+			wantErr:     `column 6: undefined method "endWith" of string; did you mean "endsWith"?`,
+		},
+		{
+			name:        "invalid regular expression",
+			inputKind:   "field",
+			inputSource: `name.matches("(")`,
+			wantErr:     "column 14: invalid regular expression: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:        "syntax error",
+			inputKind:   "field",
+			inputSource: `name.endsWith("_time" &&`,
+			wantErr:     `column 25: unexpected end of the expression`,
+		},
+		{
+			name:        "unterminated string",
+			inputKind:   "field",
+			inputSource: `name == "foo`,
+			wantErr:     `column 9: unterminated string`,
+		},
+		{
+			name:        "empty",
+			inputKind:   "field",
+			inputSource: ` `,
+			wantErr:     `column 1: empty expression`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			vars, ok := expr.ElementVars(test.inputKind)
+			if !ok {
+				t.Fatalf("unknown kind %s", test.inputKind)
+			}
+			_, err := expr.Compile(test.inputSource, vars)
+			if err == nil {
+				t.Fatal("got nil, but want an error")
+			}
+			if err.Error() != test.wantErr {
+				t.Errorf("got %q, but want %q", err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestProgram_Eval_error(t *testing.T) {
+	elements := parseTestProto(t)
+	var book map[string]interface{}
+	for _, e := range elements {
+		if e["kind"] == "message" && e["name"] == "Book" {
+			book = e
+		}
+	}
+
+	tests := []struct {
+		name        string
+		inputSource string
+		inputVars   map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "attribute of the parent of the file",
+			inputSource: `parent.parent.name == "Book"`,
+			inputVars:   book,
+			wantErr:     `failed to evaluate "parent.parent.name == \"Book\"": column 15: cannot read .name of null`,
+		},
+		{
+			name:        "index out of the range",
+			inputSource: `comments[1] == ""`,
+			inputVars:   book,
+			wantErr:     `failed to evaluate "comments[1] == \"\"": column 9: index 1 is out of the range of the list of size 1`,
+		},
+		{
+			name:        "size of the value not matching the type",
+			inputSource: `size(comments) == 0`,
+			inputVars:   map[string]interface{}{},
+			wantErr:     `failed to evaluate "size(comments) == 0": column 1: cannot get the size of null`,
+		},
+		{
+			name:        "method of the value not matching the type",
+			inputSource: `name.startsWith("B")`,
+			inputVars:   map[string]interface{}{},
+			wantErr:     `failed to evaluate "name.startsWith(\"B\")": column 6: cannot call startsWith of null`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			vars, _ := expr.ElementVars("message")
+			program, err := expr.Compile(test.inputSource, vars)
+			if err != nil {
+				t.Fatalf("got err %v", err)
+			}
+			_, err = program.Eval(test.inputVars)
+			if err == nil {
+				t.Fatal("got nil, but want an error")
+			}
+			if err.Error() != test.wantErr {
+				t.Errorf("got %q, but want %q", err.Error(), test.wantErr)
+			}
+		})
+	}
+}
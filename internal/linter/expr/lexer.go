@@ -0,0 +1,118 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenInt
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value is the unquoted string or the parsed int.
+	value interface{}
+	// pos is the 1-based column of the token.
+	pos int
+}
+
+// operators are sorted so that the longer ones are tried first.
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"(", ")", "[", "]", ",", ".", "!", "<", ">", "+", "-", "?", ":",
+}
+
+// tokenize splits the source into the tokens.
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:j]), pos: pos})
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			text := string(runes[i:j])
+			n, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, errorf(pos, "invalid number %s", text)
+			}
+			tokens = append(tokens, token{kind: tokenInt, text: text, value: n, pos: pos})
+			i = j
+		case r == '"' || r == '\'':
+			s, n, err := scanString(runes[i:], pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i : i+n]), value: s, pos: pos})
+			i += n
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(runes[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, errorf(pos, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: pos})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// scanString scans the quoted string at the head of runes, and returns it unquoted with the number of the runes.
+func scanString(runes []rune, pos int) (string, int, error) {
+	quote := runes[0]
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if len(runes) <= i {
+				break
+			}
+			switch e := runes[i]; e {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case '\\', '"', '\'':
+				b.WriteRune(e)
+			default:
+				// Keep the unknown escapes like \d for the regular expressions.
+				b.WriteRune('\\')
+				b.WriteRune(e)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", 0, errorf(pos, "unterminated string")
+}
@@ -0,0 +1,363 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Error is an error found at a column of the expression.
+type Error struct {
+	// Pos is the 1-based column.
+	Pos int
+	Msg string
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+func errorf(pos int, format string, a ...interface{}) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+// node is a node of the syntax tree.
+type node interface {
+	pos() int
+}
+
+type literalNode struct {
+	p     int
+	value interface{}
+	typ   *Type
+}
+
+type identNode struct {
+	p    int
+	name string
+}
+
+type listNode struct {
+	p     int
+	elems []node
+}
+
+type unaryNode struct {
+	p  int
+	op string
+	x  node
+}
+
+type binaryNode struct {
+	p    int
+	op   string
+	x, y node
+}
+
+type condNode struct {
+	p       int
+	c, t, f node
+}
+
+type selectNode struct {
+	p     int
+	x     node
+	field string
+}
+
+type indexNode struct {
+	p    int
+	x, i node
+}
+
+// callNode is a call of a function or a method. recv is nil for a function.
+type callNode struct {
+	p    int
+	recv node
+	fn   string
+	args []node
+	// re is the compiled regular expression given to matches as a literal.
+	re *regexp.Regexp
+}
+
+// macroNode is a call of exists or all, which binds each element of recv to the variable v in body.
+type macroNode struct {
+	p    int
+	recv node
+	fn   string
+	v    string
+	body node
+}
+
+func (n *literalNode) pos() int { return n.p }
+func (n *identNode) pos() int   { return n.p }
+func (n *listNode) pos() int    { return n.p }
+func (n *unaryNode) pos() int   { return n.p }
+func (n *binaryNode) pos() int  { return n.p }
+func (n *condNode) pos() int    { return n.p }
+func (n *selectNode) pos() int  { return n.p }
+func (n *indexNode) pos() int   { return n.p }
+func (n *callNode) pos() int    { return n.p }
+func (n *macroNode) pos() int   { return n.p }
+
+// exprParser is a recursive descent parser. The precedence from the lowest is
+// ?:, ||, &&, the comparisons and in, + and -, the unary operators and the member accesses.
+type exprParser struct {
+	tokens []token
+	i      int
+}
+
+func parse(source string) (node, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, errorf(1, "empty expression")
+	}
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorf(t.pos, "unexpected %s", describe(t))
+	}
+	return n, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator or the keyword.
+func (p *exprParser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenOperator || t.kind == tokenIdent) && t.text == text {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(text string) error {
+	if p.accept(text) {
+		return nil
+	}
+	t := p.peek()
+	return errorf(t.pos, "expected %q, but got %s", text, describe(t))
+}
+
+func describe(t token) string {
+	if t.kind == tokenEOF {
+		return "end of the expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (p *exprParser) parseExpr() (node, error) {
+	c, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	pos := p.peek().pos
+	if !p.accept("?") {
+		return c, nil
+	}
+	t, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	f, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &condNode{p: pos, c: c, t: t, f: f}, nil
+}
+
+// binaryLevels are the binary operators from the lowest precedence.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+}
+
+func (p *exprParser) parseBinary(level int) (node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		op := ""
+		for _, o := range binaryLevels[level] {
+			if (t.kind == tokenOperator || t.kind == tokenIdent) && t.text == o {
+				op = o
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryNode{p: t.pos, op: op, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	t := p.peek()
+	if p.accept("!") || p.accept("-") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{p: t.pos, op: t.text, x: x}, nil
+	}
+	return p.parseMember()
+}
+
+func (p *exprParser) parseMember() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case p.accept("."):
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, errorf(name.pos, "expected an attribute or a method after \".\", but got %s", describe(name))
+			}
+			if p.peek().text != "(" || p.peek().kind != tokenOperator {
+				x = &selectNode{p: name.pos, x: x, field: name.text}
+				continue
+			}
+			x, err = p.parseCall(name, x)
+			if err != nil {
+				return nil, err
+			}
+		case p.accept("["):
+			i, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexNode{p: t.pos, x: x, i: i}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// parseCall parses the arguments of the function or the method name.
+func (p *exprParser) parseCall(name token, recv node) (node, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if recv != nil && (name.text == "exists" || name.text == "all") {
+		v := p.next()
+		if v.kind != tokenIdent {
+			return nil, errorf(v.pos, "the first argument of %s must be a variable name, but got %s", name.text, describe(v))
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		body, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &macroNode{p: name.pos, recv: recv, fn: name.text, v: v.text, body: body}, nil
+	}
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	return &callNode{p: name.pos, recv: recv, fn: name.text, args: args}, nil
+}
+
+// parseList parses the comma separated expressions until the closing operator.
+func (p *exprParser) parseList(closing string) ([]node, error) {
+	var elems []node
+	if p.accept(closing) {
+		return elems, nil
+	}
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, e)
+		if p.accept(closing) {
+			return elems, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt:
+		return &literalNode{p: t.pos, value: t.value, typ: Int}, nil
+	case tokenString:
+		return &literalNode{p: t.pos, value: t.value, typ: String}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &literalNode{p: t.pos, value: t.text == "true", typ: Bool}, nil
+		case "null":
+			return &literalNode{p: t.pos, value: nil, typ: Null}, nil
+		case "in":
+			return nil, errorf(t.pos, "unexpected %s", describe(t))
+		}
+		if p.peek().kind == tokenOperator && p.peek().text == "(" {
+			return p.parseCall(t, nil)
+		}
+		return &identNode{p: t.pos, name: t.text}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			elems, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{p: t.pos, elems: elems}, nil
+		}
+	}
+	return nil, errorf(t.pos, "unexpected %s", describe(t))
+}
@@ -0,0 +1,142 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a type.
+type Kind int
+
+// The kinds of the types.
+const (
+	BoolKind Kind = iota + 1
+	IntKind
+	StringKind
+	NullKind
+	ListKind
+	MapKind
+	ObjectKind
+	// DynKind is the kind of the elements of an empty list, which match any type.
+	DynKind
+)
+
+// Type is the type of a value.
+// The values are represented as bool, int64, string, nil, []interface{} and map[string]interface{} in Go.
+// The objects are also represented as map[string]interface{}.
+type Type struct {
+	Kind Kind
+	// Elem is the type of the elements of a list or the values of a map, whose keys are strings.
+	Elem *Type
+	// Name is the name of an object type.
+	Name string
+	// Fields are the attributes of an object type.
+	Fields map[string]*Type
+}
+
+// The primitive types.
+var (
+	Bool   = &Type{Kind: BoolKind}
+	Int    = &Type{Kind: IntKind}
+	String = &Type{Kind: StringKind}
+	Null   = &Type{Kind: NullKind}
+	Dyn    = &Type{Kind: DynKind}
+)
+
+// ListOf returns the type of the lists of elem.
+func ListOf(elem *Type) *Type {
+	return &Type{Kind: ListKind, Elem: elem}
+}
+
+// MapOf returns the type of the maps from strings to elem.
+func MapOf(elem *Type) *Type {
+	return &Type{Kind: MapKind, Elem: elem}
+}
+
+// NewObject returns an object type with the attributes.
+func NewObject(name string, fields map[string]*Type) *Type {
+	return &Type{Kind: ObjectKind, Name: name, Fields: fields}
+}
+
+// String returns the name of the type like list<string>.
+func (t *Type) String() string {
+	switch t.Kind {
+	case BoolKind:
+		return "bool"
+	case IntKind:
+		return "int"
+	case StringKind:
+		return "string"
+	case NullKind:
+		return "null"
+	case ListKind:
+		return fmt.Sprintf("list<%s>", t.Elem)
+	case MapKind:
+		return fmt.Sprintf("map<string, %s>", t.Elem)
+	case ObjectKind:
+		return t.Name
+	default:
+		return "dyn"
+	}
+}
+
+// equal reports whether the values of t and u can be compared with each other.
+func (t *Type) equal(u *Type) bool {
+	if t.Kind == DynKind || u.Kind == DynKind {
+		return true
+	}
+	if t.Kind != u.Kind {
+		return false
+	}
+	switch t.Kind {
+	case ListKind, MapKind:
+		return t.Elem.equal(u.Elem)
+	case ObjectKind:
+		return t.Name == u.Name
+	default:
+		return true
+	}
+}
+
+// fieldNames returns the sorted names of the attributes.
+func (t *Type) fieldNames() []string {
+	var names []string
+	for name := range t.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// suggest returns the candidate closest to name, or an empty string if none of them is close enough.
+func suggest(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, c := range candidates {
+		if d := distance(strings.ToLower(name), strings.ToLower(c)); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
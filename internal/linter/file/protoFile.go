@@ -1,10 +1,10 @@
 package file
 
 import (
-	"os"
-
 	protoparser "github.com/yoheimuta/go-protoparser/v4"
 	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/osutil"
 )
 
 // ProtoFile is a Protocol Buffer file.
@@ -34,7 +34,7 @@ func NewProtoFile(
 func (f ProtoFile) Parse(
 	debug bool,
 ) (_ *parser.Proto, err error) {
-	reader, err := os.Open(f.path)
+	reader, err := osutil.Open(f.path)
 	if err != nil {
 		return nil, err
 	}
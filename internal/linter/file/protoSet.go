@@ -36,18 +36,10 @@ func (s ProtoSet) ProtoFiles() []ProtoFile {
 func collectAllProtoFilesFromArgs(
 	targetPaths []string,
 ) ([]ProtoFile, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-	absCwd, err := absClean(cwd)
+	absCwd, err := absWorkDir()
 	if err != nil {
 		return nil, err
 	}
-	// Eval a possible symlink for the cwd to calculate the correct relative paths in the next step.
-	if newPath, err := filepath.EvalSymlinks(absCwd); err == nil {
-		absCwd = newPath
-	}
 
 	var fs []ProtoFile
 	for _, path := range targetPaths {
@@ -81,12 +73,7 @@ func collectAllProtoFiles(
 				return nil
 			}
 
-			displayPath, err := filepath.Rel(absWorkDirPath, path)
-			if err != nil {
-				displayPath = path
-			}
-			displayPath = filepath.Clean(displayPath)
-			fs = append(fs, NewProtoFile(path, displayPath))
+			fs = append(fs, NewProtoFile(path, displayPathOf(absWorkDirPath, path)))
 			return nil
 		},
 	)
@@ -96,6 +83,49 @@ func collectAllProtoFiles(
 	return fs, nil
 }
 
+// NewProtoFileFromPath creates a new proto file from the path, which doesn't have to exist.
+// The display path is calculated in the same way as NewProtoSet.
+func NewProtoFileFromPath(
+	path string,
+) (ProtoFile, error) {
+	absCwd, err := absWorkDir()
+	if err != nil {
+		return ProtoFile{}, err
+	}
+	absPath, err := absClean(path)
+	if err != nil {
+		return ProtoFile{}, err
+	}
+	return NewProtoFile(absPath, displayPathOf(absCwd, absPath)), nil
+}
+
+func absWorkDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	absCwd, err := absClean(cwd)
+	if err != nil {
+		return "", err
+	}
+	// Eval a possible symlink for the cwd to calculate the correct relative paths in the next step.
+	if newPath, err := filepath.EvalSymlinks(absCwd); err == nil {
+		absCwd = newPath
+	}
+	return absCwd, nil
+}
+
+func displayPathOf(
+	absWorkDirPath string,
+	path string,
+) string {
+	displayPath, err := filepath.Rel(absWorkDirPath, path)
+	if err != nil {
+		displayPath = path
+	}
+	return filepath.Clean(displayPath)
+}
+
 // absClean returns the cleaned absolute path of the given path.
 func absClean(path string) (string, error) {
 	if path == "" {
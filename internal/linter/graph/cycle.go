@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// Cycle is an import cycle of the files.
+type Cycle struct {
+	// Import is the import statement of the first file, which starts the cycle.
+	Import symbol.Import
+	// Files are the import paths of the files in the cycle, which ends with the first one again.
+	Files []string
+}
+
+// CyclesFrom returns the import cycles starting at the file of the path.
+//
+// A cycle is returned only from the file whose path comes first among the files in the cycle,
+// so that each cycle is found once when the function is called for all files.
+// The cycles are sorted by the positions of the import statements starting them.
+func CyclesFrom(
+	symbols *symbol.Table,
+	path string,
+) []Cycle {
+	start, ok := symbols.File(path)
+	if !ok {
+		return nil
+	}
+
+	var cycles []Cycle
+	onPath := map[string]bool{start.Path: true}
+	var stack []*symbol.File
+	var visit func(f *symbol.File, first symbol.Import)
+	visit = func(f *symbol.File, first symbol.Import) {
+		stack = append(stack, f)
+		defer func() { stack = stack[:len(stack)-1] }()
+
+		for _, next := range importedFiles(symbols, f) {
+			imp := first
+			if f == start {
+				imp = importOf(f, next.Path)
+			}
+			switch {
+			case next.Path == start.Path:
+				files := make([]string, 0, len(stack)+1)
+				for _, s := range stack {
+					files = append(files, s.ImportPath)
+				}
+				files = append(files, start.ImportPath)
+				cycles = append(cycles, Cycle{
+					Import: imp,
+					Files:  files,
+				})
+			case next.Path < start.Path || onPath[next.Path]:
+				continue
+			default:
+				onPath[next.Path] = true
+				visit(next, imp)
+				onPath[next.Path] = false
+			}
+		}
+	}
+	visit(start, symbol.Import{})
+	sort.SliceStable(cycles, func(i, j int) bool {
+		return cycles[i].Import.Pos.Offset < cycles[j].Import.Pos.Offset
+	})
+	return cycles
+}
+
+// importedFiles returns the files imported by the file, sorted by their paths.
+func importedFiles(symbols *symbol.Table, f *symbol.File) []*symbol.File {
+	seen := make(map[string]bool)
+	var files []*symbol.File
+	for _, imp := range f.Imports {
+		imported, ok := symbols.File(imp.File)
+		if !ok || seen[imported.Path] {
+			continue
+		}
+		seen[imported.Path] = true
+		files = append(files, imported)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+func importOf(f *symbol.File, path string) symbol.Import {
+	for _, imp := range f.Imports {
+		if imp.File == path {
+			return imp
+		}
+	}
+	return symbol.Import{}
+}
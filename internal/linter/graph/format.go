@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseLevel returns the Level of the name, "file" or "package".
+func ParseLevel(name string) (Level, error) {
+	switch name {
+	case "file":
+		return FileLevel, nil
+	case "package":
+		return PackageLevel, nil
+	}
+	return FileLevel, fmt.Errorf(`invalid level %q. Available levels are "file" and "package"`, name)
+}
+
+// Writer writes the graph in a specific format.
+type Writer func(w io.Writer, g Graph) error
+
+// GetWriter returns the Writer of the format name, "dot" or "json".
+func GetWriter(format string) (Writer, error) {
+	switch format {
+	case "dot":
+		return WriteDOT, nil
+	case "json":
+		return WriteJSON, nil
+	}
+	return nil, fmt.Errorf(`invalid format %q. Available formats are "dot" and "json"`, format)
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz.
+// The external nodes are dashed, and the unresolved ones are red.
+func WriteDOT(w io.Writer, g Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph imports {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		var attrs []string
+		if n.IsExternal {
+			attrs = append(attrs, "style=dashed")
+		}
+		if n.IsUnresolved {
+			attrs = append(attrs, "color=red")
+		}
+		b.WriteString("  " + quoteDOT(n.ID) + dotAttrs(attrs) + ";\n")
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.IsPublic {
+			attrs = append(attrs, `label="public"`)
+		}
+		if e.IsWeak {
+			attrs = append(attrs, `label="weak"`, "style=dotted")
+		}
+		b.WriteString("  " + quoteDOT(e.From) + " -> " + quoteDOT(e.To) + dotAttrs(attrs) + ";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotAttrs(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+func quoteDOT(id string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(id, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// WriteJSON writes the graph in JSON.
+func WriteJSON(w io.Writer, g Graph) error {
+	bs, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bs))
+	return err
+}
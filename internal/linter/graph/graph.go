@@ -0,0 +1,152 @@
+// Package graph provides the import graph of the Protocol Buffer files.
+package graph
+
+import (
+	"sort"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// Level is the granularity of the nodes in the graph.
+type Level int
+
+// Level constants.
+const (
+	FileLevel Level = iota
+	PackageLevel
+)
+
+// Node is a file or a package.
+type Node struct {
+	// ID is the import path of the file or the package name.
+	ID string `json:"id"`
+	// Package is the package name of the file. It's empty at the package level.
+	Package string `json:"package,omitempty"`
+	// IsExternal reports whether the file isn't one of the given files,
+	// or the package has none of them.
+	IsExternal bool `json:"external,omitempty"`
+	// IsUnresolved reports whether the imported file isn't found.
+	IsUnresolved bool `json:"unresolved,omitempty"`
+}
+
+// Edge is an import from a node to another.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	IsPublic bool   `json:"public,omitempty"`
+	IsWeak   bool   `json:"weak,omitempty"`
+}
+
+// Graph is the directed import graph.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// NoPackage is the node ID of the files without the package statement at the package level.
+const NoPackage = "(no package)"
+
+// New creates the import graph of the files at the paths, which the symbol table has.
+// The imported files are included as the external nodes, but their imports aren't.
+func New(
+	symbols *symbol.Table,
+	paths []string,
+	level Level,
+) Graph {
+	b := &builder{
+		level: level,
+		nodes: make(map[string]Node),
+		edges: make(map[Edge]bool),
+	}
+	for _, path := range paths {
+		f, ok := symbols.File(path)
+		if !ok {
+			continue
+		}
+		from := b.addNode(f, false)
+		for _, imp := range f.Imports {
+			imported, ok := symbols.File(imp.File)
+			var to string
+			if ok {
+				to = b.addNode(imported, true)
+			} else {
+				to = b.addUnresolvedNode(imp.Path)
+			}
+			if level == PackageLevel && from == to {
+				continue
+			}
+			b.edges[Edge{
+				From:     from,
+				To:       to,
+				IsPublic: imp.IsPublic && level == FileLevel,
+				IsWeak:   imp.IsWeak && level == FileLevel,
+			}] = true
+		}
+	}
+	return b.graph()
+}
+
+type builder struct {
+	level Level
+	nodes map[string]Node
+	edges map[Edge]bool
+}
+
+func (b *builder) addNode(f *symbol.File, isExternal bool) string {
+	n := Node{
+		ID:         f.ImportPath,
+		Package:    f.Package,
+		IsExternal: isExternal,
+	}
+	if b.level == PackageLevel {
+		n = Node{
+			ID:         f.Package,
+			IsExternal: isExternal,
+		}
+		if n.ID == "" {
+			n.ID = NoPackage
+		}
+	}
+	if old, ok := b.nodes[n.ID]; ok {
+		n.IsExternal = n.IsExternal && old.IsExternal
+	}
+	b.nodes[n.ID] = n
+	return n.ID
+}
+
+func (b *builder) addUnresolvedNode(importPath string) string {
+	if _, ok := b.nodes[importPath]; !ok {
+		b.nodes[importPath] = Node{
+			ID:           importPath,
+			IsExternal:   true,
+			IsUnresolved: true,
+		}
+	}
+	return importPath
+}
+
+func (b *builder) graph() Graph {
+	g := Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	for _, n := range b.nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	for e := range b.edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		if g.Edges[i].To != g.Edges[j].To {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return !g.Edges[i].IsPublic && g.Edges[j].IsPublic
+	})
+	return g
+}
@@ -0,0 +1,164 @@
+package graph_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/graph"
+	"github.com/yoheimuta/protolint/internal/util_test"
+)
+
+var testFiles = map[string]string{
+	"a/a.proto": `syntax = "proto3";
+package a;
+import "a/c.proto";
+import "b/b.proto";
+`,
+	"a/c.proto": `syntax = "proto3";
+package a;
+import public "b/b.proto";
+`,
+	"b/b.proto": `syntax = "proto3";
+package b;
+import "a/a.proto";
+import weak "missing.proto";
+`,
+	"d.proto": `syntax = "proto3";
+import "b/b.proto";
+`,
+}
+
+func TestNew(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+
+	tests := []struct {
+		name       string
+		inputPaths []string
+		inputLevel graph.Level
+		want       graph.Graph
+	}{
+		{
+			name:       "file level",
+			inputPaths: []string{"a/c.proto", "b/b.proto"},
+			inputLevel: graph.FileLevel,
+			want: graph.Graph{
+				Nodes: []graph.Node{
+					{ID: "a/a.proto", Package: "a", IsExternal: true},
+					{ID: "a/c.proto", Package: "a"},
+					{ID: "b/b.proto", Package: "b"},
+					{ID: "missing.proto", IsExternal: true, IsUnresolved: true},
+				},
+				Edges: []graph.Edge{
+					{From: "a/c.proto", To: "b/b.proto", IsPublic: true},
+					{From: "b/b.proto", To: "a/a.proto"},
+					{From: "b/b.proto", To: "missing.proto", IsWeak: true},
+				},
+			},
+		},
+		{
+			name:       "package level",
+			inputPaths: []string{"a/a.proto", "a/c.proto", "b/b.proto", "d.proto"},
+			inputLevel: graph.PackageLevel,
+			want: graph.Graph{
+				Nodes: []graph.Node{
+					{ID: graph.NoPackage},
+					{ID: "a"},
+					{ID: "b"},
+					{ID: "missing.proto", IsExternal: true, IsUnresolved: true},
+				},
+				Edges: []graph.Edge{
+					{From: graph.NoPackage, To: "b"},
+					{From: "a", To: "b"},
+					{From: "b", To: "a"},
+					{From: "b", To: "missing.proto"},
+				},
+			},
+		},
+		{
+			name:       "no files",
+			inputLevel: graph.FileLevel,
+			want: graph.Graph{
+				Nodes: []graph.Node{},
+				Edges: []graph.Edge{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got := graph.New(table, test.inputPaths, test.inputLevel)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, but want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCyclesFrom(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+
+	tests := []struct {
+		name       string
+		inputPath  string
+		wantCycles [][]string
+	}{
+		{
+			name:      "the cycles from the file whose path comes first",
+			inputPath: "a/a.proto",
+			wantCycles: [][]string{
+				{"a/a.proto", "a/c.proto", "b/b.proto", "a/a.proto"},
+				{"a/a.proto", "b/b.proto", "a/a.proto"},
+			},
+		},
+		{
+			name:      "no cycles from the other files in the cycles",
+			inputPath: "b/b.proto",
+		},
+		{
+			name:      "no cycles from the file out of the cycles",
+			inputPath: "d.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var got [][]string
+			for _, c := range graph.CyclesFrom(table, test.inputPath) {
+				got = append(got, c.Files)
+			}
+			if !reflect.DeepEqual(got, test.wantCycles) {
+				t.Errorf("got %v, but want %v", got, test.wantCycles)
+			}
+		})
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := graph.Graph{
+		Nodes: []graph.Node{
+			{ID: "a.proto"},
+			{ID: `b"c.proto`, IsExternal: true, IsUnresolved: true},
+		},
+		Edges: []graph.Edge{
+			{From: "a.proto", To: `b"c.proto`, IsPublic: true},
+		},
+	}
+	want := `digraph imports {
+  node [shape=box];
+  "a.proto";
+  "b\"c.proto" [style=dashed, color=red];
+  "a.proto" -> "b\"c.proto" [label="public"];
+}
+`
+
+	var got bytes.Buffer
+	if err := graph.WriteDOT(&got, g); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want {
+		t.Errorf("got %s, but want %s", got.String(), want)
+	}
+}
@@ -1,21 +1,61 @@
 package linter
 
 import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"unicode/utf8"
+
 	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
 
+	"github.com/yoheimuta/protolint/internal/diffutil"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalsymbol "github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/fixer"
 	"github.com/yoheimuta/protolint/linter/report"
 	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
 )
 
 // Linter represents the protocol buffer linter with some rules.
-type Linter struct{}
+type Linter struct {
+	symbols *symbol.Table
+}
 
 // NewLinter creates a new Linter.
 func NewLinter() *Linter {
 	return &Linter{}
 }
 
+// WithSymbols returns the linter which gives the symbol table to the rules implementing rule.HasApplyWithSymbols.
+// Without it, RunFile builds the table from each file and its imports.
+func (l *Linter) WithSymbols(symbols *symbol.Table) *Linter {
+	return &Linter{
+		symbols: symbols,
+	}
+}
+
+// Symbols returns the symbol table given by WithSymbols.
+func (l *Linter) Symbols() *symbol.Table {
+	return l.symbols
+}
+
+// NeedsSymbols reports whether any of the rules needs the symbol table.
+func NeedsSymbols(hasApplies []rule.HasApply) bool {
+	for _, hasApply := range hasApplies {
+		if _, ok := hasApply.(rule.HasApplyWithSymbols); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Run lints the protocol buffer.
+//
+// All rules share one parsed proto. genProto is called again only when
+// the previous rule has rewritten or renamed the file.
 func (l *Linter) Run(
 	genProto func(*parser.Proto) (*parser.Proto, error),
 	hasApplies []rule.HasApply,
@@ -24,17 +64,193 @@ func (l *Linter) Run(
 	var p *parser.Proto
 	var err error
 
+	stale := true
 	for _, hasApply := range hasApplies {
-		p, err = genProto(p)
-		if err != nil {
-			return nil, err
+		if stale {
+			p, err = genProto(p)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		f, err := hasApply.Apply(p)
+		filename := p.Meta.Filename
+		f, err := l.apply(hasApply, p)
 		if err != nil {
 			return nil, err
 		}
 		fs = append(fs, f...)
+
+		stale = fixer.TakeDirty(filename) || p.Meta.Filename != filename
 	}
 	return fs, nil
 }
+
+// apply applies the rule to the proto with the symbol table if the rule needs it.
+func (l *Linter) apply(
+	hasApply rule.HasApply,
+	p *parser.Proto,
+) ([]report.Failure, error) {
+	if r, ok := hasApply.(rule.HasApplyWithSymbols); ok {
+		return r.ApplyWithSymbols(p, l.symbolsOf(p))
+	}
+	return hasApply.Apply(p)
+}
+
+// symbolsOf returns the symbol table reflecting the current version of the proto.
+func (l *Linter) symbolsOf(p *parser.Proto) *symbol.Table {
+	if l.symbols == nil {
+		return symbol.NewTable(nil, nil).WithProto(p)
+	}
+	return l.symbols.WithProto(p)
+}
+
+// ParseError represents the error returned through a parsing exception.
+type ParseError struct {
+	Message string
+}
+
+func (p ParseError) Error() string {
+	return p.Message
+}
+
+// RunFile lints the proto file.
+// It returns the file, which a fixing rule may have renamed, along with the failures.
+func (l *Linter) RunFile(
+	f file.ProtoFile,
+	hasApplies []rule.HasApply,
+	verbose bool,
+) (file.ProtoFile, []report.Failure, error) {
+	if l.symbols == nil && NeedsSymbols(hasApplies) {
+		l = l.WithSymbols(internalsymbol.Build([]file.ProtoFile{f}, nil))
+	}
+
+	fs, err := l.Run(func(p *parser.Proto) (*parser.Proto, error) {
+		// Recreate a protoFile if the previous rule changed the filename.
+		if p != nil && p.Meta.Filename != f.DisplayPath() {
+			newFilename := p.Meta.Filename
+			newBase := filepath.Base(newFilename)
+			f = file.NewProtoFile(filepath.Join(filepath.Dir(f.Path()), newBase), newFilename)
+		}
+
+		proto, err := f.Parse(verbose)
+		if err != nil {
+			if verbose {
+				return nil, ParseError{Message: err.Error()}
+			}
+			return nil, ParseError{Message: fmt.Sprintf("%s. Use -v for more details", err)}
+		}
+		return proto, nil
+	}, hasApplies)
+	return f, fs, err
+}
+
+// SuggestFixes attaches the edits which fix the failures of each rule to them.
+//
+// fixingRules must be built in fix mode. Each of them that reported a failure is applied
+// independently to the original content in memory, so the file is never written.
+func (l *Linter) SuggestFixes(
+	f file.ProtoFile,
+	fixingRules []rule.HasApply,
+	failures []report.Failure,
+	verbose bool,
+) ([]report.Failure, error) {
+	original, err := osutil.ReadFile(f.Path())
+	if err != nil {
+		return nil, err
+	}
+	// Restore the content from stdin or an editor after suggesting.
+	if prev, ok := osutil.RemoveOverlay(f.Path()); ok {
+		defer osutil.SetOverlay(f.Path(), prev)
+	} else {
+		defer osutil.RemoveOverlay(f.Path())
+	}
+
+	if l.symbols == nil && NeedsSymbols(fixingRules) {
+		l = l.WithSymbols(internalsymbol.Build([]file.ProtoFile{f}, nil))
+	}
+
+	suggested := append([]report.Failure(nil), failures...)
+	for _, fixingRule := range fixingRules {
+		r, ok := fixingRule.(rule.HasID)
+		if !ok {
+			continue
+		}
+		var indexes []int
+		for i, failure := range failures {
+			if failure.RuleID() == r.ID() {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) == 0 {
+			continue
+		}
+
+		osutil.SetOverlay(f.Path(), original)
+		proto, err := f.Parse(verbose)
+		if err != nil {
+			return nil, ParseError{Message: err.Error()}
+		}
+		_, err = l.apply(fixingRule, proto)
+		if err != nil {
+			return nil, err
+		}
+		// The rename by the rule isn't suggested.
+		_ = fixer.TakeDirty(f.Path())
+		fixed, err := osutil.ReadFile(f.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		edits := make(map[int][]report.SuggestedEdit)
+		for _, e := range diffutil.Edits(original, fixed) {
+			edit := report.SuggestedEdit{
+				Pos:     positionAt(original, f.DisplayPath(), e.Start),
+				End:     positionAt(original, f.DisplayPath(), e.End),
+				NewText: e.NewText,
+			}
+			i := ownerOf(failures, indexes, edit)
+			edits[i] = append(edits[i], edit)
+		}
+		for i, es := range edits {
+			suggested[i] = suggested[i].WithSuggestedEdits(es...)
+		}
+	}
+	return suggested, nil
+}
+
+// ownerOf returns the index of the failure which the edit fixes.
+// It's the first one on the lines of the edit, or the nearest one before the edit otherwise.
+func ownerOf(
+	failures []report.Failure,
+	indexes []int,
+	edit report.SuggestedEdit,
+) int {
+	owner := indexes[0]
+	for _, i := range indexes {
+		pos := failures[i].Pos()
+		if edit.Pos.Line <= pos.Line && pos.Line <= edit.End.Line {
+			return i
+		}
+		if pos.Offset <= edit.Pos.Offset && failures[owner].Pos().Offset <= pos.Offset {
+			owner = i
+		}
+	}
+	return owner
+}
+
+// positionAt converts the byte offset in the content to the position.
+// The column counts characters from 1 like the parser.
+func positionAt(
+	content []byte,
+	filename string,
+	offset int,
+) meta.Position {
+	before := content[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return meta.Position{
+		Filename: filename,
+		Offset:   offset,
+		Line:     bytes.Count(before, []byte("\n")) + 1,
+		Column:   utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
@@ -20,15 +20,50 @@ import (
 //					{"filename": FILENAME, "line": LINE, "column": COL, "message": MESSAGE, "rule": RULE}
 //				],
 //	 }
+//
+// A failure with the suggested fixes also has "suggested_edits", each of which is:
+//
+//	 {
+//			"start_line": LINE, "start_column": COL, "end_line": LINE, "end_column": COL,
+//			"offset": OFFSET, "length": LENGTH, "new_text": TEXT
+//	 }
 type JSONReporter struct{}
 
 type lintJSON struct {
-	Filename string `json:"filename"`
-	Line     int    `json:"line"`
-	Column   int    `json:"column"`
-	Message  string `json:"message"`
-	Rule     string `json:"rule"`
-	Severity string `json:"severity"`
+	Filename string     `json:"filename"`
+	Line     int        `json:"line"`
+	Column   int        `json:"column"`
+	Message  string     `json:"message"`
+	Rule     string     `json:"rule"`
+	Severity string     `json:"severity"`
+	Edits    []editJSON `json:"suggested_edits,omitempty"`
+}
+
+// editJSON represents a suggested edit, which replaces the length bytes from the offset with new_text.
+type editJSON struct {
+	StartLine   int    `json:"start_line"`
+	StartColumn int    `json:"start_column"`
+	EndLine     int    `json:"end_line"`
+	EndColumn   int    `json:"end_column"`
+	Offset      int    `json:"offset"`
+	Length      int    `json:"length"`
+	NewText     string `json:"new_text"`
+}
+
+func toEditsJSON(edits []report.SuggestedEdit) []editJSON {
+	var es []editJSON
+	for _, e := range edits {
+		es = append(es, editJSON{
+			StartLine:   e.Pos.Line,
+			StartColumn: e.Pos.Column,
+			EndLine:     e.End.Line,
+			EndColumn:   e.End.Column,
+			Offset:      e.Pos.Offset,
+			Length:      e.End.Offset - e.Pos.Offset,
+			NewText:     e.NewText,
+		})
+	}
+	return es
 }
 
 type outJSON struct {
@@ -54,6 +89,7 @@ func (r JSONReporter) Report(w io.Writer, fs []report.Failure) error {
 			Message:  failure.Message(),
 			Rule:     failure.RuleID(),
 			Severity: failure.Severity(),
+			Edits:    toEditsJSON(failure.SuggestedEdits()),
 		})
 	}
 
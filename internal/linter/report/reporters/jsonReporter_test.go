@@ -66,6 +66,53 @@ func TestJSONReporter_Report(t *testing.T) {
     }
   ]
 }
+`
+			},
+		},
+		{
+			name: "Prints failures with the suggested edits",
+			inputFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: "example.proto",
+						Offset:   100,
+						Line:     5,
+						Column:   6,
+					},
+					"ENUM_NAMES_UPPER_CAMEL_CASE",
+					string(rule.SeverityError),
+					`Enum name "enumName" must be UpperCamelCase like "EnumName"`,
+				).WithSuggestedEdits(report.SuggestedEdit{
+					Pos:     meta.Position{Filename: "example.proto", Offset: 100, Line: 5, Column: 6},
+					End:     meta.Position{Filename: "example.proto", Offset: 101, Line: 5, Column: 7},
+					NewText: "E",
+				}),
+			},
+			wantOutput: func(basedir string) string {
+				return `{
+  "basedir": "` + basedir + `",
+  "lints": [
+    {
+      "filename": "example.proto",
+      "line": 5,
+      "column": 6,
+      "message": "Enum name \"enumName\" must be UpperCamelCase like \"EnumName\"",
+      "rule": "ENUM_NAMES_UPPER_CAMEL_CASE",
+      "severity": "error",
+      "suggested_edits": [
+        {
+          "start_line": 5,
+          "start_column": 6,
+          "end_line": 5,
+          "end_column": 7,
+          "offset": 100,
+          "length": 1,
+          "new_text": "E"
+        }
+      ]
+    }
+  ]
+}
 `
 			},
 		},
@@ -26,6 +26,9 @@ func (r MCPReporter) Report(w io.Writer, fs []report.Failure) error {
 			"column":   failure.Pos().Column,
 			"severity": failure.Severity(),
 		}
+		if edits := failure.SuggestedEdits(); 0 < len(edits) {
+			failureInfo["suggested_edits"] = toEditsJSON(edits)
+		}
 
 		fileFailures[filePath] = append(fileFailures[filePath], failureInfo)
 	}
@@ -74,6 +74,10 @@ func (r SarifReporter) Report(w io.Writer, fs []report.Failure) error {
 			if lvl, ok := allSeverities[failure.Severity()]; ok {
 				recentResult.Level = getResultLevel(lvl)
 			}
+
+			if edits := failure.SuggestedEdits(); 0 < len(edits) {
+				recentResult.Fixes = []*garif.Fix{newFix(failure.Pos().Filename, edits)}
+			}
 		}
 	}
 
@@ -84,6 +88,29 @@ func (r SarifReporter) Report(w io.Writer, fs []report.Failure) error {
 	return logFile.PrettyWrite(w)
 }
 
+// newFix converts the suggested edits to a fix. The regions refer to the lines and the columns of the original file.
+func newFix(filename string, edits []report.SuggestedEdit) *garif.Fix {
+	var replacements []*garif.Replacement
+	for _, e := range edits {
+		region := garif.NewRegion()
+		region.StartLine = e.Pos.Line
+		region.StartColumn = e.Pos.Column
+		region.EndLine = e.End.Line
+		region.EndColumn = e.End.Column
+
+		replacement := garif.NewReplacement(region)
+		if 0 < len(e.NewText) {
+			replacement.InsertedContent = garif.NewArtifactContent()
+			replacement.InsertedContent.Text = e.NewText
+		}
+		replacements = append(replacements, replacement)
+	}
+
+	location := garif.NewArtifactLocation()
+	location.Uri = filename
+	return garif.NewFix(garif.NewArtifactChange(location, replacements...))
+}
+
 func getResultLevel(severity rule.Severity) garif.ResultLevel {
 	switch severity {
 	case rule.SeverityError:
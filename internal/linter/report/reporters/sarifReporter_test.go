@@ -112,6 +112,99 @@ func TestSarifReporter_Report(t *testing.T) {
     }
   ],
   "version": "2.1.0"
+}`,
+		},
+		{
+			name: "Prints failures with the suggested edits as fixes",
+			inputFailures: []report.Failure{
+				report.Failuref(
+					meta.Position{
+						Filename: "example.proto",
+						Offset:   100,
+						Line:     5,
+						Column:   6,
+					},
+					"ENUM_NAMES_UPPER_CAMEL_CASE",
+					string(rule.SeverityError),
+					`Enum name "enumName" must be UpperCamelCase like "EnumName"`,
+				).WithSuggestedEdits(report.SuggestedEdit{
+					Pos:     meta.Position{Filename: "example.proto", Offset: 100, Line: 5, Column: 6},
+					End:     meta.Position{Filename: "example.proto", Offset: 101, Line: 5, Column: 7},
+					NewText: "E",
+				}),
+			},
+			wantOutput: `{
+  "runs": [
+    {
+      "artifacts": [
+        {
+          "location": {
+            "uri": "example.proto"
+          }
+        }
+      ],
+      "results": [
+        {
+          "fixes": [
+            {
+              "artifactChanges": [
+                {
+                  "artifactLocation": {
+                    "uri": "example.proto"
+                  },
+                  "replacements": [
+                    {
+                      "deletedRegion": {
+                        "endColumn": 7,
+                        "endLine": 5,
+                        "startColumn": 6,
+                        "startLine": 5
+                      },
+                      "insertedContent": {
+                        "text": "E"
+                      }
+                    }
+                  ]
+                }
+              ]
+            }
+          ],
+          "kind": "fail",
+          "level": "error",
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "example.proto"
+                },
+                "region": {
+                  "startColumn": 6,
+                  "startLine": 5
+                }
+              }
+            }
+          ],
+          "message": {
+            "text": "Enum name \"enumName\" must be UpperCamelCase like \"EnumName\""
+          },
+          "ruleId": "ENUM_NAMES_UPPER_CAMEL_CASE"
+        }
+      ],
+      "tool": {
+        "driver": {
+          "informationUri": "https://github.com/yoheimuta/protolint",
+          "name": "protolint",
+          "rules": [
+            {
+              "helpUri": "https://github.com/yoheimuta/protolint",
+              "id": "ENUM_NAMES_UPPER_CAMEL_CASE"
+            }
+          ]
+        }
+      }
+    }
+  ],
+  "version": "2.1.0"
 }`,
 		},
 	}
@@ -0,0 +1,8 @@
+package rule
+
+// HasCacheKey is implemented by the rules whose results depend on more than the file and the config,
+// like the previous version of the file at a git ref.
+type HasCacheKey interface {
+	// CacheKey returns the key which changes when the results may change.
+	CacheKey() (string, error)
+}
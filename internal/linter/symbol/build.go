@@ -0,0 +1,192 @@
+// Package symbol builds the symbol tables of the linter/symbol package from the files on the disk.
+package symbol
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+type builder struct {
+	includePaths []string
+	// searchAncestors makes the imports found in the ancestor directories of the importing file
+	// when no include path is given.
+	searchAncestors bool
+
+	files        []*symbol.File
+	displayPaths map[string]string
+	visited      map[string]bool
+	// guessed holds the paths of the files given to Build, whose import paths may be guessed.
+	guessed map[string]bool
+}
+
+// Build builds the table of the files and the ones imported by them recursively.
+//
+// The imports are searched in the include paths in order, like the -I option of protoc.
+// Without any include path, the working directory and then the ancestor directories of
+// the importing file are searched, and the import paths of the given files are guessed
+// from the import statements. The well-known types shipped with protoc are used
+// when they are not found. The files failing to be parsed are left out of the table
+// because the linter reports the errors of the target files.
+func Build(
+	fs []file.ProtoFile,
+	includePaths []string,
+) *symbol.Table {
+	b := &builder{
+		displayPaths: make(map[string]string),
+		visited:      make(map[string]bool),
+		guessed:      make(map[string]bool),
+	}
+	if len(includePaths) == 0 {
+		includePaths = []string{"."}
+		b.searchAncestors = true
+	}
+	for _, p := range includePaths {
+		if abs, err := filepath.Abs(p); err == nil {
+			b.includePaths = append(b.includePaths, abs)
+		}
+	}
+
+	for _, f := range fs {
+		b.guessed[f.Path()] = true
+		b.add(f, b.importPathOf(f.Path()))
+	}
+	if b.searchAncestors {
+		b.guessImportPaths()
+	}
+	return symbol.NewTable(b.files, b.displayPaths)
+}
+
+// guessImportPaths replaces the import paths of the files given to Build with the ones in the import
+// statements of the other files, or the paths relative to the deepest root directory which any import
+// statement is resolved under.
+func (b *builder) guessImportPaths() {
+	imported := make(map[string]string)
+	var roots []string
+	for _, f := range b.files {
+		for _, imp := range f.Imports {
+			if imp.File == "" || imp.File == imp.Path {
+				continue
+			}
+			imported[imp.File] = imp.Path
+			if root, ok := strings.CutSuffix(imp.File, filepath.FromSlash(imp.Path)); ok {
+				roots = append(roots, root)
+			}
+		}
+	}
+	for _, f := range b.files {
+		if !b.guessed[f.Path] {
+			continue
+		}
+		if importPath, ok := imported[f.Path]; ok {
+			f.ImportPath = importPath
+			continue
+		}
+		var deepest string
+		for _, root := range roots {
+			if strings.HasPrefix(f.Path, root) && len(deepest) < len(root) {
+				deepest = root
+			}
+		}
+		if deepest != "" {
+			f.ImportPath = filepath.ToSlash(strings.TrimPrefix(f.Path, deepest))
+		}
+	}
+}
+
+func (b *builder) add(f file.ProtoFile, importPath string) {
+	if b.visited[f.Path()] {
+		return
+	}
+	b.visited[f.Path()] = true
+
+	proto, err := f.Parse(false)
+	if err != nil {
+		return
+	}
+	b.displayPaths[f.DisplayPath()] = f.Path()
+
+	var imported []string
+	symbolFile := symbol.NewFile(proto, f.Path(), importPath, func(importPath string) string {
+		path := b.resolve(f.Path(), importPath)
+		if path != "" {
+			imported = append(imported, path)
+		}
+		return path
+	})
+	b.files = append(b.files, symbolFile)
+
+	for _, path := range imported {
+		if b.visited[path] {
+			continue
+		}
+		if wk, ok := symbol.WellKnownFile(path); ok {
+			b.visited[path] = true
+			b.files = append(b.files, wk)
+			continue
+		}
+		importedFile, err := file.NewProtoFileFromPath(path)
+		if err != nil {
+			continue
+		}
+		b.add(importedFile, importPathOfImport(symbolFile, path))
+	}
+}
+
+func importPathOfImport(f *symbol.File, path string) string {
+	for _, imp := range f.Imports {
+		if imp.File == path {
+			return imp.Path
+		}
+	}
+	return ""
+}
+
+// importPathOf returns the path relative to the first include path which has the file.
+func (b *builder) importPathOf(path string) string {
+	for _, inc := range b.includePaths {
+		rel, err := filepath.Rel(inc, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Base(path))
+}
+
+// resolve returns the path of the imported file, or empty when it isn't found.
+func (b *builder) resolve(from string, importPath string) string {
+	rel := filepath.FromSlash(importPath)
+	for _, inc := range b.includePaths {
+		if p := filepath.Join(inc, rel); exists(p) {
+			return p
+		}
+	}
+	if b.searchAncestors {
+		for dir := filepath.Dir(from); ; {
+			if p := filepath.Join(dir, rel); exists(p) {
+				return p
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	if _, ok := symbol.WellKnownFile(importPath); ok {
+		return importPath
+	}
+	return ""
+}
+
+func exists(path string) bool {
+	if osutil.HasOverlay(path) {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
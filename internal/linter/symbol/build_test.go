@@ -0,0 +1,151 @@
+package symbol_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/osutil"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func protoFile(t *testing.T, path string) file.ProtoFile {
+	f, err := file.NewProtoFileFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeFiles(t, dir, map[string]string{
+		"proto/foo/v1/foo.proto": `syntax = "proto3";
+package foo.v1;
+import "bar/v1/bar.proto";
+import "google/protobuf/timestamp.proto";
+import "missing.proto";
+message Foo {
+  bar.v1.Bar bar = 1;
+  google.protobuf.Timestamp time = 2;
+}
+`,
+		"proto/bar/v1/bar.proto": `syntax = "proto3";
+package bar.v1;
+import public "bar/v1/baz.proto";
+message Bar {}
+`,
+		"proto/bar/v1/baz.proto": `syntax = "proto3";
+package bar.v1;
+message Baz {}
+`,
+	})
+
+	tests := []struct {
+		name              string
+		inputIncludePaths []string
+		wantFiles         []string
+		wantImportPath    string
+	}{
+		{
+			name:              "resolve the imports through the include path",
+			inputIncludePaths: []string{"proto"},
+			wantFiles: []string{
+				filepath.Join(dir, "proto", "bar", "v1", "bar.proto"),
+				filepath.Join(dir, "proto", "bar", "v1", "baz.proto"),
+				filepath.Join(dir, "proto", "foo", "v1", "foo.proto"),
+				"google/protobuf/timestamp.proto",
+			},
+			wantImportPath: "foo/v1/foo.proto",
+		},
+		{
+			name: "resolve the imports in the ancestor directories without include paths",
+			wantFiles: []string{
+				filepath.Join(dir, "proto", "bar", "v1", "bar.proto"),
+				filepath.Join(dir, "proto", "bar", "v1", "baz.proto"),
+				filepath.Join(dir, "proto", "foo", "v1", "foo.proto"),
+				"google/protobuf/timestamp.proto",
+			},
+			wantImportPath: "foo/v1/foo.proto",
+		},
+		{
+			name:              "leave the imports out of the include paths unresolved",
+			inputIncludePaths: []string{"proto/foo"},
+			wantFiles: []string{
+				filepath.Join(dir, "proto", "foo", "v1", "foo.proto"),
+				"google/protobuf/timestamp.proto",
+			},
+			wantImportPath: "v1/foo.proto",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			table := symbol.Build(
+				[]file.ProtoFile{protoFile(t, filepath.Join("proto", "foo", "v1", "foo.proto"))},
+				test.inputIncludePaths,
+			)
+
+			var gotFiles []string
+			for _, f := range table.Files() {
+				gotFiles = append(gotFiles, f.Path)
+			}
+			sort.Strings(gotFiles)
+			if !reflect.DeepEqual(gotFiles, test.wantFiles) {
+				t.Errorf("got %v, but want %v", gotFiles, test.wantFiles)
+			}
+
+			foo, ok := table.File(filepath.Join(dir, "proto", "foo", "v1", "foo.proto"))
+			if !ok {
+				t.Fatal("got no file of foo.proto")
+			}
+			if foo.ImportPath != test.wantImportPath {
+				t.Errorf("got %s, but want %s", foo.ImportPath, test.wantImportPath)
+			}
+			if foo.Imports[2].File != "" {
+				t.Errorf("got %s, but want the missing import unresolved", foo.Imports[2].File)
+			}
+		})
+	}
+}
+
+func TestBuild_Overlay(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeFiles(t, dir, map[string]string{
+		"a.proto": `syntax = "proto3";
+message A {}
+`,
+	})
+
+	f := protoFile(t, "a.proto")
+	osutil.SetOverlay(f.Path(), []byte(`syntax = "proto3";
+message B {}
+`))
+	defer osutil.RemoveOverlay(f.Path())
+
+	table := symbol.Build([]file.ProtoFile{f}, nil)
+	if got := table.Lookup("A"); len(got) != 0 {
+		t.Errorf("got %v, but want no symbol on the disk", got)
+	}
+	if got := table.Lookup("B"); len(got) != 1 {
+		t.Errorf("got %v, but want the symbol in memory", got)
+	}
+}
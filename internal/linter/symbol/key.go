@@ -0,0 +1,108 @@
+package symbol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"path/filepath"
+	"sort"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// FileKey returns the hash of what the rules read about the file from the other files in the table:
+// the imports of the file, the exported symbols of the files it imports, the definitions of its references,
+// the imports of the files reachable from it, and the packages and the options of the files sharing
+// its directory or package. It stays the same while the other files change in the other ways,
+// like the positions and the field types, so that their changes don't invalidate the results of the file.
+// It returns empty when the file isn't in the table.
+func FileKey(
+	t *symbol.Table,
+	path string,
+) string {
+	f, ok := t.File(path)
+	if !ok {
+		return ""
+	}
+
+	h := sha256.New()
+	writeFile(h, "file", f)
+	for _, imp := range f.Imports {
+		_, _ = fmt.Fprintf(h, "import %s %t %t %s\n", imp.Path, imp.IsPublic, imp.IsWeak, imp.File)
+	}
+
+	visible := t.VisibleFiles(f)
+	var visiblePaths []string
+	for p := range visible {
+		visiblePaths = append(visiblePaths, p)
+	}
+	sort.Strings(visiblePaths)
+	for _, p := range visiblePaths {
+		v, ok := t.File(p)
+		if !ok || v == f {
+			continue
+		}
+		writeFile(h, "visible", v)
+		for _, s := range v.Symbols {
+			_, _ = fmt.Fprintf(h, "symbol %s %s\n", s.Name, s.Kind)
+		}
+	}
+
+	// The references may be defined in the files which aren't imported yet.
+	for _, r := range f.References {
+		s, ok := t.Resolve(r.Scope, r.Name)
+		if !ok {
+			_, _ = fmt.Fprintf(h, "reference %s %s\n", r.Scope, r.Name)
+			continue
+		}
+		for _, d := range t.Lookup(s.Name) {
+			var importPath string
+			if definer, ok := t.File(d.File); ok {
+				importPath = definer.ImportPath
+			}
+			_, _ = fmt.Fprintf(h, "reference %s %s %s %s %s %t\n", r.Scope, r.Name, d.Name, d.File, importPath, visible[d.File])
+		}
+	}
+
+	// The imports of the reachable files make the import cycles.
+	reached := map[string]bool{f.Path: true}
+	for queue := []*symbol.File{f}; 0 < len(queue); queue = queue[1:] {
+		for _, imp := range queue[0].Imports {
+			if imp.File == "" {
+				continue
+			}
+			_, _ = fmt.Fprintf(h, "edge %s %s\n", queue[0].Path, imp.File)
+			if reached[imp.File] {
+				continue
+			}
+			reached[imp.File] = true
+			if next, ok := t.File(imp.File); ok {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	dir := filepath.Dir(f.Path)
+	for _, other := range t.Files() {
+		if other == f || other.IsWellKnown {
+			continue
+		}
+		if filepath.Dir(other.Path) == dir || (f.Package != "" && other.Package == f.Package) {
+			writeFile(h, "neighbor", other)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeFile(h hash.Hash, label string, f *symbol.File) {
+	_, _ = fmt.Fprintf(h, "%s %s %s %s\n", label, f.Path, f.ImportPath, f.Package)
+	var names []string
+	for name := range f.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(h, "option %s %s\n", name, f.Options[name])
+	}
+}
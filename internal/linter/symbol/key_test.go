@@ -0,0 +1,133 @@
+package symbol_test
+
+import (
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/util_test"
+)
+
+var keyTestFiles = map[string]string{
+	"a/a.proto": `syntax = "proto3";
+package a;
+import "b/b.proto";
+message A {
+  b.B b = 1;
+  c.C c = 2;
+}
+`,
+	"b/b.proto": `syntax = "proto3";
+package b;
+import "d/d.proto";
+message B {}
+`,
+	"c/c.proto": `syntax = "proto3";
+package c;
+`,
+	"d/d.proto": `syntax = "proto3";
+package d;
+`,
+	"e/e.proto": `syntax = "proto3";
+package e;
+message E {}
+`,
+}
+
+func TestFileKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		inputPath   string
+		inputChange string
+		wantChanged bool
+	}{
+		{
+			name:      "the positions of the imported file",
+			inputPath: "b/b.proto",
+			inputChange: `syntax = "proto3";
+
+package b;
+
+import "d/d.proto";
+
+message B {}
+`,
+		},
+		{
+			name:      "the fields of the imported file",
+			inputPath: "b/b.proto",
+			inputChange: `syntax = "proto3";
+package b;
+import "d/d.proto";
+message B {
+  string name = 1;
+}
+`,
+		},
+		{
+			name:      "the file which isn't read",
+			inputPath: "e/e.proto",
+			inputChange: `syntax = "proto3";
+package e;
+message E {}
+message F {}
+`,
+		},
+		{
+			name:      "the symbols of the imported file",
+			inputPath: "b/b.proto",
+			inputChange: `syntax = "proto3";
+package b;
+import "d/d.proto";
+message B {}
+message Other {}
+`,
+			wantChanged: true,
+		},
+		{
+			name:      "the definition of the reference in the file not imported",
+			inputPath: "c/c.proto",
+			inputChange: `syntax = "proto3";
+package c;
+message C {}
+`,
+			wantChanged: true,
+		},
+		{
+			name:      "the imports of the reachable file",
+			inputPath: "d/d.proto",
+			inputChange: `syntax = "proto3";
+package d;
+import "a/a.proto";
+`,
+			wantChanged: true,
+		},
+		{
+			name:      "the package of the file in the same directory",
+			inputPath: "a/other.proto",
+			inputChange: `syntax = "proto3";
+package other;
+`,
+			wantChanged: true,
+		},
+	}
+
+	want := symbol.FileKey(util_test.NewSymbolTable(t, keyTestFiles), "a/a.proto")
+	if want == "" {
+		t.Fatal("got empty, but want the key")
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			files := make(map[string]string)
+			for name, content := range keyTestFiles {
+				files[name] = content
+			}
+			files[test.inputPath] = test.inputChange
+
+			got := symbol.FileKey(util_test.NewSymbolTable(t, files), "a/a.proto")
+			if (got != want) != test.wantChanged {
+				t.Errorf("got changed %t, but want %t", got != want, test.wantChanged)
+			}
+		})
+	}
+}
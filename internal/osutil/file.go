@@ -19,7 +19,7 @@ func ReadAllLines(
 	fileName string,
 	newlineChar string,
 ) ([]string, error) {
-	data, err := os.ReadFile(fileName)
+	data, err := ReadFile(fileName)
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +40,16 @@ func WriteLinesToExistingFile(
 }
 
 // WriteExistingFile writes the byte array to an existing file.
+// The file with in-memory content gets the byte array only in memory.
 func WriteExistingFile(
 	fileName string,
 	data []byte,
 ) error {
+	if HasOverlay(fileName) {
+		SetOverlay(fileName, data)
+		return nil
+	}
+
 	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_TRUNC, 0)
 	if err != nil {
 		return err
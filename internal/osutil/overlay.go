@@ -0,0 +1,122 @@
+package osutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// overlay holds in-memory file contents which shadow the files on the disk.
+// It lets the linter and the fixer work on unsaved buffers or stdin.
+var overlay sync.Map
+
+func overlayKey(name string) string {
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return filepath.Clean(name)
+}
+
+// SetOverlay makes the file functions in this package use the content instead of the named file.
+func SetOverlay(
+	name string,
+	content []byte,
+) {
+	overlay.Store(overlayKey(name), bytes.Clone(content))
+}
+
+// RemoveOverlay drops the in-memory content of the named file and returns the last content.
+func RemoveOverlay(
+	name string,
+) ([]byte, bool) {
+	v, ok := overlay.LoadAndDelete(overlayKey(name))
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+
+// fileLock is the lock of a file with the number of the callers holding or waiting for it.
+type fileLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var (
+	fileLocksMu sync.Mutex
+	fileLocks   = make(map[string]*fileLock)
+)
+
+// LockFile blocks until no other caller holds the lock of the named file, and returns the function to release it.
+// The callers which use the in-memory content, or expect none, hold the lock not to see the content of each other.
+func LockFile(
+	name string,
+) func() {
+	key := overlayKey(name)
+
+	fileLocksMu.Lock()
+	l, ok := fileLocks[key]
+	if !ok {
+		l = &fileLock{}
+		fileLocks[key] = l
+	}
+	l.refs++
+	fileLocksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		fileLocksMu.Lock()
+		defer fileLocksMu.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(fileLocks, key)
+		}
+	}
+}
+
+// UseOverlay holds the lock of the named file and gives it the in-memory content.
+// The returned function drops the content, releases the lock, and returns the last content.
+func UseOverlay(
+	name string,
+	content []byte,
+) func() []byte {
+	unlock := LockFile(name)
+	SetOverlay(name, content)
+	return func() []byte {
+		defer unlock()
+		last, _ := RemoveOverlay(name)
+		return last
+	}
+}
+
+// HasOverlay reports whether the named file has in-memory content.
+func HasOverlay(
+	name string,
+) bool {
+	_, ok := overlay.Load(overlayKey(name))
+	return ok
+}
+
+// ReadFile reads the named file, preferring its in-memory content.
+func ReadFile(
+	name string,
+) ([]byte, error) {
+	if v, ok := overlay.Load(overlayKey(name)); ok {
+		return bytes.Clone(v.([]byte)), nil
+	}
+	return os.ReadFile(name)
+}
+
+// Open opens the named file for reading, preferring its in-memory content.
+func Open(
+	name string,
+) (io.ReadCloser, error) {
+	if v, ok := overlay.Load(overlayKey(name)); ok {
+		return io.NopCloser(bytes.NewReader(v.([]byte))), nil
+	}
+	return os.Open(name)
+}
@@ -22,3 +22,13 @@ func TestDataPath(elem ...string) string {
 	ps = append(ps, elem...)
 	return filepath.Join(ps...)
 }
+
+// ExamplePath is the directory path for the examples.
+func ExamplePath(elem ...string) string {
+	ps := []string{
+		projectRootPath(),
+		"_example",
+	}
+	ps = append(ps, elem...)
+	return filepath.Join(ps...)
+}
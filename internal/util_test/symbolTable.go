@@ -0,0 +1,28 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// NewSymbolTable creates a table of the files keyed by their import paths, which are also their paths.
+func NewSymbolTable(t *testing.T, files map[string]string) *symbol.Table {
+	var fs []*symbol.File
+	for name, content := range files {
+		proto, err := protoparser.Parse(strings.NewReader(content), protoparser.WithFilename(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fs = append(fs, symbol.NewFile(proto, name, name, func(importPath string) string {
+			if _, ok := files[importPath]; ok {
+				return importPath
+			}
+			return ""
+		}))
+	}
+	return symbol.NewTable(fs, nil)
+}
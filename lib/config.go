@@ -0,0 +1,254 @@
+package lib
+
+import (
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// Config is the typed config of a Linter. It corresponds to the lint section of .protolint.yaml,
+// and is validated in the same way as the file when the Linter is created.
+// The zero value of each field leaves the default of the rule.
+type Config struct {
+	// Extends are the config files or the built-in presets like "builtin:google" which the config extends.
+	// The relative paths are resolved against the working directory.
+	Extends []string `yaml:"extends,omitempty"`
+	// Ignores are the files which each rule isn't applied to.
+	Ignores []Ignore `yaml:"ignores,omitempty"`
+	// Rules selects the rules to apply.
+	Rules Rules `yaml:"rules,omitempty"`
+	// RulesOption configures the built-in rules.
+	RulesOption RulesOption `yaml:"rules_option,omitempty"`
+	// CustomRules are the rules declared without writing Go.
+	CustomRules []CustomRule `yaml:"custom_rules,omitempty"`
+}
+
+// Ignore represents the files which the rule isn't applied to.
+type Ignore struct {
+	ID    string   `yaml:"id"`
+	Files []string `yaml:"files"`
+}
+
+// Rules selects the rules to apply.
+type Rules struct {
+	// NoDefault disables the rules enabled by default.
+	NoDefault bool `yaml:"no_default,omitempty"`
+	// AllDefault enables all rules including the ones disabled by default.
+	AllDefault bool `yaml:"all_default,omitempty"`
+	// Add enables the rules.
+	Add []string `yaml:"add,omitempty"`
+	// Remove disables the rules.
+	Remove []string `yaml:"remove,omitempty"`
+}
+
+// CustomRule represents a rule which checks the names of the target elements or evaluates an expression over them.
+// See custom_rules in the README for the meaning of each field.
+type CustomRule struct {
+	ID           string        `yaml:"id"`
+	Purpose      string        `yaml:"purpose,omitempty"`
+	Target       string        `yaml:"target,omitempty"`
+	Pattern      string        `yaml:"pattern,omitempty"`
+	NotPattern   string        `yaml:"not_pattern,omitempty"`
+	AllowWords   []string      `yaml:"allow_words,omitempty"`
+	DenyWords    []string      `yaml:"deny_words,omitempty"`
+	WordPosition string        `yaml:"word_position,omitempty"`
+	When         string        `yaml:"when,omitempty"`
+	Expr         string        `yaml:"expr,omitempty"`
+	Message      string        `yaml:"message,omitempty"`
+	Severity     rule.Severity `yaml:"severity,omitempty"`
+}
+
+// SeverityOption is the option of the rules which only have the severity.
+type SeverityOption struct {
+	Severity rule.Severity `yaml:"severity,omitempty"`
+}
+
+// IndentStyle is the style of the indentation.
+type IndentStyle string
+
+// IndentStyle constants.
+const (
+	IndentTab     IndentStyle = "tab"
+	IndentSpaces2 IndentStyle = "2"
+	IndentSpaces4 IndentStyle = "4"
+)
+
+// Quote is the quote of the strings.
+type Quote string
+
+// Quote constants.
+const (
+	QuoteDouble Quote = "double"
+	QuoteSingle Quote = "single"
+)
+
+// RPCNamesConvention is the case of the RPC names.
+type RPCNamesConvention string
+
+// RPCNamesConvention constants.
+const (
+	RPCNamesLowerCamelCase RPCNamesConvention = "lower_camel_case"
+	RPCNamesUpperSnakeCase RPCNamesConvention = "upper_snake_case"
+	RPCNamesLowerSnakeCase RPCNamesConvention = "lower_snake_case"
+)
+
+// FileNamesLowerSnakeCaseOption is the option of FILE_NAMES_LOWER_SNAKE_CASE.
+type FileNamesLowerSnakeCaseOption struct {
+	SeverityOption `yaml:",inline"`
+	Excludes       []string `yaml:"excludes,omitempty"`
+}
+
+// QuoteConsistentOption is the option of QUOTE_CONSISTENT.
+type QuoteConsistentOption struct {
+	SeverityOption `yaml:",inline"`
+	Quote          Quote `yaml:"quote,omitempty"`
+}
+
+// MaxLineLengthOption is the option of MAX_LINE_LENGTH.
+type MaxLineLengthOption struct {
+	SeverityOption `yaml:",inline"`
+	MaxChars       int `yaml:"max_chars,omitempty"`
+	TabChars       int `yaml:"tab_chars,omitempty"`
+}
+
+// IndentOption is the option of INDENT.
+type IndentOption struct {
+	SeverityOption   `yaml:",inline"`
+	Style            IndentStyle `yaml:"style,omitempty"`
+	NotInsertNewline bool        `yaml:"not_insert_newline,omitempty"`
+}
+
+// EnumFieldNamesZeroValueEndWithOption is the option of ENUM_FIELD_NAMES_ZERO_VALUE_END_WITH.
+type EnumFieldNamesZeroValueEndWithOption struct {
+	SeverityOption `yaml:",inline"`
+	Suffix         string `yaml:"suffix,omitempty"`
+}
+
+// ServiceNamesEndWithOption is the option of SERVICE_NAMES_END_WITH.
+type ServiceNamesEndWithOption struct {
+	SeverityOption `yaml:",inline"`
+	Text           string `yaml:"text,omitempty"`
+}
+
+// ExcludePrepositionsOption is the option of FIELD_NAMES_EXCLUDE_PREPOSITIONS and MESSAGE_NAMES_EXCLUDE_PREPOSITIONS.
+type ExcludePrepositionsOption struct {
+	SeverityOption `yaml:",inline"`
+	Prepositions   []string `yaml:"prepositions,omitempty"`
+	Excludes       []string `yaml:"excludes,omitempty"`
+}
+
+// RPCNamesCaseOption is the option of RPC_NAMES_CASE.
+type RPCNamesCaseOption struct {
+	SeverityOption `yaml:",inline"`
+	Convention     RPCNamesConvention `yaml:"convention,omitempty"`
+}
+
+// HaveCommentOption is the option of the rules which require the comments, like MESSAGES_HAVE_COMMENT.
+type HaveCommentOption struct {
+	SeverityOption          `yaml:",inline"`
+	ShouldFollowGolangStyle bool `yaml:"should_follow_golang_style,omitempty"`
+}
+
+// SyntaxConsistentOption is the option of SYNTAX_CONSISTENT.
+type SyntaxConsistentOption struct {
+	SeverityOption `yaml:",inline"`
+	Version        string `yaml:"version,omitempty"`
+}
+
+// RepeatedFieldNamesPluralizedOption is the option of REPEATED_FIELD_NAMES_PLURALIZED.
+type RepeatedFieldNamesPluralizedOption struct {
+	SeverityOption   `yaml:",inline"`
+	PluralRules      map[string]string `yaml:"plural_rules,omitempty"`
+	SingularRules    map[string]string `yaml:"singular_rules,omitempty"`
+	UncountableRules []string          `yaml:"uncountable_rules,omitempty"`
+	IrregularRules   map[string]string `yaml:"irregular_rules,omitempty"`
+}
+
+// PackageDirectoryMatchOption is the option of PACKAGE_DIRECTORY_MATCH.
+type PackageDirectoryMatchOption struct {
+	SeverityOption `yaml:",inline"`
+	Root           string `yaml:"root,omitempty"`
+}
+
+// PackageSameFileOptionsOption is the option of PACKAGE_SAME_FILE_OPTIONS.
+type PackageSameFileOptionsOption struct {
+	SeverityOption `yaml:",inline"`
+	Options        []string `yaml:"options,omitempty"`
+}
+
+// PackageVersionSuffixOption is the option of PACKAGE_VERSION_SUFFIX.
+type PackageVersionSuffixOption struct {
+	SeverityOption `yaml:",inline"`
+	Pattern        string `yaml:"pattern,omitempty"`
+}
+
+// FieldNumbersNoGapsOption is the option of FIELD_NUMBERS_NO_GAPS.
+type FieldNumbersNoGapsOption struct {
+	SeverityOption `yaml:",inline"`
+	MaxGap         int `yaml:"max_gap,omitempty"`
+}
+
+// FieldsReservedOnDeletionOption is the option of FIELDS_RESERVED_ON_DELETION.
+type FieldsReservedOnDeletionOption struct {
+	SeverityOption `yaml:",inline"`
+	GitRef         string `yaml:"git_ref,omitempty"`
+	SnapshotDir    string `yaml:"snapshot_dir,omitempty"`
+}
+
+// RulesOption configures the built-in rules. Each field is named after the rule.
+type RulesOption struct {
+	FileNamesLowerSnakeCase         FileNamesLowerSnakeCaseOption        `yaml:"file_names_lower_snake_case,omitempty"`
+	QuoteConsistent                 QuoteConsistentOption                `yaml:"quote_consistent,omitempty"`
+	ImportsSorted                   SeverityOption                       `yaml:"imports_sorted,omitempty"`
+	MaxLineLength                   MaxLineLengthOption                  `yaml:"max_line_length,omitempty"`
+	Indent                          IndentOption                         `yaml:"indent,omitempty"`
+	EnumFieldNamesZeroValueEndWith  EnumFieldNamesZeroValueEndWithOption `yaml:"enum_field_names_zero_value_end_with,omitempty"`
+	ServiceNamesEndWith             ServiceNamesEndWithOption            `yaml:"service_names_end_with,omitempty"`
+	FieldNamesExcludePrepositions   ExcludePrepositionsOption            `yaml:"field_names_exclude_prepositions,omitempty"`
+	MessageNamesExcludePrepositions ExcludePrepositionsOption            `yaml:"message_names_exclude_prepositions,omitempty"`
+	RPCNamesCase                    RPCNamesCaseOption                   `yaml:"rpc_names_case,omitempty"`
+	MessagesHaveComment             HaveCommentOption                    `yaml:"messages_have_comment,omitempty"`
+	ServicesHaveComment             HaveCommentOption                    `yaml:"services_have_comment,omitempty"`
+	RPCsHaveComment                 HaveCommentOption                    `yaml:"rpcs_have_comment,omitempty"`
+	FieldsHaveComment               HaveCommentOption                    `yaml:"fields_have_comment,omitempty"`
+	EnumsHaveComment                HaveCommentOption                    `yaml:"enums_have_comment,omitempty"`
+	EnumFieldsHaveComment           HaveCommentOption                    `yaml:"enum_fields_have_comment,omitempty"`
+	SyntaxConsistent                SyntaxConsistentOption               `yaml:"syntax_consistent,omitempty"`
+	RepeatedFieldNamesPluralized    RepeatedFieldNamesPluralizedOption   `yaml:"repeated_field_names_pluralized,omitempty"`
+	EnumFieldNamesPrefix            SeverityOption                       `yaml:"enum_field_names_prefix,omitempty"`
+	EnumFieldNamesUpperSnakeCase    SeverityOption                       `yaml:"enum_field_names_upper_snake_case,omitempty"`
+	EnumNamesUpperCamelCase         SeverityOption                       `yaml:"enum_names_upper_camel_case,omitempty"`
+	FieldNamesLowerSnakeCase        SeverityOption                       `yaml:"field_names_lower_snake_case,omitempty"`
+	FileHasComment                  SeverityOption                       `yaml:"file_has_comment,omitempty"`
+	MessageNamesUpperCamelCase      SeverityOption                       `yaml:"message_names_upper_camel_case,omitempty"`
+	Order                           SeverityOption                       `yaml:"order,omitempty"`
+	PackageNameLowerCase            SeverityOption                       `yaml:"package_name_lower_case,omitempty"`
+	Proto3FieldsAvoidRequired       SeverityOption                       `yaml:"proto3_fields_avoid_required,omitempty"`
+	Proto3GroupsAvoid               SeverityOption                       `yaml:"proto3_groups_avoid,omitempty"`
+	RPCNamesUpperCamelCase          SeverityOption                       `yaml:"rpc_names_upper_camel_case,omitempty"`
+	ServiceNamesUpperCamelCase      SeverityOption                       `yaml:"service_names_upper_caml_case,omitempty"`
+	FieldNumbersOrderAscending      SeverityOption                       `yaml:"field_numbers_order_ascending,omitempty"`
+	ImportsUnused                   SeverityOption                       `yaml:"imports_unused,omitempty"`
+	ImportsMissing                  SeverityOption                       `yaml:"imports_missing,omitempty"`
+	ImportNoCycle                   SeverityOption                       `yaml:"import_no_cycle,omitempty"`
+	PackageDirectoryMatch           PackageDirectoryMatchOption          `yaml:"package_directory_match,omitempty"`
+	PackageSameDirectory            SeverityOption                       `yaml:"package_same_directory,omitempty"`
+	PackageSameFileOptions          PackageSameFileOptionsOption         `yaml:"package_same_file_options,omitempty"`
+	PackageVersionSuffix            PackageVersionSuffixOption           `yaml:"package_version_suffix,omitempty"`
+	StablePackageNoUnstableImport   SeverityOption                       `yaml:"stable_package_no_unstable_import,omitempty"`
+	FieldNumbersValid               SeverityOption                       `yaml:"field_numbers_valid,omitempty"`
+	FieldNumbersNotReserved         SeverityOption                       `yaml:"field_numbers_not_reserved,omitempty"`
+	FieldNumbersUnique              SeverityOption                       `yaml:"field_numbers_unique,omitempty"`
+	FieldNumbersNoGaps              FieldNumbersNoGapsOption             `yaml:"field_numbers_no_gaps,omitempty"`
+	FieldsReservedOnDeletion        FieldsReservedOnDeletionOption       `yaml:"fields_reserved_on_deletion,omitempty"`
+}
+
+// toExternalConfig converts the config through the YAML format, so that it's validated and resolved like the file.
+func (c Config) toExternalConfig() (*config.ExternalConfig, error) {
+	data, err := yaml.Marshal(map[string]Config{"lint": c})
+	if err != nil {
+		return nil, err
+	}
+	return config.ParseYAMLConfig(data)
+}
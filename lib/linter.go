@@ -0,0 +1,257 @@
+package lib
+
+import (
+	"bytes"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalrule "github.com/yoheimuta/protolint/internal/linter/rule"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// Option configures a Linter.
+type Option func(*Linter)
+
+// WithConfig makes the Linter use the config instead of searching for a config file.
+func WithConfig(c Config) Option {
+	return func(l *Linter) {
+		l.typedConfig = &c
+		l.configYAML = nil
+	}
+}
+
+// WithConfigYAML makes the Linter use the config instead of searching for a config file.
+// The data is the content of protolint.yaml. The relative paths in it are resolved against the working directory.
+func WithConfigYAML(data []byte) Option {
+	return func(l *Linter) {
+		l.configYAML = data
+		l.typedConfig = nil
+	}
+}
+
+// WithConfigPath makes the Linter load the config file at the path.
+// The supported files are the same as the -config_path flag.
+func WithConfigPath(path string) Option {
+	return func(l *Linter) {
+		l.configPath = path
+	}
+}
+
+// WithFixMode makes the Linter fix the problems reported by the fixable rules.
+func WithFixMode(fixMode bool) Option {
+	return func(l *Linter) {
+		l.fixMode = fixMode
+	}
+}
+
+// WithSuggestFixes makes the Linter attach the edits which fix the failures to them.
+// It's ignored in fix mode.
+func WithSuggestFixes(suggestFixes bool) Option {
+	return func(l *Linter) {
+		l.suggestFixes = suggestFixes
+	}
+}
+
+// WithRules adds the custom rules to the built-in ones.
+// They are enabled by default if IsOfficial returns true, like the built-in ones.
+func WithRules(rules ...rule.Rule) Option {
+	return func(l *Linter) {
+		l.customRules = append(l.customRules, rules...)
+	}
+}
+
+// Linter lints Protocol Buffer files without going through the command line arguments.
+type Linter struct {
+	config       *config.ExternalConfig
+	typedConfig  *Config
+	configYAML   []byte
+	configPath   string
+	fixMode      bool
+	suggestFixes bool
+	customRules  []rule.Rule
+
+	l           *linter.Linter
+	allRules    internalrule.Rules
+	fixingRules internalrule.Rules
+	wasmPlugins []shared.RuleSet
+}
+
+// NewLinter creates a new Linter.
+// Without WithConfig, WithConfigYAML and WithConfigPath, the config file is searched in the same way as the lint command.
+// The last one of WithConfig and WithConfigYAML is used.
+func NewLinter(opts ...Option) (*Linter, error) {
+	l := &Linter{
+		l: linter.NewLinter(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	switch {
+	case l.typedConfig != nil:
+		externalConfig, err := l.typedConfig.toExternalConfig()
+		if err != nil {
+			return nil, err
+		}
+		l.config = externalConfig
+	case l.configYAML != nil:
+		externalConfig, err := config.ParseYAMLConfig(l.configYAML)
+		if err != nil {
+			return nil, err
+		}
+		l.config = externalConfig
+	default:
+		externalConfig, err := config.GetExternalConfig(l.configPath, "")
+		if err != nil {
+			return nil, err
+		}
+		if externalConfig == nil {
+			externalConfig = &config.ExternalConfig{}
+		}
+		l.config = externalConfig
+	}
+
+	wasmPlugins, err := subcmds.BuildWasmPlugins(l.config.WasmPlugins(), false)
+	if err != nil {
+		return nil, err
+	}
+	l.wasmPlugins = wasmPlugins
+	allRules, err := subcmds.NewAllRules(l.config.Lint.RulesOption, l.config.Lint.CustomRules, l.config.Lint.Plugins, l.fixMode, autodisable.Noop, false, wasmPlugins)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	l.allRules = append(allRules, l.customRules...)
+
+	if l.suggestFixes && !l.fixMode {
+		fixingRules, err := subcmds.NewAllRules(l.config.Lint.RulesOption, l.config.Lint.CustomRules, l.config.Lint.Plugins, true, autodisable.Noop, false, nil)
+		if err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+		l.fixingRules = fixingRules
+	}
+	return l, nil
+}
+
+// Close releases the WebAssembly plugins listed in the config.
+// The Linter can't be used after that.
+func (l *Linter) Close() error {
+	subcmds.ClosePlugins(l.wasmPlugins)
+	l.wasmPlugins = nil
+	return nil
+}
+
+// Result represents the result of linting.
+type Result struct {
+	// Failures are the reported problems in the order of the files.
+	Failures []report.Failure
+	// Fixes are the results of fixing each file. These are only set in fix mode.
+	Fixes []FixResult
+}
+
+// FixResult represents the result of fixing a file.
+type FixResult struct {
+	// Path is the display path of the file, which is also used in the failures.
+	// It differs from the original one when a rule renamed the file.
+	Path string
+	// Content is the content after fixing.
+	Content []byte
+	// Changed reports whether fixing changed the content or the path.
+	Changed bool
+}
+
+// LintPaths lints the files and the directories at the paths.
+// In fix mode, the fixed files are written back to the disk.
+// It's safe to call concurrently, though the calls on the same file are serialized.
+func (l *Linter) LintPaths(paths ...string) (Result, error) {
+	protoSet, err := file.NewProtoSet(paths)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Failures: []report.Failure{},
+	}
+	for _, f := range protoSet.ProtoFiles() {
+		unlock := osutil.LockFile(f.Path())
+		err := l.lintFile(f, &result)
+		unlock()
+		if err != nil {
+			return Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// LintContent lints the in-memory content as if it were the file at the path.
+// The config is applied according to the path. The file doesn't have to exist and is never written.
+// It's safe to call concurrently, though the calls on the same file are serialized.
+func (l *Linter) LintContent(path string, content []byte) (Result, error) {
+	f, err := file.NewProtoFileFromPath(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// The rules read the content by the path, so no other call can use it until this one is done.
+	release := osutil.UseOverlay(f.Path(), content)
+	defer release()
+
+	result := Result{
+		Failures: []report.Failure{},
+	}
+	if err := l.lintFile(f, &result); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// lintFile lints the file and adds the failures and the fix to the result.
+// The caller must hold the lock of the file.
+func (l *Linter) lintFile(f file.ProtoFile, result *Result) error {
+	var before []byte
+	if l.fixMode {
+		data, err := osutil.ReadFile(f.Path())
+		if err != nil {
+			return err
+		}
+		before = data
+	}
+
+	fixed := f
+	rs := l.config.SelectRules(f.DisplayPath(), l.allRules)
+	if 0 < len(rs) {
+		var failures []report.Failure
+		var err error
+		fixed, failures, err = l.l.RunFile(f, rs, false)
+		if err != nil {
+			return err
+		}
+		if 0 < len(failures) && 0 < len(l.fixingRules) {
+			failures, err = l.l.SuggestFixes(f, l.config.SelectRules(f.DisplayPath(), l.fixingRules), failures, false)
+			if err != nil {
+				return err
+			}
+		}
+		result.Failures = append(result.Failures, failures...)
+	}
+
+	if l.fixMode {
+		after, err := osutil.ReadFile(fixed.Path())
+		if err != nil {
+			return err
+		}
+		result.Fixes = append(result.Fixes, FixResult{
+			Path:    fixed.DisplayPath(),
+			Content: after,
+			Changed: fixed.Path() != f.Path() || !bytes.Equal(before, after),
+		})
+	}
+	return nil
+}
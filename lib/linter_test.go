@@ -0,0 +1,358 @@
+package lib_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/lib"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+type fileRule struct{}
+
+func (fileRule) ID() string              { return "FILE_RULE" }
+func (fileRule) Purpose() string         { return "Reports every file." }
+func (fileRule) IsOfficial() bool        { return true }
+func (fileRule) Severity() rule.Severity { return rule.SeverityWarning }
+func (r fileRule) Apply(p *parser.Proto) ([]report.Failure, error) {
+	return []report.Failure{
+		report.Failuref(p.Syntax.Meta.Pos, r.ID(), string(r.Severity()), "found"),
+	}, nil
+}
+
+// slowRule takes time to let the other calls run meanwhile.
+type slowRule struct{}
+
+func (slowRule) ID() string              { return "SLOW_RULE" }
+func (slowRule) Purpose() string         { return "Reports nothing slowly." }
+func (slowRule) IsOfficial() bool        { return true }
+func (slowRule) Severity() rule.Severity { return rule.SeverityWarning }
+func (slowRule) Apply(*parser.Proto) ([]report.Failure, error) {
+	time.Sleep(time.Millisecond)
+	return nil, nil
+}
+
+func ruleIDs(fs []report.Failure) []string {
+	var ids []string
+	for _, f := range fs {
+		ids = append(ids, f.RuleID())
+	}
+	return ids
+}
+
+func TestLinter_LintPaths(t *testing.T) {
+	noIndent := []byte("lint:\n  rules:\n    remove:\n      - INDENT\n")
+
+	tests := []struct {
+		name        string
+		inputOpts   []lib.Option
+		inputPath   string
+		wantRuleIDs []string
+	}{
+		{
+			name:      "no failures with the config file",
+			inputOpts: []lib.Option{lib.WithConfigPath(setting_test.TestDataPath("lib", ".protolint.yaml"))},
+			inputPath: setting_test.TestDataPath("lib", "invalid.proto"),
+		},
+		{
+			name:        "failures with the default config",
+			inputOpts:   []lib.Option{lib.WithConfigYAML([]byte("lint: {}"))},
+			inputPath:   setting_test.TestDataPath("lib", "invalid.proto"),
+			wantRuleIDs: []string{"INDENT"},
+		},
+		{
+			name:      "no failures with the config extending the config file",
+			inputOpts: []lib.Option{lib.WithConfigYAML([]byte("lint:\n  extends:\n    - " + setting_test.TestDataPath("lib", ".protolint.yaml") + "\n"))},
+			inputPath: setting_test.TestDataPath("lib", "invalid.proto"),
+		},
+		{
+			name: "failures by the custom rule",
+			inputOpts: []lib.Option{
+				lib.WithConfigYAML(noIndent),
+				lib.WithRules(fileRule{}),
+			},
+			inputPath:   setting_test.TestDataPath("lib", "invalid.proto"),
+			wantRuleIDs: []string{"FILE_RULE"},
+		},
+		{
+			name: "no failures with the typed config",
+			inputOpts: []lib.Option{lib.WithConfig(lib.Config{
+				Rules: lib.Rules{Remove: []string{"INDENT"}},
+			})},
+			inputPath: setting_test.TestDataPath("lib", "invalid.proto"),
+		},
+		{
+			name: "failures with the typed rules option",
+			inputOpts: []lib.Option{lib.WithConfig(lib.Config{
+				Rules: lib.Rules{
+					NoDefault: true,
+					Add:       []string{"MAX_LINE_LENGTH"},
+				},
+				RulesOption: lib.RulesOption{
+					MaxLineLength: lib.MaxLineLengthOption{MaxChars: 30},
+				},
+			})},
+			inputPath:   setting_test.TestDataPath("lib", "invalid.proto"),
+			wantRuleIDs: []string{"MAX_LINE_LENGTH"},
+		},
+		{
+			name: "failures by the typed custom rule",
+			inputOpts: []lib.Option{lib.WithConfig(lib.Config{
+				Rules: lib.Rules{NoDefault: true},
+				CustomRules: []lib.CustomRule{
+					{
+						ID:         "ENUM_NAMES_NO_ALIAS",
+						Target:     "enum",
+						NotPattern: "Alias$",
+					},
+				},
+			})},
+			inputPath:   setting_test.TestDataPath("lib", "invalid.proto"),
+			wantRuleIDs: []string{"ENUM_NAMES_NO_ALIAS"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			l, err := lib.NewLinter(test.inputOpts...)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			defer func() {
+				_ = l.Close()
+			}()
+			got, err := l.LintPaths(test.inputPath)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if !reflect.DeepEqual(ruleIDs(got.Failures), test.wantRuleIDs) {
+				t.Errorf("got %v, but want %v", ruleIDs(got.Failures), test.wantRuleIDs)
+			}
+			if got.Fixes != nil {
+				t.Errorf("got fixes %v, but want nil", got.Fixes)
+			}
+		})
+	}
+}
+
+func TestNewLinter_invalidConfig(t *testing.T) {
+	for _, c := range []lib.Config{
+		{RulesOption: lib.RulesOption{Indent: lib.IndentOption{Style: "3"}}},
+		{CustomRules: []lib.CustomRule{{ID: "NO_TARGET"}}},
+		{Extends: []string{"builtin:not_found"}},
+	} {
+		if _, err := lib.NewLinter(lib.WithConfig(c)); err == nil {
+			t.Errorf("got err nil for %+v, but want err", c)
+		}
+	}
+}
+
+// TestRulesOption checks that each rule option can be given with the typed config.
+func TestRulesOption(t *testing.T) {
+	tagsOf := func(typ reflect.Type) []string {
+		var tags []string
+		for i := 0; i < typ.NumField(); i++ {
+			tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		return tags
+	}
+	got := tagsOf(reflect.TypeOf(lib.RulesOption{}))
+	want := tagsOf(reflect.TypeOf(config.RulesOption{}))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+}
+
+func TestNewLinter_invalidConfigYAML(t *testing.T) {
+	for _, data := range []string{
+		"lint:\n  unknown_field: true\n",
+		"lint:\n  extends:\n    - builtin:not_found\n",
+	} {
+		if _, err := lib.NewLinter(lib.WithConfigYAML([]byte(data))); err == nil {
+			t.Errorf("got err nil for %q, but want err", data)
+		}
+	}
+}
+
+func TestLinter_LintContent(t *testing.T) {
+	path := setting_test.TestDataPath("lib", "in_memory.proto")
+	content := []byte(`syntax = "proto3";
+
+enum Enum {
+    ENUM_UNSPECIFIED = 0;
+}
+`)
+
+	tests := []struct {
+		name        string
+		inputFix    bool
+		wantRuleIDs []string
+		wantFixes   []lib.FixResult
+	}{
+		{
+			name:        "lint the content",
+			wantRuleIDs: []string{"INDENT"},
+		},
+		{
+			name:        "fix the content",
+			inputFix:    true,
+			wantRuleIDs: []string{"INDENT"},
+			wantFixes: []lib.FixResult{
+				{
+					Path: "../_testdata/lib/in_memory.proto",
+					Content: []byte(`syntax = "proto3";
+
+enum Enum {
+  ENUM_UNSPECIFIED = 0;
+}
+`),
+					Changed: true,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			l, err := lib.NewLinter(
+				lib.WithConfigYAML([]byte("lint: {}")),
+				lib.WithFixMode(test.inputFix),
+			)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			defer func() {
+				_ = l.Close()
+			}()
+			got, err := l.LintContent(path, content)
+			if err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+			if !reflect.DeepEqual(ruleIDs(got.Failures), test.wantRuleIDs) {
+				t.Errorf("got %v, but want %v", ruleIDs(got.Failures), test.wantRuleIDs)
+			}
+			if !reflect.DeepEqual(got.Fixes, test.wantFixes) {
+				t.Errorf("got %v, but want %v", got.Fixes, test.wantFixes)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("got the file written, but want no file")
+			}
+		})
+	}
+}
+
+func TestLinter_SuggestFixes(t *testing.T) {
+	path := setting_test.TestDataPath("lib", "in_memory.proto")
+	content := []byte(`syntax = "proto3";
+
+enum Enum {
+    ENUM_UNSPECIFIED = 0;
+}
+`)
+	filename := "../_testdata/lib/in_memory.proto"
+
+	l, err := lib.NewLinter(
+		lib.WithConfigYAML([]byte("lint: {}")),
+		lib.WithSuggestFixes(true),
+	)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	defer func() {
+		_ = l.Close()
+	}()
+	got, err := l.LintContent(path, content)
+	if err != nil {
+		t.Fatalf("got err %v, but want nil", err)
+	}
+	if len(got.Failures) != 1 {
+		t.Fatalf("got %v, but want one failure", got.Failures)
+	}
+
+	want := []report.SuggestedEdit{
+		{
+			Pos:     meta.Position{Filename: filename, Offset: 34, Line: 4, Column: 3},
+			End:     meta.Position{Filename: filename, Offset: 36, Line: 4, Column: 5},
+			NewText: "",
+		},
+	}
+	if !reflect.DeepEqual(got.Failures[0].SuggestedEdits(), want) {
+		t.Errorf("got %v, but want %v", got.Failures[0].SuggestedEdits(), want)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("got the file written, but want no file")
+	}
+}
+
+func TestLinter_concurrent(t *testing.T) {
+	path := setting_test.TestDataPath("lib", "invalid.proto")
+	valid, err := os.ReadFile(setting_test.TestDataPath("lib", "valid.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var linters []*lib.Linter
+	for i := 0; i < 2; i++ {
+		l, err := lib.NewLinter(
+			lib.WithConfigYAML([]byte("lint: {}")),
+			lib.WithRules(slowRule{}),
+		)
+		if err != nil {
+			t.Fatalf("got err %v, but want nil", err)
+		}
+		defer func() {
+			_ = l.Close()
+		}()
+		linters = append(linters, l)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		l := linters[i%len(linters)]
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			got, err := l.LintContent(path, valid)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if ids := ruleIDs(got.Failures); ids != nil {
+				errs <- fmt.Errorf("got %v from the content, but want nil", ids)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			got, err := l.LintPaths(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if ids := ruleIDs(got.Failures); !reflect.DeepEqual(ids, []string{"INDENT"}) {
+				errs <- fmt.Errorf("got %v from the file, but want [INDENT]", ids)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
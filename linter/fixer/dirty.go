@@ -0,0 +1,28 @@
+package fixer
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// dirtyFiles records the files rewritten by BaseFixing.Finally.
+// The linter consults it to decide whether a file must be parsed again.
+var dirtyFiles sync.Map
+
+func dirtyKey(fileName string) string {
+	if abs, err := filepath.Abs(fileName); err == nil {
+		return abs
+	}
+	return filepath.Clean(fileName)
+}
+
+func markDirty(fileName string) {
+	dirtyFiles.Store(dirtyKey(fileName), struct{}{})
+}
+
+// TakeDirty reports whether a fixer has rewritten the file since the last call.
+// The recorded flag is cleared.
+func TakeDirty(fileName string) bool {
+	_, ok := dirtyFiles.LoadAndDelete(dirtyKey(fileName))
+	return ok
+}
@@ -0,0 +1,53 @@
+package fixer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yoheimuta/protolint/linter/fixer"
+)
+
+func TestBaseFixing_Finally_marksDirty(t *testing.T) {
+	tests := []struct {
+		name      string
+		proc      func(content []byte) []byte
+		wantDirty bool
+	}{
+		{
+			name: "no change leaves the file clean",
+			proc: func(content []byte) []byte { return content },
+		},
+		{
+			name:      "a change marks the file dirty",
+			proc:      func(content []byte) []byte { return append(content, '\n') },
+			wantDirty: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "a.proto")
+			if err := os.WriteFile(path, []byte(`syntax = "proto3";`), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := fixer.NewBaseFixing(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.ReplaceContent(test.proc)
+			if err := f.Finally(); err != nil {
+				t.Fatalf("got err %v, but want nil", err)
+			}
+
+			if got := fixer.TakeDirty(path); got != test.wantDirty {
+				t.Errorf("got %v, but want %v", got, test.wantDirty)
+			}
+			if fixer.TakeDirty(path) {
+				t.Errorf("got dirty after TakeDirty, but want clean")
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package fixer
 
 import (
 	"bytes"
-	"os"
 	"strings"
 
 	"github.com/yoheimuta/go-protoparser/v4/lexer"
@@ -49,6 +48,7 @@ func NewFixing(fixMode bool, proto *parser.Proto) (Fixing, error) {
 // BaseFixing implements Fixing.
 type BaseFixing struct {
 	content    []byte
+	origin     []byte
 	lineEnding string
 	fileName   string
 	textEdits  []TextEdit
@@ -56,7 +56,7 @@ type BaseFixing struct {
 
 // NewBaseFixing creates a BaseFixing.
 func NewBaseFixing(protoFileName string) (*BaseFixing, error) {
-	content, err := os.ReadFile(protoFileName)
+	content, err := osutil.ReadFile(protoFileName)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +70,7 @@ func NewBaseFixing(protoFileName string) (*BaseFixing, error) {
 
 	return &BaseFixing{
 		content:    content,
+		origin:     bytes.Clone(content),
 		lineEnding: lineEnding,
 		fileName:   protoFileName,
 	}, nil
@@ -116,6 +117,7 @@ func (f *BaseFixing) Lines() []string {
 }
 
 // Finally writes the fixed content to the file.
+// It leaves the file untouched when nothing has changed.
 func (f *BaseFixing) Finally() error {
 	diff := 0
 	for _, t := range f.textEdits {
@@ -124,7 +126,15 @@ func (f *BaseFixing) Finally() error {
 		f.content = append(f.content[:t.Pos], append(t.NewText, f.content[t.End+1:]...)...)
 		diff += len(t.NewText) - (t.End - t.Pos + 1)
 	}
-	return osutil.WriteExistingFile(f.fileName, f.content)
+	if bytes.Equal(f.content, f.origin) {
+		return nil
+	}
+	err := osutil.WriteExistingFile(f.fileName, f.content)
+	if err != nil {
+		return err
+	}
+	markDirty(f.fileName)
+	return nil
 }
 
 // Replace records a textedit to replace the old with the next later.
@@ -0,0 +1,224 @@
+// Package linttest provides the utilities to test the rules, like golang.org/x/tools/go/analysis/analysistest.
+//
+// The rule is applied to the .proto files in the testdata directory, and the failures are checked against
+// the comments in the files like the following, which expect a failure of the rule on the line whose message
+// matches the regular expression:
+//
+//	enum fooBar { // want ENUM_NAMES_UPPER_CAMEL_CASE `Enum name "fooBar" must be UpperCamelCase`
+//
+// The files are never written even when the rule fixes them.
+package linttest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/yoheimuta/protolint/internal/diffutil"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalsymbol "github.com/yoheimuta/protolint/internal/linter/symbol"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+// GoldenSuffix is appended to the name of the file to make the name of the golden file.
+const GoldenSuffix = ".golden"
+
+// Testing is the part of *testing.T used by this package.
+type Testing interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Result is the result of applying the rule to a file.
+type Result struct {
+	// Filename is the path to the file, which is the dir joined with the name.
+	Filename string
+	// Failures are the failures reported by the rule.
+	Failures []report.Failure
+	// Fixed is the content of the file after the rule is applied.
+	Fixed []byte
+	// Err is the error returned by the rule.
+	Err error
+}
+
+// Run applies the rule to the files in the dir, and checks the failures against the want comments in the files.
+// The files are the names relative to the dir. All .proto files in the dir are used when no file is given.
+// The rule gets the symbol table of the files and their imports, which are searched in the dir,
+// if it implements rule.HasApplyWithSymbols.
+func Run(
+	t Testing,
+	dir string,
+	r rule.HasApply,
+	files ...string,
+) []*Result {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	return run(t, dir, r, false, files)
+}
+
+// RunWithFixes does the same as Run, and also checks the content fixed by the rule against the golden file,
+// whose name is the one of the file followed by GoldenSuffix. The rule should be created in the fix mode.
+// The golden file must exist, and is the same as the file when nothing is fixed.
+func RunWithFixes(
+	t Testing,
+	dir string,
+	r rule.HasApply,
+	files ...string,
+) []*Result {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	return run(t, dir, r, true, files)
+}
+
+func run(
+	t Testing,
+	dir string,
+	r rule.HasApply,
+	checkFixes bool,
+	files []string,
+) []*Result {
+	if len(files) == 0 {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.proto"))
+		if err != nil {
+			t.Errorf("failed to find the files in %s: %v", dir, err)
+			return nil
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			files = append(files, filepath.Base(m))
+		}
+	}
+	if len(files) == 0 {
+		t.Errorf("not found any .proto file in %s", dir)
+		return nil
+	}
+
+	var protoFiles []file.ProtoFile
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		protoFiles = append(protoFiles, file.NewProtoFile(path, path))
+	}
+	var symbols *symbol.Table
+	if _, ok := r.(rule.HasApplyWithSymbols); ok {
+		symbols = internalsymbol.Build(protoFiles, []string{dir})
+	}
+
+	var results []*Result
+	for _, f := range protoFiles {
+		result, err := applyFile(f, r, symbols)
+		if err != nil {
+			t.Errorf("%s: %v", f.Path(), err)
+			continue
+		}
+		results = append(results, result)
+		if result.Err != nil {
+			t.Errorf("%s: got err %v, but want nil", f.Path(), result.Err)
+			continue
+		}
+
+		checkFailures(t, f.Path(), result.Failures)
+		if checkFixes {
+			checkFixed(t, f.Path(), result.Fixed)
+		}
+	}
+	return results
+}
+
+// applyFile applies the rule to the in-memory copy of the file, so that the fixes are kept in memory.
+func applyFile(
+	f file.ProtoFile,
+	r rule.HasApply,
+	symbols *symbol.Table,
+) (*Result, error) {
+	content, err := os.ReadFile(f.Path())
+	if err != nil {
+		return nil, err
+	}
+	osutil.SetOverlay(f.Path(), content)
+	defer osutil.RemoveOverlay(f.Path())
+
+	p, err := f.Parse(false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Filename: f.Path(),
+	}
+	if rs, ok := r.(rule.HasApplyWithSymbols); ok {
+		result.Failures, result.Err = rs.ApplyWithSymbols(p, symbols.WithProto(p))
+	} else {
+		result.Failures, result.Err = r.Apply(p)
+	}
+
+	result.Fixed, err = osutil.ReadFile(f.Path())
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkFailures reports the failures which no want comment expects, and the want comments which no failure matches.
+// The want comments are read from the original file because the fixes may move them.
+func checkFailures(
+	t Testing,
+	path string,
+	failures []report.Failure,
+) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+	expectations, err := parseExpectations(content)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+
+	for _, f := range failures {
+		if !matchExpectation(expectations, f) {
+			t.Errorf("%s:%d:%d: unexpected failure %s: %s", path, f.Pos().Line, f.Pos().Column, f.RuleID(), f.Message())
+		}
+	}
+	for _, e := range expectations {
+		if !e.matched {
+			t.Errorf("%s:%d: no failure %s matching %q was reported", path, e.line, e.ruleID, e.re)
+		}
+	}
+}
+
+func matchExpectation(
+	expectations []*expectation,
+	f report.Failure,
+) bool {
+	for _, e := range expectations {
+		if e.matched || e.line != f.Pos().Line || e.ruleID != f.RuleID() || !e.re.MatchString(f.Message()) {
+			continue
+		}
+		e.matched = true
+		return true
+	}
+	return false
+}
+
+func checkFixed(
+	t Testing,
+	path string,
+	fixed []byte,
+) {
+	golden := path + GoldenSuffix
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Errorf("%s: failed to read the golden file: %v", path, err)
+		return
+	}
+	if !bytes.Equal(fixed, want) {
+		t.Errorf("%s: the fixed content differs from %s:\n%s", path, golden, diffutil.Unified(golden, want, fixed))
+	}
+}
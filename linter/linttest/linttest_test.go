@@ -0,0 +1,113 @@
+package linttest_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/addon/rules"
+	"github.com/yoheimuta/protolint/internal/setting_test"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+	"github.com/yoheimuta/protolint/linter/linttest"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// recorder records the errors instead of failing the test.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRunWithFixes(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputDir   string
+		inputRule  rule.HasApply
+		inputFiles []string
+		inputFixes bool
+		wantErrors []string
+	}{
+		{
+			name:       "the failures and the fixes are expected",
+			inputDir:   "enumNamesUpperCamelCase",
+			inputRule:  rules.NewEnumNamesUpperCamelCaseRule(rule.SeverityError, true, autodisable.Noop),
+			inputFixes: true,
+		},
+		{
+			name:       "the rule gets the symbol table",
+			inputDir:   "importsUnused",
+			inputRule:  rules.NewImportsUnusedRule(rule.SeverityError, true),
+			inputFiles: []string{"a.proto"},
+			inputFixes: true,
+		},
+		{
+			name:      "the unexpected failures and the unmatched expectations are reported",
+			inputDir:  "mismatch",
+			inputRule: rules.NewEnumNamesUpperCamelCaseRule(rule.SeverityError, false, autodisable.Noop),
+			wantErrors: []string{
+				`a.proto:3:1: unexpected failure ENUM_NAMES_UPPER_CAMEL_CASE: Enum name "enum_name" must be UpperCamelCase like "EnumName"`,
+				`a.proto:7: no failure ENUM_NAMES_UPPER_CAMEL_CASE matching "FooBar" was reported`,
+			},
+		},
+		{
+			name:       "the missing golden file is reported",
+			inputDir:   "mismatch",
+			inputRule:  rules.NewEnumNamesUpperCamelCaseRule(rule.SeverityError, true, autodisable.Noop),
+			inputFixes: true,
+			wantErrors: []string{
+				`a.proto:3:1: unexpected failure ENUM_NAMES_UPPER_CAMEL_CASE: Enum name "enum_name" must be UpperCamelCase like "EnumName"`,
+				`a.proto:7: no failure ENUM_NAMES_UPPER_CAMEL_CASE matching "FooBar" was reported`,
+				`a.proto: failed to read the golden file`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			dir := setting_test.TestDataPath("linttest", test.inputDir)
+
+			r := &recorder{}
+			run := linttest.Run
+			if test.inputFixes {
+				run = linttest.RunWithFixes
+			}
+			results := run(r, dir, test.inputRule, test.inputFiles...)
+			if len(results) == 0 {
+				t.Errorf("got no results")
+			}
+
+			var got []string
+			for _, e := range r.errors {
+				e = strings.TrimPrefix(e, dir+string(filepath.Separator))
+				// Drop the error of the os package, which depends on the platform.
+				if i := strings.Index(e, "golden file:"); 0 <= i {
+					e = e[:i+len("golden file")]
+				}
+				got = append(got, e)
+			}
+			if !reflect.DeepEqual(got, test.wantErrors) {
+				t.Errorf("got %q, but want %q", got, test.wantErrors)
+			}
+		})
+	}
+}
+
+func TestRunWithFixes_diff(t *testing.T) {
+	dir := setting_test.TestDataPath("linttest", "enumNamesUpperCamelCase")
+
+	r := &recorder{}
+	// The rule not in the fix mode leaves the content different from the golden file.
+	linttest.RunWithFixes(r, dir, rules.NewEnumNamesUpperCamelCaseRule(rule.SeverityError, false, autodisable.Noop), "invalid.proto")
+	if len(r.errors) != 1 {
+		t.Fatalf("got %q, but want an error", r.errors)
+	}
+	if !strings.Contains(r.errors[0], "-enum EnumName {") || !strings.Contains(r.errors[0], "+enum enum_name {") {
+		t.Errorf("got %s, but want the diff", r.errors[0])
+	}
+}
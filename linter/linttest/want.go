@@ -0,0 +1,128 @@
+package linttest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expectation is a failure expected by a want comment.
+type expectation struct {
+	line   int
+	ruleID string
+	re     *regexp.Regexp
+	// matched is true once a failure matches it, since each expectation matches exactly one failure.
+	matched bool
+}
+
+// comment is a comment in the content and the line it starts at.
+type comment struct {
+	line int
+	text string
+}
+
+// parseExpectations finds the want comments in the content.
+// A comment like `// want RULE_ID "regex"` expects a failure of the rule on the line, whose message matches the regex.
+// It can have more than one pair of the rule ID and the regex, and the regex can be quoted with backquotes.
+func parseExpectations(content []byte) ([]*expectation, error) {
+	var es []*expectation
+	for _, c := range scanComments(content) {
+		text := strings.TrimSpace(c.text)
+		if text != "want" && !strings.HasPrefix(text, "want ") {
+			continue
+		}
+		pairs, err := parseWant(strings.TrimPrefix(text, "want"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid want comment %q: %w", c.line, text, err)
+		}
+		for _, p := range pairs {
+			re, err := regexp.Compile(p[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid regular expression %q: %w", c.line, p[1], err)
+			}
+			es = append(es, &expectation{
+				line:   c.line,
+				ruleID: p[0],
+				re:     re,
+			})
+		}
+	}
+	return es, nil
+}
+
+// parseWant parses the pairs of the rule ID and the regex.
+func parseWant(s string) ([][2]string, error) {
+	var pairs [][2]string
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			break
+		}
+
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end < 0 {
+			return nil, fmt.Errorf("not found the regex after %s", s)
+		}
+		id := s[:end]
+		if strings.ContainsAny(id, "\"`") {
+			return nil, fmt.Errorf("not found the rule ID before %s", id)
+		}
+		s = strings.TrimSpace(s[end:])
+
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("not found the quoted regex after %s", id)
+		}
+		re, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, [2]string{id, re})
+		s = s[len(quoted):]
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("not found any expectation")
+	}
+	return pairs, nil
+}
+
+// scanComments returns the line and block comments in the content, skipping the string literals.
+func scanComments(content []byte) []comment {
+	var cs []comment
+	line := 1
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '\n':
+			line++
+		case c == '"' || c == '\'':
+			for i++; i < len(content) && content[i] != c && content[i] != '\n'; i++ {
+				if content[i] == '\\' {
+					i++
+				}
+			}
+			// The unterminated string ends at the newline, which is counted next.
+			if i < len(content) && content[i] == '\n' {
+				i--
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			start := i + 2
+			for i = start; i < len(content) && content[i] != '\n'; i++ {
+			}
+			cs = append(cs, comment{line: line, text: string(content[start:i])})
+			i--
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			start := i + 2
+			end := strings.Index(string(content[start:]), "*/")
+			if end < 0 {
+				end = len(content) - start
+			}
+			text := string(content[start : start+end])
+			cs = append(cs, comment{line: line, text: text})
+			line += strings.Count(text, "\n")
+			i = start + end + 1
+		}
+	}
+	return cs
+}
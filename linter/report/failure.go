@@ -9,11 +9,21 @@ import (
 )
 
 // Failure represents a lint error information.
+// It's comparable, so the edits are held behind a pointer.
 type Failure struct {
-	pos      meta.Position
-	message  string
-	ruleID   string
-	severity string
+	pos            meta.Position
+	message        string
+	ruleID         string
+	severity       string
+	suggestedEdits *[]SuggestedEdit
+}
+
+// SuggestedEdit represents an edit which fixes the failure.
+// It replaces the original content from Pos to End, exclusive, with NewText.
+type SuggestedEdit struct {
+	Pos     meta.Position
+	End     meta.Position
+	NewText string
 }
 
 // Failuref creates a new Failure and the formatting works like fmt.Sprintf.
@@ -57,6 +67,21 @@ func (f Failure) Severity() string {
 	return f.severity
 }
 
+// SuggestedEdits returns the edits to fix the failure. It's empty unless the fix is suggested.
+func (f Failure) SuggestedEdits() []SuggestedEdit {
+	if f.suggestedEdits == nil {
+		return nil
+	}
+	return *f.suggestedEdits
+}
+
+// WithSuggestedEdits returns a copy of the failure with the edits to fix it.
+func (f Failure) WithSuggestedEdits(edits ...SuggestedEdit) Failure {
+	copied := append([]SuggestedEdit(nil), edits...)
+	f.suggestedEdits = &copied
+	return f
+}
+
 // FilenameWithoutExt returns a filename without the extension.
 func (f Failure) FilenameWithoutExt() string {
 	name := f.pos.Filename
@@ -0,0 +1,33 @@
+package report_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/linter/report"
+)
+
+func TestFailure_WithSuggestedEdits(t *testing.T) {
+	edit := report.SuggestedEdit{
+		Pos:     meta.Position{Offset: 1, Line: 1, Column: 2},
+		End:     meta.Position{Offset: 4, Line: 1, Column: 5},
+		NewText: "Foo",
+	}
+	failure := report.Failuref(meta.Position{Line: 1, Column: 2}, "RULE", "error", "message")
+	withEdits := failure.WithSuggestedEdits(edit)
+
+	if got := failure.SuggestedEdits(); got != nil {
+		t.Errorf("got %v, but want nil", got)
+	}
+	if got := withEdits.SuggestedEdits(); !reflect.DeepEqual(got, []report.SuggestedEdit{edit}) {
+		t.Errorf("got %v, but want %v", got, []report.SuggestedEdit{edit})
+	}
+
+	// Failure must stay comparable for the users comparing it or using it as a map key.
+	seen := map[report.Failure]bool{failure: true, withEdits: true}
+	if !seen[failure] || !seen[withEdits] || failure == withEdits {
+		t.Errorf("got %v, but want both failures to be distinct keys", seen)
+	}
+}
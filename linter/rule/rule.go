@@ -4,6 +4,7 @@ import (
 	"github.com/yoheimuta/go-protoparser/v4/parser"
 
 	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/symbol"
 )
 
 // Severity represents the severity of the rule.
@@ -25,6 +26,14 @@ type HasApply interface {
 	Apply(proto *parser.Proto) ([]report.Failure, error)
 }
 
+// HasApplyWithSymbols represents a rule which needs to look into the other files.
+// The linter calls ApplyWithSymbols instead of Apply when the rule implements it.
+type HasApplyWithSymbols interface {
+	// ApplyWithSymbols applies the rule to the proto with the symbol table of the project,
+	// which includes the proto itself and the files imported by it.
+	ApplyWithSymbols(proto *parser.Proto, symbols *symbol.Table) ([]report.Failure, error)
+}
+
 // HasID represents a rule with ID.
 type HasID interface {
 	// ID returns the ID of this rule. This should be all UPPER_SNAKE_CASE.
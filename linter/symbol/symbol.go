@@ -0,0 +1,269 @@
+// Package symbol provides the project-wide symbol table of the Protocol Buffer files,
+// which lets the rules look beyond the file being linted.
+package symbol
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// Kind is a kind of the symbol.
+type Kind int
+
+// Kind constants.
+const (
+	KindMessage Kind = iota
+	KindEnum
+	KindService
+	KindExtension
+)
+
+// String returns the kind name.
+func (k Kind) String() string {
+	switch k {
+	case KindMessage:
+		return "message"
+	case KindEnum:
+		return "enum"
+	case KindExtension:
+		return "extension"
+	default:
+		return "service"
+	}
+}
+
+// Symbol is a definition of a message, an enum, a service or an extension.
+type Symbol struct {
+	// Name is the fully-qualified name without the leading dot.
+	Name string
+	Kind Kind
+	// File is the path of the file which defines the symbol.
+	File string
+	Pos  meta.Position
+}
+
+// Import is an import statement.
+type Import struct {
+	// Path is the path as written in the import statement.
+	Path     string
+	IsPublic bool
+	IsWeak   bool
+	// File is the path of the imported file, or empty when it isn't found.
+	File string
+	Pos  meta.Position
+}
+
+// Reference is a type name referred by a field, an RPC or an extend,
+// or an extension name referred by a custom option.
+type Reference struct {
+	// Name is the type or extension name as written.
+	Name string
+	// Scope is the fully-qualified name of the message enclosing the reference, or the package.
+	Scope string
+	// IsOption reports whether the name is the extension of a custom option.
+	IsOption bool
+	Pos      meta.Position
+}
+
+// File is a Protocol Buffer file in the table.
+type File struct {
+	// Path is the absolute path to the file.
+	// It's the import path for the well-known types which don't exist on the disk.
+	Path string
+	// ImportPath is the path relative to the include path, which the other files import the file with.
+	ImportPath string
+	Package    string
+	// Options maps the names of the file options to their constants as written.
+	Options    map[string]string
+	Imports    []Import
+	Symbols    []Symbol
+	References []Reference
+	// IsWellKnown reports whether the file is one of the well-known types shipped with protoc.
+	IsWellKnown bool
+}
+
+// NewFile creates a new File of the parsed proto.
+// resolve returns the path of the imported file, or empty when it isn't found.
+func NewFile(
+	proto *parser.Proto,
+	path string,
+	importPath string,
+	resolve func(importPath string) string,
+) *File {
+	f := &File{
+		Path:       path,
+		ImportPath: importPath,
+	}
+	for _, v := range proto.ProtoBody {
+		if p, ok := v.(*parser.Package); ok {
+			f.Package = p.Name
+			break
+		}
+	}
+
+	for _, v := range proto.ProtoBody {
+		switch e := v.(type) {
+		case *parser.Option:
+			if f.Options == nil {
+				f.Options = make(map[string]string)
+			}
+			f.Options[e.OptionName] = e.Constant
+		case *parser.Import:
+			importPath := unquote(e.Location)
+			f.Imports = append(f.Imports, Import{
+				Path:     importPath,
+				IsPublic: e.Modifier == parser.ImportModifierPublic,
+				IsWeak:   e.Modifier == parser.ImportModifierWeak,
+				File:     resolve(importPath),
+				Pos:      e.Meta.Pos,
+			})
+		case *parser.Service:
+			f.addSymbol(join(f.Package, e.ServiceName), KindService, e.Meta.Pos)
+			for _, b := range e.ServiceBody {
+				switch s := b.(type) {
+				case *parser.Option:
+					f.addOptionReferences(s.OptionName, f.Package, s.Meta.Pos)
+				case *parser.RPC:
+					if s.RPCRequest != nil {
+						f.addReference(s.RPCRequest.MessageType, f.Package, s.RPCRequest.Meta.Pos)
+					}
+					if s.RPCResponse != nil {
+						f.addReference(s.RPCResponse.MessageType, f.Package, s.RPCResponse.Meta.Pos)
+					}
+					for _, o := range s.Options {
+						f.addOptionReferences(o.OptionName, f.Package, o.Meta.Pos)
+					}
+				}
+			}
+		}
+	}
+	f.addBody(f.Package, proto.ProtoBody)
+	return f
+}
+
+func (f *File) addSymbol(name string, kind Kind, pos meta.Position) {
+	f.Symbols = append(f.Symbols, Symbol{
+		Name: name,
+		Kind: kind,
+		File: f.Path,
+		Pos:  pos,
+	})
+}
+
+func (f *File) addReference(name string, scope string, pos meta.Position) {
+	if isScalar(name) {
+		return
+	}
+	f.References = append(f.References, Reference{
+		Name:  name,
+		Scope: scope,
+		Pos:   pos,
+	})
+}
+
+// optionExtensionRegexp matches the parenthesized extension names in an option name like (a.b).c.
+var optionExtensionRegexp = regexp.MustCompile(`\((\.?[\w.]+)\)`)
+
+func (f *File) addOptionReferences(optionName string, scope string, pos meta.Position) {
+	for _, m := range optionExtensionRegexp.FindAllStringSubmatch(optionName, -1) {
+		f.References = append(f.References, Reference{
+			Name:     m[1],
+			Scope:    scope,
+			IsOption: true,
+			Pos:      pos,
+		})
+	}
+}
+
+func (f *File) addFieldOptionReferences(options []*parser.FieldOption, scope string, pos meta.Position) {
+	for _, o := range options {
+		f.addOptionReferences(o.OptionName, scope, pos)
+	}
+}
+
+func (f *File) addBody(scope string, body []parser.Visitee) {
+	for _, v := range body {
+		switch e := v.(type) {
+		case *parser.Message:
+			name := join(scope, e.MessageName)
+			f.addSymbol(name, KindMessage, e.Meta.Pos)
+			f.addBody(name, e.MessageBody)
+		case *parser.GroupField:
+			name := join(scope, e.GroupName)
+			f.addSymbol(name, KindMessage, e.Meta.Pos)
+			f.addBody(name, e.MessageBody)
+		case *parser.Enum:
+			f.addSymbol(join(scope, e.EnumName), KindEnum, e.Meta.Pos)
+			for _, b := range e.EnumBody {
+				switch ev := b.(type) {
+				case *parser.Option:
+					f.addOptionReferences(ev.OptionName, scope, ev.Meta.Pos)
+				case *parser.EnumField:
+					for _, o := range ev.EnumValueOptions {
+						f.addOptionReferences(o.OptionName, scope, ev.Meta.Pos)
+					}
+				}
+			}
+		case *parser.Option:
+			f.addOptionReferences(e.OptionName, scope, e.Meta.Pos)
+		case *parser.Field:
+			f.addReference(e.Type, scope, e.Meta.Pos)
+			f.addFieldOptionReferences(e.FieldOptions, scope, e.Meta.Pos)
+		case *parser.MapField:
+			f.addReference(e.Type, scope, e.Meta.Pos)
+			f.addFieldOptionReferences(e.FieldOptions, scope, e.Meta.Pos)
+		case *parser.Oneof:
+			for _, o := range e.Options {
+				f.addOptionReferences(o.OptionName, scope, o.Meta.Pos)
+			}
+			for _, of := range e.OneofFields {
+				f.addReference(of.Type, scope, of.Meta.Pos)
+				f.addFieldOptionReferences(of.FieldOptions, scope, of.Meta.Pos)
+			}
+		case *parser.Extend:
+			f.addReference(e.MessageType, scope, e.Meta.Pos)
+			for _, b := range e.ExtendBody {
+				if field, ok := b.(*parser.Field); ok {
+					f.addSymbol(join(scope, field.FieldName), KindExtension, field.Meta.Pos)
+				}
+			}
+			f.addBody(scope, e.ExtendBody)
+		}
+	}
+}
+
+var scalarTypes = map[string]bool{
+	"double":   true,
+	"float":    true,
+	"int32":    true,
+	"int64":    true,
+	"uint32":   true,
+	"uint64":   true,
+	"sint32":   true,
+	"sint64":   true,
+	"fixed32":  true,
+	"fixed64":  true,
+	"sfixed32": true,
+	"sfixed64": true,
+	"bool":     true,
+	"string":   true,
+	"bytes":    true,
+}
+
+func isScalar(name string) bool {
+	return scalarTypes[name]
+}
+
+func join(scope string, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, `"'`)
+}
@@ -0,0 +1,153 @@
+package symbol_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/linter/symbol"
+)
+
+func TestNewFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		inputContent   string
+		wantSymbols    []string
+		wantReferences []string
+	}{
+		{
+			name: "types",
+			inputContent: `syntax = "proto3";
+package p;
+message A {
+  message B {}
+  B b = 1;
+  map<string, C> c = 2;
+  oneof o {
+    string s = 3;
+    D d = 4;
+  }
+}
+service S {
+  rpc Get(A) returns (A.B);
+}
+`,
+			wantSymbols:    []string{"service p.S", "message p.A", "message p.A.B"},
+			wantReferences: []string{"p A", "p A.B", "p.A B", "p.A C", "p.A D"},
+		},
+		{
+			name: "extensions and custom options",
+			inputContent: `syntax = "proto3";
+package p;
+option (file_opt) = true;
+extend google.protobuf.FieldOptions {
+  string field_opt = 50000;
+}
+message A {
+  option (msg_opt).name = "a";
+  string a = 1 [(field_opt) = "x", deprecated = true];
+  extend B {
+    string nested = 100;
+  }
+}
+enum E {
+  option (.q.enum_opt) = true;
+  E_UNSPECIFIED = 0 [(value_opt) = 1];
+}
+service S {
+  option (svc_opt) = true;
+  rpc Get(A) returns (A) {
+    option (google.api.http) = { get: "/v1" };
+  }
+}
+`,
+			wantSymbols: []string{
+				"service p.S",
+				"extension p.field_opt",
+				"message p.A",
+				"extension p.A.nested",
+				"enum p.E",
+			},
+			wantReferences: []string{
+				"p option svc_opt",
+				"p A",
+				"p A",
+				"p option google.api.http",
+				"p option file_opt",
+				"p google.protobuf.FieldOptions",
+				"p.A option msg_opt",
+				"p.A option field_opt",
+				"p.A B",
+				"p option .q.enum_opt",
+				"p option value_opt",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			f := symbol.NewFile(parse(t, "a.proto", test.inputContent), "a.proto", "a.proto", func(string) string {
+				return ""
+			})
+
+			var gotSymbols []string
+			for _, s := range f.Symbols {
+				gotSymbols = append(gotSymbols, s.Kind.String()+" "+s.Name)
+			}
+			if !reflect.DeepEqual(gotSymbols, test.wantSymbols) {
+				t.Errorf("got %v, but want %v", gotSymbols, test.wantSymbols)
+			}
+
+			var gotReferences []string
+			for _, r := range f.References {
+				ref := r.Scope + " " + r.Name
+				if r.IsOption {
+					ref = r.Scope + " option " + r.Name
+				}
+				gotReferences = append(gotReferences, ref)
+			}
+			if !reflect.DeepEqual(gotReferences, test.wantReferences) {
+				t.Errorf("got %v, but want %v", gotReferences, test.wantReferences)
+			}
+		})
+	}
+}
+
+func TestLookupWellKnown(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputName string
+		wantFile  string
+		wantFound bool
+	}{
+		{
+			name:      "message",
+			inputName: "google.protobuf.Duration",
+			wantFile:  "google/protobuf/duration.proto",
+			wantFound: true,
+		},
+		{
+			name:      "fully-qualified name with the leading dot",
+			inputName: ".google.protobuf.FieldDescriptorProto.Type",
+			wantFile:  "google/protobuf/descriptor.proto",
+			wantFound: true,
+		},
+		{
+			name:      "not well-known",
+			inputName: "google.protobuf.Unknown",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, found := symbol.LookupWellKnown(test.inputName)
+			if found != test.wantFound {
+				t.Errorf("got found %v, but want %v", found, test.wantFound)
+			}
+			if got.File != test.wantFile {
+				t.Errorf("got %s, but want %s", got.File, test.wantFile)
+			}
+		})
+	}
+}
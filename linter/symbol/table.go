@@ -0,0 +1,262 @@
+package symbol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+)
+
+// Table is the symbol table of the files being linted and the ones imported by them.
+//
+// A Table is immutable and safe for concurrent use.
+type Table struct {
+	files map[string]*File
+	// displayPaths maps the filename in the parsed proto to the path of the file.
+	displayPaths map[string]string
+	// importPaths maps the import paths to the paths of the files found before.
+	importPaths map[string]string
+	symbols     map[string][]Symbol
+	// packages maps the package names and their prefixes to the paths of the files declaring them.
+	packages map[string][]string
+}
+
+// NewTable creates a new Table of the files.
+// displayPaths maps the filename in the parsed proto to the path of each file.
+func NewTable(
+	files []*File,
+	displayPaths map[string]string,
+) *Table {
+	t := &Table{
+		files:        make(map[string]*File),
+		displayPaths: make(map[string]string),
+		importPaths:  make(map[string]string),
+		symbols:      make(map[string][]Symbol),
+		packages:     make(map[string][]string),
+	}
+	for displayPath, path := range displayPaths {
+		t.displayPaths[displayPath] = path
+	}
+	for _, f := range files {
+		t.files[f.Path] = f
+		if f.ImportPath != "" {
+			t.importPaths[f.ImportPath] = f.Path
+		}
+		for _, imp := range f.Imports {
+			if imp.File != "" {
+				t.importPaths[imp.Path] = imp.File
+			}
+		}
+		for _, s := range f.Symbols {
+			t.symbols[s.Name] = append(t.symbols[s.Name], s)
+		}
+		for _, p := range packagePrefixes(f.Package) {
+			t.packages[p] = append(t.packages[p], f.Path)
+		}
+	}
+	return t
+}
+
+func packagePrefixes(pkg string) []string {
+	if pkg == "" {
+		return nil
+	}
+	var prefixes []string
+	parts := strings.Split(pkg, ".")
+	for i := range parts {
+		prefixes = append(prefixes, strings.Join(parts[:i+1], "."))
+	}
+	return prefixes
+}
+
+// WithProto returns the table whose file of the proto is replaced with the given version,
+// which a fixing rule may have modified since the table was built.
+// The new imports are resolved only to the files already in the table.
+func (t *Table) WithProto(proto *parser.Proto) *Table {
+	filename := proto.Meta.Filename
+	path, ok := t.displayPaths[filename]
+	if !ok {
+		path = filename
+	}
+	importPath := filename
+	old, ok := t.files[path]
+	if ok {
+		importPath = old.ImportPath
+	}
+	f := NewFile(proto, path, importPath, func(importPath string) string {
+		if p, ok := t.importPaths[importPath]; ok {
+			return p
+		}
+		if wk, ok := WellKnownFile(importPath); ok {
+			return wk.Path
+		}
+		return ""
+	})
+	// Most rules aren't fixing, so the proto is usually the same as the one the table was built from.
+	if old != nil && reflect.DeepEqual(old, f) {
+		return t
+	}
+
+	files := make([]*File, 0, len(t.files)+1)
+	for p, other := range t.files {
+		if p != path {
+			files = append(files, other)
+		}
+	}
+	files = append(files, f)
+	for _, imp := range f.Imports {
+		if _, ok := t.files[imp.File]; !ok {
+			if wk, ok := WellKnownFile(imp.Path); ok {
+				files = append(files, wk)
+			}
+		}
+	}
+
+	displayPaths := make(map[string]string, len(t.displayPaths)+1)
+	for k, v := range t.displayPaths {
+		displayPaths[k] = v
+	}
+	displayPaths[filename] = path
+	return NewTable(files, displayPaths)
+}
+
+// Files returns all files sorted by their paths.
+func (t *Table) Files() []*File {
+	var files []*File
+	for _, f := range t.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// File returns the file at the path.
+func (t *Table) File(path string) (*File, bool) {
+	f, ok := t.files[path]
+	return f, ok
+}
+
+// FileOf returns the file of the parsed proto.
+func (t *Table) FileOf(proto *parser.Proto) (*File, bool) {
+	path, ok := t.displayPaths[proto.Meta.Filename]
+	if !ok {
+		path = proto.Meta.Filename
+	}
+	return t.File(path)
+}
+
+// Lookup returns all definitions of the fully-qualified name.
+// More than one definition means the duplicates among the files.
+func (t *Table) Lookup(name string) []Symbol {
+	return t.symbols[strings.TrimPrefix(name, ".")]
+}
+
+// Resolve finds the symbol which the type name refers to in the scope, like protoc does.
+//
+// The first component of the name is searched from the innermost scope to the outermost,
+// and the rest of the name must be found in the first scope which has the component.
+// The visibility by the imports isn't considered. Use IsVisible to check it.
+func (t *Table) Resolve(scope string, name string) (Symbol, bool) {
+	if strings.HasPrefix(name, ".") {
+		return t.lookupOne(name[1:])
+	}
+
+	first := name
+	if i := strings.Index(name, "."); 0 <= i {
+		first = name[:i]
+	}
+	for s := scope; ; s = parentScope(s) {
+		if t.exists(join(s, first)) {
+			return t.lookupOne(join(s, name))
+		}
+		if s == "" {
+			return Symbol{}, false
+		}
+	}
+}
+
+func (t *Table) lookupOne(name string) (Symbol, bool) {
+	symbols := t.symbols[name]
+	if len(symbols) == 0 {
+		return Symbol{}, false
+	}
+	return symbols[0], true
+}
+
+func (t *Table) exists(name string) bool {
+	return 0 < len(t.symbols[name]) || 0 < len(t.packages[name])
+}
+
+func parentScope(scope string) string {
+	if i := strings.LastIndex(scope, "."); 0 <= i {
+		return scope[:i]
+	}
+	return ""
+}
+
+// VisibleFiles returns the paths of the files whose symbols the file can refer to:
+// the file itself, the imported ones and the ones publicly imported by them recursively.
+func (t *Table) VisibleFiles(f *File) map[string]bool {
+	visible := map[string]bool{f.Path: true}
+	var visitPublic func(path string)
+	visitPublic = func(path string) {
+		imported, ok := t.files[path]
+		if !ok {
+			return
+		}
+		for _, imp := range imported.Imports {
+			if imp.IsPublic && imp.File != "" && !visible[imp.File] {
+				visible[imp.File] = true
+				visitPublic(imp.File)
+			}
+		}
+	}
+	for _, imp := range f.Imports {
+		if imp.File != "" && !visible[imp.File] {
+			visible[imp.File] = true
+			visitPublic(imp.File)
+		}
+	}
+	return visible
+}
+
+// IsVisible reports whether the file can refer to the symbol.
+func (t *Table) IsVisible(f *File, s Symbol) bool {
+	return t.VisibleFiles(f)[s.File]
+}
+
+// Digest returns the hash of the table, which changes when any file changes the symbols, imports or references.
+func (t *Table) Digest() string {
+	h := sha256.New()
+	for _, f := range t.Files() {
+		_, _ = fmt.Fprintf(h, "file %s %s %s\n", f.Path, f.ImportPath, f.Package)
+		for _, name := range sortedKeys(f.Options) {
+			_, _ = fmt.Fprintf(h, "option %s %s\n", name, f.Options[name])
+		}
+		for _, imp := range f.Imports {
+			_, _ = fmt.Fprintf(h, "import %s %t %t %s %v\n", imp.Path, imp.IsPublic, imp.IsWeak, imp.File, imp.Pos)
+		}
+		for _, s := range f.Symbols {
+			_, _ = fmt.Fprintf(h, "symbol %s %s %v\n", s.Name, s.Kind, s.Pos)
+		}
+		for _, r := range f.References {
+			_, _ = fmt.Fprintf(h, "reference %s %s %t %v\n", r.Name, r.Scope, r.IsOption, r.Pos)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
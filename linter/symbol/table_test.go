@@ -0,0 +1,186 @@
+package symbol_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	protoparser "github.com/yoheimuta/go-protoparser/v4"
+	"github.com/yoheimuta/go-protoparser/v4/parser"
+
+	"github.com/yoheimuta/protolint/internal/util_test"
+)
+
+func parse(t *testing.T, filename string, content string) *parser.Proto {
+	proto, err := protoparser.Parse(strings.NewReader(content), protoparser.WithFilename(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proto
+}
+
+var testFiles = map[string]string{
+	"a.proto": `syntax = "proto3";
+package foo.bar;
+import "b.proto";
+message A {
+  message Inner {}
+  Inner inner = 1;
+  foo.B b = 2;
+  C c = 3;
+}
+`,
+	"b.proto": `syntax = "proto3";
+package foo;
+import public "c.proto";
+message B {}
+message Dup {}
+`,
+	"c.proto": `syntax = "proto3";
+package foo.bar;
+message C {}
+enum Dup { DUP_UNSPECIFIED = 0; }
+`,
+	"d.proto": `syntax = "proto3";
+package bar;
+message D {}
+`,
+}
+
+func TestTable_Resolve(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+
+	tests := []struct {
+		name       string
+		inputScope string
+		inputName  string
+		wantName   string
+		wantFound  bool
+	}{
+		{
+			name:       "nested type",
+			inputScope: "foo.bar.A",
+			inputName:  "Inner",
+			wantName:   "foo.bar.A.Inner",
+			wantFound:  true,
+		},
+		{
+			name:       "type in the parent package",
+			inputScope: "foo.bar.A",
+			inputName:  "B",
+			wantName:   "foo.B",
+			wantFound:  true,
+		},
+		{
+			name:       "qualified type",
+			inputScope: "foo.bar.A",
+			inputName:  "foo.B",
+			wantName:   "foo.B",
+			wantFound:  true,
+		},
+		{
+			name:       "fully-qualified type",
+			inputScope: "foo.bar.A",
+			inputName:  ".bar.D",
+			wantName:   "bar.D",
+			wantFound:  true,
+		},
+		{
+			name:       "the first component found in the inner scope shadows the outer one",
+			inputScope: "foo.bar.A",
+			inputName:  "bar.D",
+			wantFound:  false,
+		},
+		{
+			name:       "unknown type",
+			inputScope: "foo.bar.A",
+			inputName:  "Unknown",
+			wantFound:  false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, found := table.Resolve(test.inputScope, test.inputName)
+			if found != test.wantFound {
+				t.Fatalf("got found %v, but want %v", found, test.wantFound)
+			}
+			if got.Name != test.wantName {
+				t.Errorf("got %s, but want %s", got.Name, test.wantName)
+			}
+		})
+	}
+}
+
+func TestTable_VisibleFiles(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+	a, _ := table.File("a.proto")
+
+	want := map[string]bool{
+		"a.proto": true,
+		"b.proto": true,
+		"c.proto": true,
+	}
+	if got := table.VisibleFiles(a); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+	d := table.Lookup("bar.D")[0]
+	if table.IsVisible(a, d) {
+		t.Errorf("got %v visible, but want invisible", d)
+	}
+}
+
+func TestTable_Lookup(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+
+	var got []string
+	for _, s := range table.Lookup("foo.bar.C") {
+		got = append(got, s.Kind.String()+" in "+s.File)
+	}
+	if want := []string{"message in c.proto"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, but want %v", got, want)
+	}
+
+	a, _ := table.File("a.proto")
+	var refs []string
+	for _, r := range a.References {
+		refs = append(refs, r.Scope+":"+r.Name)
+	}
+	if want := []string{"foo.bar.A:Inner", "foo.bar.A:foo.B", "foo.bar.A:C"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("got %v, but want %v", refs, want)
+	}
+}
+
+func TestTable_WithProto(t *testing.T) {
+	table := util_test.NewSymbolTable(t, testFiles)
+
+	same := table.WithProto(parse(t, "b.proto", testFiles["b.proto"]))
+	if same != table {
+		t.Errorf("got a new table, but want the same one for the unchanged proto")
+	}
+
+	changed := table.WithProto(parse(t, "b.proto", `syntax = "proto3";
+package foo;
+message B2 {}
+`))
+	if len(changed.Lookup("foo.B")) != 0 || len(changed.Lookup("foo.B2")) != 1 {
+		t.Errorf("got the symbols %v and %v, but want only foo.B2", changed.Lookup("foo.B"), changed.Lookup("foo.B2"))
+	}
+	if len(table.Lookup("foo.B")) != 1 {
+		t.Errorf("got the original table changed")
+	}
+	if changed.Digest() == table.Digest() {
+		t.Errorf("got the same digest, but want a different one")
+	}
+
+	wellKnown := table.WithProto(parse(t, "d.proto", `syntax = "proto3";
+package bar;
+import "google/protobuf/empty.proto";
+message D {}
+`))
+	d, _ := wellKnown.File("d.proto")
+	if !wellKnown.IsVisible(d, wellKnown.Lookup("google.protobuf.Empty")[0]) {
+		t.Errorf("got google.protobuf.Empty invisible, but want visible")
+	}
+}
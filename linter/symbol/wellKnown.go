@@ -0,0 +1,127 @@
+package symbol
+
+import (
+	"strings"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+)
+
+// wellKnownTypes lists the types of the files shipped with protoc, which are usually not in the include paths.
+var wellKnownTypes = map[string]struct {
+	pkg      string
+	messages []string
+	enums    []string
+}{
+	"google/protobuf/any.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Any"},
+	},
+	"google/protobuf/api.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Api", "Method", "Mixin"},
+	},
+	"google/protobuf/duration.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Duration"},
+	},
+	"google/protobuf/empty.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Empty"},
+	},
+	"google/protobuf/field_mask.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"FieldMask"},
+	},
+	"google/protobuf/source_context.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"SourceContext"},
+	},
+	"google/protobuf/struct.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Struct", "Value", "ListValue"},
+		enums:    []string{"NullValue"},
+	},
+	"google/protobuf/timestamp.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Timestamp"},
+	},
+	"google/protobuf/type.proto": {
+		pkg:      "google.protobuf",
+		messages: []string{"Type", "Field", "Enum", "EnumValue", "Option"},
+		enums:    []string{"Syntax", "Field.Kind", "Field.Cardinality"},
+	},
+	"google/protobuf/wrappers.proto": {
+		pkg: "google.protobuf",
+		messages: []string{
+			"DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value",
+			"UInt32Value", "BoolValue", "StringValue", "BytesValue",
+		},
+	},
+	"google/protobuf/descriptor.proto": {
+		pkg: "google.protobuf",
+		messages: []string{
+			"FileDescriptorSet", "FileDescriptorProto", "DescriptorProto",
+			"DescriptorProto.ExtensionRange", "DescriptorProto.ReservedRange",
+			"ExtensionRangeOptions", "FieldDescriptorProto", "OneofDescriptorProto",
+			"EnumDescriptorProto", "EnumDescriptorProto.EnumReservedRange", "EnumValueDescriptorProto",
+			"ServiceDescriptorProto", "MethodDescriptorProto", "FileOptions", "MessageOptions",
+			"FieldOptions", "OneofOptions", "EnumOptions", "EnumValueOptions", "ServiceOptions",
+			"MethodOptions", "UninterpretedOption", "UninterpretedOption.NamePart", "FeatureSet",
+			"FeatureSetDefaults", "SourceCodeInfo", "SourceCodeInfo.Location",
+			"GeneratedCodeInfo", "GeneratedCodeInfo.Annotation",
+		},
+		enums: []string{
+			"Edition", "FieldDescriptorProto.Type", "FieldDescriptorProto.Label",
+			"FileOptions.OptimizeMode", "FieldOptions.CType", "FieldOptions.JSType",
+			"FieldOptions.OptionRetention", "FieldOptions.OptionTargetType",
+			"MethodOptions.IdempotencyLevel",
+		},
+	},
+	"google/protobuf/compiler/plugin.proto": {
+		pkg:      "google.protobuf.compiler",
+		messages: []string{"Version", "CodeGeneratorRequest", "CodeGeneratorResponse", "CodeGeneratorResponse.File"},
+		enums:    []string{"CodeGeneratorResponse.Feature"},
+	},
+}
+
+// WellKnownFile returns the file of the well-known types at the import path.
+func WellKnownFile(importPath string) (*File, bool) {
+	wk, ok := wellKnownTypes[importPath]
+	if !ok {
+		return nil, false
+	}
+	f := &File{
+		Path:        importPath,
+		ImportPath:  importPath,
+		Package:     wk.pkg,
+		IsWellKnown: true,
+	}
+	for _, m := range wk.messages {
+		f.addSymbol(join(wk.pkg, m), KindMessage, meta.Position{Filename: importPath})
+	}
+	for _, e := range wk.enums {
+		f.addSymbol(join(wk.pkg, e), KindEnum, meta.Position{Filename: importPath})
+	}
+	return f, true
+}
+
+// IsWellKnownImport reports whether the import path is one of the well-known types shipped with protoc.
+func IsWellKnownImport(importPath string) bool {
+	_, ok := wellKnownTypes[strings.TrimPrefix(importPath, "./")]
+	return ok
+}
+
+// LookupWellKnown returns the well-known type of the fully-qualified name,
+// which is found even when no file in the table imports it.
+func LookupWellKnown(name string) (Symbol, bool) {
+	name = strings.TrimPrefix(name, ".")
+	for importPath := range wellKnownTypes {
+		wk, _ := WellKnownFile(importPath)
+		for _, s := range wk.Symbols {
+			if s.Name == name {
+				return s, true
+			}
+		}
+	}
+	return Symbol{}, false
+}
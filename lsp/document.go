@@ -0,0 +1,153 @@
+package lsp
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/linter/report"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// document represents an opened document, whose content is kept in memory.
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    string
+
+	// failures are the lint results of the current text.
+	failures []report.Failure
+	// fixes and disables are the failures with the edits to fix or disable them.
+	// They are computed on demand because only code actions need them.
+	fixes     []report.Failure
+	disables  []report.Failure
+	suggested bool
+}
+
+// uriToPath converts the file URI to the file path.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme %q in %s", u.Scheme, uri)
+	}
+	path := u.Path
+	// The path on Windows looks like /C:/path/to/file.proto.
+	if runtime.GOOS == "windows" {
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path), nil
+}
+
+// line returns the text of the one-based line without the line ending.
+func (d *document) line(line int) string {
+	lines := strings.SplitN(d.text, "\n", line+1)
+	if line < 1 || len(lines) < line {
+		return ""
+	}
+	return strings.TrimSuffix(lines[line-1], "\r")
+}
+
+// position converts the one-based line and the one-based column in characters
+// to the LSP position, whose character is counted in UTF-16 code units.
+func (d *document) position(pos meta.Position) Position {
+	character := 0
+	for i, r := range []rune(d.line(pos.Line)) {
+		if pos.Column-1 <= i {
+			break
+		}
+		character += len(utf16.Encode([]rune{r}))
+	}
+	line := pos.Line - 1
+	if line < 0 {
+		line = 0
+	}
+	return Position{Line: line, Character: character}
+}
+
+// failureRange returns the range of the word at the position of the failure,
+// or the range of the character there if it's not a word.
+func (d *document) failureRange(pos meta.Position) Range {
+	start := d.position(pos)
+
+	runes := []rune(d.line(pos.Line))
+	column := pos.Column
+	for column <= len(runes) && isWordRune(runes[column-1]) {
+		column++
+	}
+	if column == pos.Column && column <= len(runes) {
+		column++
+	}
+	end := d.position(meta.Position{Line: pos.Line, Column: column})
+	return Range{Start: start, End: end}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// diagnostic converts the failure to the diagnostic.
+func (d *document) diagnostic(failure report.Failure) Diagnostic {
+	severity := severityError
+	switch rule.Severity(failure.Severity()) {
+	case rule.SeverityWarning:
+		severity = severityWarning
+	case rule.SeverityNote:
+		severity = severityInformation
+	}
+	return Diagnostic{
+		Range:    d.failureRange(failure.Pos()),
+		Severity: severity,
+		Code:     failure.RuleID(),
+		Source:   "protolint",
+		Message:  failure.Message(),
+	}
+}
+
+// textEdits converts the suggested edits to the text edits.
+func (d *document) textEdits(edits []report.SuggestedEdit) []TextEdit {
+	es := []TextEdit{}
+	for _, e := range edits {
+		es = append(es, TextEdit{
+			Range: Range{
+				Start: d.position(e.Pos),
+				End:   d.position(e.End),
+			},
+			NewText: e.NewText,
+		})
+	}
+	return es
+}
+
+// workspaceEdit wraps the edits to the document.
+func (d *document) workspaceEdit(edits []TextEdit) WorkspaceEdit {
+	return WorkspaceEdit{
+		Changes: map[string][]TextEdit{
+			d.uri: edits,
+		},
+	}
+}
+
+// disableNextEdit inserts the disable:next comment just above the line of the failure.
+func (d *document) disableNextEdit(failure report.Failure) TextEdit {
+	line := d.line(failure.Pos().Line)
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	newline := "\n"
+	if strings.Contains(d.text, "\r\n") {
+		newline = "\r\n"
+	}
+	start := Position{Line: failure.Pos().Line - 1}
+	return TextEdit{
+		Range:   Range{Start: start, End: start},
+		NewText: fmt.Sprintf("%s// protolint:disable:next %s%s", indent, failure.RuleID(), newline),
+	}
+}
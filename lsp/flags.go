@@ -0,0 +1,57 @@
+package lsp
+
+import (
+	"flag"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+)
+
+// Flags represents a set of lsp flag parameters.
+type Flags struct {
+	*flag.FlagSet
+
+	ConfigPath    string
+	ConfigDirPath string
+	Plugins       []shared.RuleSet
+
+	// Version is the protolint version given by the caller, not by the command line.
+	Version string
+}
+
+// NewFlags creates a new Flags.
+func NewFlags(
+	args []string,
+) (Flags, error) {
+	f := Flags{
+		FlagSet: flag.NewFlagSet("lsp", flag.ExitOnError),
+	}
+	var pf subcmds.PluginFlag
+
+	f.StringVar(
+		&f.ConfigPath,
+		"config_path",
+		"",
+		"path/to/protolint.yaml. Note that if both are set, config_dir_path is ignored.",
+	)
+	f.StringVar(
+		&f.ConfigDirPath,
+		"config_dir_path",
+		"",
+		"path/to/the_directory_including_protolint.yaml",
+	)
+	f.Var(
+		&pf,
+		"plugin",
+		`plugins to provide custom lint rule set. Note that it's necessary to specify it as path format'. The path ending with .wasm is run as a WASI module`,
+	)
+
+	_ = f.Parse(args)
+
+	plugins, err := pf.BuildPlugins(false)
+	if err != nil {
+		return Flags{}, err
+	}
+	f.Plugins = plugins
+	return f, nil
+}
@@ -0,0 +1,223 @@
+// Package lsp implements the Language Server Protocol (LSP) server for protolint.
+package lsp
+
+import (
+	"encoding/json"
+)
+
+// Message represents a JSON-RPC 2.0 request, notification or response.
+// A notification has no ID, and a response has no method.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error represents a JSON-RPC 2.0 error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// The error codes defined by JSON-RPC 2.0 and LSP.
+const (
+	codeParseError     = -32700
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeInvalidRequest = -32600
+)
+
+// InitializeParams represents the parameters for initialize request
+type InitializeParams struct {
+	RootURI      string             `json:"rootUri,omitempty"`
+	Capabilities ClientCapabilities `json:"capabilities"`
+	ClientInfo   *ClientInfo        `json:"clientInfo,omitempty"`
+}
+
+// ClientInfo represents information about the client
+type ClientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// ClientCapabilities represents the client capabilities which the server cares about
+type ClientCapabilities struct {
+	Workspace struct {
+		DidChangeWatchedFiles struct {
+			DynamicRegistration bool `json:"dynamicRegistration"`
+		} `json:"didChangeWatchedFiles"`
+	} `json:"workspace"`
+}
+
+// InitializeResult represents the response for initialize request
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   ServerInfo         `json:"serverInfo"`
+}
+
+// ServerInfo represents information about the server
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ServerCapabilities represents the server's capabilities
+type ServerCapabilities struct {
+	TextDocumentSync   TextDocumentSyncOptions `json:"textDocumentSync"`
+	CodeActionProvider CodeActionOptions       `json:"codeActionProvider"`
+}
+
+// TextDocumentSyncOptions represents how the client syncs the documents
+type TextDocumentSyncOptions struct {
+	OpenClose bool `json:"openClose"`
+	// Change is 1 to send the full content on every change.
+	Change int         `json:"change"`
+	Save   SaveOptions `json:"save"`
+}
+
+// SaveOptions represents the options for didSave notification
+type SaveOptions struct {
+	IncludeText bool `json:"includeText"`
+}
+
+// CodeActionOptions represents the supported code actions
+type CodeActionOptions struct {
+	CodeActionKinds []string `json:"codeActionKinds"`
+}
+
+// textDocumentSyncFull makes the client send the full content on every change.
+const textDocumentSyncFull = 1
+
+// codeActionKindQuickFix is the kind of the code actions the server provides.
+const codeActionKindQuickFix = "quickfix"
+
+// TextDocumentItem represents an opened document
+type TextDocumentItem struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+// TextDocumentIdentifier identifies a document
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// DidOpenTextDocumentParams represents the parameters for textDocument/didOpen
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// DidChangeTextDocumentParams represents the parameters for textDocument/didChange
+type DidChangeTextDocumentParams struct {
+	TextDocument struct {
+		URI     string `json:"uri"`
+		Version int    `json:"version"`
+	} `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+// DidCloseTextDocumentParams represents the parameters for textDocument/didClose
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DidSaveTextDocumentParams represents the parameters for textDocument/didSave
+type DidSaveTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DidChangeWatchedFilesParams represents the parameters for workspace/didChangeWatchedFiles
+type DidChangeWatchedFilesParams struct {
+	Changes []struct {
+		URI string `json:"uri"`
+	} `json:"changes"`
+}
+
+// Position is a zero-based line and a zero-based UTF-16 character offset in the line.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range in a document, whose end is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// The severities of Diagnostic.
+const (
+	severityError       = 1
+	severityWarning     = 2
+	severityInformation = 3
+)
+
+// Diagnostic represents a lint failure
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// PublishDiagnosticsParams represents the parameters for textDocument/publishDiagnostics
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// CodeActionParams represents the parameters for textDocument/codeAction
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+// TextEdit represents a replacement of the range with the new text
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// WorkspaceEdit represents the edits to the documents keyed by their URIs
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+// CodeAction represents a fix or a disable comment for a diagnostic
+type CodeAction struct {
+	Title       string        `json:"title"`
+	Kind        string        `json:"kind"`
+	Diagnostics []Diagnostic  `json:"diagnostics"`
+	IsPreferred bool          `json:"isPreferred,omitempty"`
+	Edit        WorkspaceEdit `json:"edit"`
+}
+
+// RegistrationParams represents the parameters for client/registerCapability
+type RegistrationParams struct {
+	Registrations []Registration `json:"registrations"`
+}
+
+// Registration represents a capability to register dynamically
+type Registration struct {
+	ID              string      `json:"id"`
+	Method          string      `json:"method"`
+	RegisterOptions interface{} `json:"registerOptions,omitempty"`
+}
+
+// FileSystemWatcher represents a glob pattern of the files to watch
+type FileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+}
+
+// DidChangeWatchedFilesRegistrationOptions represents the files to watch
+type DidChangeWatchedFilesRegistrationOptions struct {
+	Watchers []FileSystemWatcher `json:"watchers"`
+}
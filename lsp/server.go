@@ -0,0 +1,696 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/yoheimuta/go-protoparser/v4/parser/meta"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
+	"github.com/yoheimuta/protolint/internal/cmd/subcmds"
+	"github.com/yoheimuta/protolint/internal/linter"
+	"github.com/yoheimuta/protolint/internal/linter/config"
+	"github.com/yoheimuta/protolint/internal/linter/file"
+	internalrule "github.com/yoheimuta/protolint/internal/linter/rule"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/autodisable"
+)
+
+// Server represents an LSP server
+type Server struct {
+	flags  Flags
+	in     *bufio.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	l      *linter.Linter
+	config *config.ExternalConfig
+	// extendedFiles are the absolute paths to the config files which the config extends.
+	extendedFiles  map[string]bool
+	allRules       internalrule.Rules
+	fixingRules    internalrule.Rules
+	disablingRules internalrule.Rules
+	// wasmPlugins are the plugins in the config which allRules run on.
+	wasmPlugins []shared.RuleSet
+
+	documents map[string]*document
+	watchable bool
+	shutdown  bool
+	nextID    int
+}
+
+// NewServer creates a new LSP server
+func NewServer(
+	flags Flags,
+	stdin io.Reader,
+	stdout io.Writer,
+	stderr io.Writer,
+) *Server {
+	return &Server{
+		flags:     flags,
+		in:        bufio.NewReader(stdin),
+		stdout:    stdout,
+		stderr:    stderr,
+		l:         linter.NewLinter(),
+		documents: make(map[string]*document),
+	}
+}
+
+// Run starts the LSP server
+func (s *Server) Run() osutil.ExitCode {
+	defer plugin.CleanupClients()
+	defer subcmds.ClosePlugins(s.flags.Plugins)
+	defer func() {
+		subcmds.ClosePlugins(s.wasmPlugins)
+	}()
+	_, _ = fmt.Fprintf(s.stderr, "protolint LSP server is running. cwd: %s\n", getCurrentDir())
+
+	for {
+		body, err := s.read()
+		if err != nil {
+			if err == io.EOF {
+				// Normal termination
+				return osutil.ExitSuccess
+			}
+			_, _ = fmt.Fprintf(s.stderr, "Error reading message: %v\n", err)
+			return osutil.ExitInternalFailure
+		}
+
+		var msg Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.replyError(nil, codeParseError, fmt.Sprintf("Invalid message: %v", err))
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return osutil.ExitSuccess
+			}
+			return osutil.ExitInternalFailure
+		}
+		s.handleMessage(&msg)
+	}
+}
+
+// read reads the content of a message framed by the Content-Length header.
+func (s *Server) read() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && 0 < len(line) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if length < 0 {
+				return nil, fmt.Errorf("missing Content-Length header")
+			}
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header: %v", err)
+			}
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
+// write writes the message framed by the Content-Length header.
+func (s *Server) write(msg Message) {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error encoding message: %v\n", err)
+		return
+	}
+	if _, err := fmt.Fprintf(s.stdout, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error writing message: %v\n", err)
+	}
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.replyError(id, codeInvalidRequest, fmt.Sprintf("Failed to marshal result: %v", err))
+		return
+	}
+	s.write(Message{ID: id, Result: data})
+}
+
+func (s *Server) replyError(id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(Message{ID: id, Error: &Error{Code: code, Message: message}})
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error encoding %s: %v\n", method, err)
+		return
+	}
+	s.write(Message{Method: method, Params: data})
+}
+
+// showError shows the error in the client as well as the log.
+func (s *Server) showError(err error) {
+	_, _ = fmt.Fprintf(s.stderr, "Error: %v\n", err)
+	s.notify("window/showMessage", map[string]interface{}{
+		"type":    1, // Error
+		"message": fmt.Sprintf("protolint: %v", err),
+	})
+}
+
+// handleMessage handles a single JSON-RPC message
+func (s *Server) handleMessage(msg *Message) {
+	switch {
+	case msg.Method == "":
+		// A response to the server's request. The server doesn't wait for any of them.
+		if msg.Error != nil {
+			_, _ = fmt.Fprintf(s.stderr, "Received error response: %s\n", msg.Error.Message)
+		}
+	case msg.ID == nil:
+		s.handleNotification(msg)
+	default:
+		result, rpcErr := s.handleRequest(msg)
+		if rpcErr != nil {
+			s.replyError(msg.ID, rpcErr.Code, rpcErr.Message)
+			return
+		}
+		s.reply(msg.ID, result)
+	}
+}
+
+// handleRequest handles a request, which requires a response
+func (s *Server) handleRequest(msg *Message) (interface{}, *Error) {
+	switch msg.Method {
+	case "initialize":
+		return s.handleInitialize(msg)
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/codeAction":
+		return s.handleCodeAction(msg)
+	default:
+		return nil, &Error{
+			Code:    codeMethodNotFound,
+			Message: fmt.Sprintf("Method not found: %s", msg.Method),
+		}
+	}
+}
+
+// handleNotification handles a notification, which doesn't require a response
+func (s *Server) handleNotification(msg *Message) {
+	var err error
+	switch msg.Method {
+	case "initialized":
+		s.registerWatchers()
+	case "textDocument/didOpen":
+		err = s.handleDidOpen(msg)
+	case "textDocument/didChange":
+		err = s.handleDidChange(msg)
+	case "textDocument/didClose":
+		err = s.handleDidClose(msg)
+	case "textDocument/didSave":
+		err = s.handleDidSave(msg)
+	case "workspace/didChangeWatchedFiles":
+		err = s.handleDidChangeWatchedFiles(msg)
+	default:
+		// Ignore the other notifications like $/cancelRequest
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error handling %s: %v\n", msg.Method, err)
+	}
+}
+
+// handleInitialize handles initialization requests according to the LSP
+func (s *Server) handleInitialize(msg *Message) (interface{}, *Error) {
+	var params InitializeParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil, &Error{
+			Code:    codeInvalidParams,
+			Message: fmt.Sprintf("Failed to parse initialize params: %v", err),
+		}
+	}
+	if params.ClientInfo != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Client info: %s %s\n", params.ClientInfo.Name, params.ClientInfo.Version)
+	}
+	s.watchable = params.Capabilities.Workspace.DidChangeWatchedFiles.DynamicRegistration
+
+	// Work in the workspace root like the lint command in the project root,
+	// so that the config is found and the ignored paths in it are matched in the same way.
+	if len(params.RootURI) != 0 {
+		if err := s.moveToRoot(params.RootURI); err != nil {
+			s.showError(err)
+		}
+	}
+
+	s.reloadConfig()
+
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+			},
+			CodeActionProvider: CodeActionOptions{
+				CodeActionKinds: []string{codeActionKindQuickFix},
+			},
+		},
+		ServerInfo: ServerInfo{
+			Name:    "protolint-lsp",
+			Version: s.flags.Version,
+		},
+	}, nil
+}
+
+// moveToRoot changes the working directory to the root, keeping the paths in the flags valid.
+func (s *Server) moveToRoot(rootURI string) error {
+	root, err := uriToPath(rootURI)
+	if err != nil {
+		return err
+	}
+	for _, p := range []*string{&s.flags.ConfigPath, &s.flags.ConfigDirPath} {
+		if len(*p) == 0 {
+			continue
+		}
+		abs, err := filepath.Abs(*p)
+		if err != nil {
+			return err
+		}
+		*p = abs
+	}
+	return os.Chdir(root)
+}
+
+// registerWatchers asks the client to notify the server of the changes of the config files.
+func (s *Server) registerWatchers() {
+	if !s.watchable {
+		return
+	}
+	// Any YAML file can be extended by the config.
+	watchers := []FileSystemWatcher{
+		{GlobPattern: "**/*.{yaml,yml}"},
+		{GlobPattern: "**/package.json"},
+		{GlobPattern: "**/pyproject.toml"},
+	}
+	if len(s.flags.ConfigPath) != 0 {
+		watchers = append(watchers, FileSystemWatcher{GlobPattern: filepath.ToSlash(s.flags.ConfigPath)})
+	}
+
+	params, err := json.Marshal(RegistrationParams{
+		Registrations: []Registration{
+			{
+				ID:     "protolint-config",
+				Method: "workspace/didChangeWatchedFiles",
+				RegisterOptions: DidChangeWatchedFilesRegistrationOptions{
+					Watchers: watchers,
+				},
+			},
+		},
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error encoding client/registerCapability: %v\n", err)
+		return
+	}
+
+	s.nextID++
+	s.write(Message{
+		ID:     json.RawMessage(strconv.Itoa(s.nextID)),
+		Method: "client/registerCapability",
+		Params: params,
+	})
+}
+
+// reloadConfig loads the config and rebuilds the rules.
+// The previous ones are kept on failure.
+func (s *Server) reloadConfig() {
+	err := s.loadConfig()
+	if err == nil {
+		return
+	}
+	s.showError(err)
+	if s.config != nil {
+		return
+	}
+	// Fall back on the default config to keep linting.
+	if err := s.applyConfig(&config.ExternalConfig{}); err != nil {
+		s.showError(err)
+	}
+}
+
+func (s *Server) loadConfig() error {
+	externalConfig, err := config.GetExternalConfig(s.flags.ConfigPath, s.flags.ConfigDirPath)
+	if err != nil {
+		return err
+	}
+	extendedFiles := make(map[string]bool)
+	if externalConfig == nil {
+		externalConfig = &config.ExternalConfig{}
+	} else {
+		_, _ = fmt.Fprintf(s.stderr, "protolint loads a config file at %s\n", externalConfig.SourcePath)
+
+		files, err := config.ExtendedFiles(externalConfig.SourcePath)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				return err
+			}
+			extendedFiles[abs] = true
+		}
+	}
+	if err := s.applyConfig(externalConfig); err != nil {
+		return err
+	}
+	s.extendedFiles = extendedFiles
+	return nil
+}
+
+func (s *Server) applyConfig(externalConfig *config.ExternalConfig) error {
+	option := externalConfig.Lint.RulesOption
+	customRules := externalConfig.Lint.CustomRules
+	pluginRules := externalConfig.Lint.Plugins
+	wasmPlugins, err := subcmds.BuildWasmPlugins(externalConfig.WasmPlugins(), false)
+	if err != nil {
+		return err
+	}
+	plugins := append(append([]shared.RuleSet(nil), s.flags.Plugins...), wasmPlugins...)
+	allRules, err := subcmds.NewAllRules(option, customRules, pluginRules, false, autodisable.Noop, false, plugins)
+	if err != nil {
+		subcmds.ClosePlugins(wasmPlugins)
+		return err
+	}
+	// The plugins are left out because they share the server with the rules in lint mode.
+	fixingRules, err := subcmds.NewAllRules(option, customRules, pluginRules, true, autodisable.Noop, false, nil)
+	if err != nil {
+		subcmds.ClosePlugins(wasmPlugins)
+		return err
+	}
+	disablingRules, err := subcmds.NewAllRules(option, customRules, pluginRules, false, autodisable.Next, false, nil)
+	if err != nil {
+		subcmds.ClosePlugins(wasmPlugins)
+		return err
+	}
+
+	subcmds.ClosePlugins(s.wasmPlugins)
+	s.config = externalConfig
+	s.allRules = allRules
+	s.fixingRules = fixingRules
+	s.disablingRules = disablingRules
+	s.wasmPlugins = wasmPlugins
+	return nil
+}
+
+func (s *Server) handleDidOpen(msg *Message) error {
+	var params DidOpenTextDocumentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return err
+	}
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	doc := &document{
+		uri:     params.TextDocument.URI,
+		path:    path,
+		version: params.TextDocument.Version,
+		text:    params.TextDocument.Text,
+	}
+	s.documents[doc.uri] = doc
+	s.publishDiagnostics(doc)
+	return nil
+}
+
+func (s *Server) handleDidChange(msg *Message) error {
+	var params DidChangeTextDocumentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return err
+	}
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return fmt.Errorf("not found the opened document %s", params.TextDocument.URI)
+	}
+	if len(params.ContentChanges) == 0 {
+		return nil
+	}
+
+	// The full content is sent as the last change because of textDocumentSyncFull.
+	doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+	doc.version = params.TextDocument.Version
+	s.publishDiagnostics(doc)
+	return nil
+}
+
+func (s *Server) handleDidClose(msg *Message) error {
+	var params DidCloseTextDocumentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return err
+	}
+	delete(s.documents, params.TextDocument.URI)
+	s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         params.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+	return nil
+}
+
+func (s *Server) handleDidSave(msg *Message) error {
+	var params DidSaveTextDocumentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return err
+	}
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if s.isConfigFile(path) {
+		s.reloadAll()
+	}
+	return nil
+}
+
+func (s *Server) handleDidChangeWatchedFiles(msg *Message) error {
+	var params DidChangeWatchedFilesParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return err
+	}
+	for _, change := range params.Changes {
+		path, err := uriToPath(change.URI)
+		if err != nil {
+			return err
+		}
+		if s.isConfigFile(path) {
+			s.reloadAll()
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *Server) isConfigFile(path string) bool {
+	if s.extendedFiles[path] {
+		return true
+	}
+	if len(s.flags.ConfigPath) != 0 {
+		configPath, err := filepath.Abs(s.flags.ConfigPath)
+		return err == nil && configPath == path
+	}
+	return config.IsExternalConfigFile(path)
+}
+
+// reloadAll reloads the config and lints all the opened documents again.
+func (s *Server) reloadAll() {
+	s.reloadConfig()
+	for _, doc := range s.documents {
+		s.publishDiagnostics(doc)
+	}
+}
+
+func (s *Server) publishDiagnostics(doc *document) {
+	diagnostics, err := s.lint(doc)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error linting %s: %v\n", doc.path, err)
+	}
+	version := doc.version
+	s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// lint lints the in-memory content of the document.
+// A parse error is reported as a diagnostic too.
+func (s *Server) lint(doc *document) ([]Diagnostic, error) {
+	doc.failures = nil
+	doc.fixes = nil
+	doc.disables = nil
+	doc.suggested = false
+
+	// The document can't be linted before initialize.
+	if s.config == nil {
+		return []Diagnostic{}, nil
+	}
+
+	f, err := file.NewProtoFileFromPath(doc.path)
+	if err != nil {
+		return []Diagnostic{}, err
+	}
+	rs := s.config.SelectRules(f.DisplayPath(), s.allRules)
+	if len(rs) == 0 {
+		return []Diagnostic{}, nil
+	}
+
+	osutil.SetOverlay(f.Path(), []byte(doc.text))
+	defer osutil.RemoveOverlay(f.Path())
+
+	_, failures, err := s.l.RunFile(f, rs, false)
+	if err != nil {
+		if parseErr, ok := err.(linter.ParseError); ok {
+			return []Diagnostic{doc.parseErrorDiagnostic(parseErr)}, nil
+		}
+		return []Diagnostic{}, err
+	}
+	doc.failures = failures
+
+	diagnostics := []Diagnostic{}
+	for _, failure := range failures {
+		diagnostics = append(diagnostics, doc.diagnostic(failure))
+	}
+	return diagnostics, nil
+}
+
+// parseErrorPos matches the position in the message of the parse error like "Pos=a.proto:2:6".
+var parseErrorPos = regexp.MustCompile(`Pos=[^)]*:(\d+):(\d+)\)`)
+
+// parseErrorDiagnostic reports the parse error at the position in the message,
+// or at the start of the document if not found.
+func (d *document) parseErrorDiagnostic(err linter.ParseError) Diagnostic {
+	var r Range
+	if m := parseErrorPos.FindStringSubmatch(err.Message); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		column, _ := strconv.Atoi(m[2])
+		r = d.failureRange(meta.Position{Line: line, Column: column})
+	}
+	return Diagnostic{
+		Range:    r,
+		Severity: severityError,
+		Source:   "protolint",
+		Message:  strings.TrimSuffix(err.Message, ". Use -v for more details"),
+	}
+}
+
+// suggest computes the edits to fix and to disable the failures of the document.
+func (s *Server) suggest(doc *document) error {
+	if doc.suggested {
+		return nil
+	}
+
+	f, err := file.NewProtoFileFromPath(doc.path)
+	if err != nil {
+		return err
+	}
+	osutil.SetOverlay(f.Path(), []byte(doc.text))
+	defer osutil.RemoveOverlay(f.Path())
+
+	fixes, err := s.l.SuggestFixes(f, s.config.SelectRules(f.DisplayPath(), s.fixingRules), doc.failures, false)
+	if err != nil {
+		return err
+	}
+	disables, err := s.l.SuggestFixes(f, s.config.SelectRules(f.DisplayPath(), s.disablingRules), doc.failures, false)
+	if err != nil {
+		return err
+	}
+
+	doc.fixes = fixes
+	doc.disables = disables
+	doc.suggested = true
+	return nil
+}
+
+func (s *Server) handleCodeAction(msg *Message) (interface{}, *Error) {
+	var params CodeActionParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil, &Error{
+			Code:    codeInvalidParams,
+			Message: fmt.Sprintf("Failed to parse codeAction params: %v", err),
+		}
+	}
+
+	actions := []CodeAction{}
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return actions, nil
+	}
+	if err := s.suggest(doc); err != nil {
+		_, _ = fmt.Fprintf(s.stderr, "Error suggesting fixes for %s: %v\n", doc.path, err)
+		return actions, nil
+	}
+
+	for i, failure := range doc.failures {
+		diagnostic := doc.diagnostic(failure)
+		if diagnostic.Range.End.Line < params.Range.Start.Line || params.Range.End.Line < diagnostic.Range.Start.Line {
+			continue
+		}
+
+		if edits := doc.fixes[i].SuggestedEdits(); 0 < len(edits) {
+			actions = append(actions, CodeAction{
+				Title:       fmt.Sprintf("Fix %s", failure.RuleID()),
+				Kind:        codeActionKindQuickFix,
+				Diagnostics: []Diagnostic{diagnostic},
+				IsPreferred: true,
+				Edit:        doc.workspaceEdit(doc.textEdits(edits)),
+			})
+		}
+
+		// Fall back on the comment above the line for the rules which don't support auto_disable.
+		disableEdits := doc.textEdits(doc.disables[i].SuggestedEdits())
+		if len(disableEdits) == 0 {
+			disableEdits = []TextEdit{doc.disableNextEdit(failure)}
+		}
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Disable %s for this line", failure.RuleID()),
+			Kind:        codeActionKindQuickFix,
+			Diagnostics: []Diagnostic{diagnostic},
+			Edit:        doc.workspaceEdit(disableEdits),
+		})
+	}
+	return actions, nil
+}
+
+// getCurrentDir returns the current working directory
+func getCurrentDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "<unknown>"
+	}
+	return dir
+}
@@ -0,0 +1,287 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yoheimuta/protolint/internal/osutil"
+)
+
+const testContent = `syntax = "proto3";
+
+enum enumName {
+    ENUM_NAME_UNSPECIFIED = 0;
+}
+`
+
+func frame(t *testing.T, msgs ...map[string]interface{}) io.Reader {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		msg["jsonrpc"] = "2.0"
+		body, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	return &buf
+}
+
+func readAll(t *testing.T, out []byte) []Message {
+	var msgs []Message
+	s := NewServer(Flags{}, bytes.NewReader(out), io.Discard, io.Discard)
+	for {
+		body, err := s.read()
+		if err == io.EOF {
+			return msgs
+		}
+		if err != nil {
+			t.Fatalf("got err %v, but want nil", err)
+		}
+		var msg Message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func diagnosticCodes(t *testing.T, msgs []Message) [][]string {
+	var codes [][]string
+	for _, msg := range msgs {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		cs := []string{}
+		for _, d := range params.Diagnostics {
+			cs = append(cs, d.Code)
+		}
+		codes = append(codes, cs)
+	}
+	return codes
+}
+
+func newWorkspace(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	return "file://" + filepath.ToSlash(dir), "file://" + filepath.ToSlash(filepath.Join(dir, "a.proto"))
+}
+
+func TestServer_Run(t *testing.T) {
+	rootURI, uri := newWorkspace(t)
+	in := frame(t,
+		map[string]interface{}{"id": 1, "method": "initialize", "params": map[string]interface{}{"rootUri": rootURI}},
+		map[string]interface{}{"method": "initialized", "params": map[string]interface{}{}},
+		map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "version": 1, "text": testContent},
+		}},
+		map[string]interface{}{"id": 2, "method": "textDocument/codeAction", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"range": Range{
+				Start: Position{Line: 3},
+				End:   Position{Line: 3},
+			},
+		}},
+		map[string]interface{}{"id": 3, "method": "shutdown"},
+		map[string]interface{}{"method": "exit"},
+	)
+	var out bytes.Buffer
+	got := NewServer(Flags{Version: "1.0.0(abc)"}, in, &out, io.Discard).Run()
+	if got != osutil.ExitSuccess {
+		t.Errorf("got exit code %v, but want %v", got, osutil.ExitSuccess)
+	}
+
+	msgs := readAll(t, out.Bytes())
+	var initialized InitializeResult
+	if err := json.Unmarshal(msgs[0].Result, &initialized); err != nil {
+		t.Fatal(err)
+	}
+	if initialized.ServerInfo.Version != "1.0.0(abc)" {
+		t.Errorf("got version %s, but want 1.0.0(abc)", initialized.ServerInfo.Version)
+	}
+	wantCodes := [][]string{{"INDENT", "ENUM_NAMES_UPPER_CAMEL_CASE"}}
+	if !reflect.DeepEqual(diagnosticCodes(t, msgs), wantCodes) {
+		t.Errorf("got %v, but want %v", diagnosticCodes(t, msgs), wantCodes)
+	}
+
+	var actions []CodeAction
+	for _, msg := range msgs {
+		if string(msg.ID) == "2" {
+			if err := json.Unmarshal(msg.Result, &actions); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	wantActions := []struct {
+		title string
+		edits []TextEdit
+	}{
+		{
+			title: "Fix INDENT",
+			edits: []TextEdit{{
+				Range:   Range{Start: Position{Line: 3, Character: 2}, End: Position{Line: 3, Character: 4}},
+				NewText: "",
+			}},
+		},
+		{
+			title: "Disable INDENT for this line",
+			edits: []TextEdit{{
+				Range:   Range{Start: Position{Line: 3}, End: Position{Line: 3}},
+				NewText: "    // protolint:disable:next INDENT\n",
+			}},
+		},
+	}
+	if len(actions) != len(wantActions) {
+		t.Fatalf("got %v, but want %v", actions, wantActions)
+	}
+	for i, want := range wantActions {
+		if actions[i].Title != want.title {
+			t.Errorf("got %s, but want %s", actions[i].Title, want.title)
+		}
+		if !reflect.DeepEqual(actions[i].Edit.Changes[uri], want.edits) {
+			t.Errorf("got %v, but want %v", actions[i].Edit.Changes[uri], want.edits)
+		}
+	}
+
+	if _, err := os.Stat("a.proto"); !os.IsNotExist(err) {
+		t.Errorf("got the document written, but want no file")
+	}
+}
+
+func TestServer_Run_ReloadConfig(t *testing.T) {
+	noIndent := "lint:\n  rules:\n    remove:\n      - INDENT\n"
+
+	for _, test := range []struct {
+		name string
+		// inputFiles are written before the server starts.
+		inputFiles map[string]string
+		// inputChangedFile is written with inputChangedContent while the server runs.
+		inputChangedFile    string
+		inputChangedContent string
+	}{
+		{
+			name:                "the config file is added",
+			inputChangedFile:    ".protolint.yaml",
+			inputChangedContent: noIndent,
+		},
+		{
+			name: "the config file extended is changed",
+			inputFiles: map[string]string{
+				".protolint.yaml":   "lint:\n  extends:\n    - presets/base.yaml\n",
+				"presets/base.yaml": "lint: {}\n",
+			},
+			inputChangedFile:    "presets/base.yaml",
+			inputChangedContent: noIndent,
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rootURI, uri := newWorkspace(t)
+			for name, content := range test.inputFiles {
+				if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			in, w := io.Pipe()
+			var out bytes.Buffer
+			done := make(chan osutil.ExitCode)
+			go func() {
+				done <- NewServer(Flags{}, in, &out, io.Discard).Run()
+			}()
+
+			send := func(msgs ...map[string]interface{}) {
+				if _, err := io.Copy(w, frame(t, msgs...)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			send(
+				map[string]interface{}{"id": 1, "method": "initialize", "params": map[string]interface{}{"rootUri": rootURI}},
+				map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+					"textDocument": map[string]interface{}{"uri": uri, "version": 1, "text": testContent},
+				}},
+			)
+
+			// The config is written after the server has read the previous messages.
+			send(map[string]interface{}{"id": 2, "method": "shutdown"})
+			err := os.WriteFile(test.inputChangedFile, []byte(test.inputChangedContent), 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			send(
+				map[string]interface{}{"method": "workspace/didChangeWatchedFiles", "params": map[string]interface{}{
+					"changes": []map[string]interface{}{{"uri": rootURI + "/" + test.inputChangedFile, "type": 1}},
+				}},
+				map[string]interface{}{"method": "exit"},
+			)
+			_ = w.Close()
+			<-done
+
+			want := [][]string{
+				{"INDENT", "ENUM_NAMES_UPPER_CAMEL_CASE"},
+				{"ENUM_NAMES_UPPER_CAMEL_CASE"},
+			}
+			if got := diagnosticCodes(t, readAll(t, out.Bytes())); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, but want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestServer_read(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "read the content",
+			input: "Content-Length: 2\r\n\r\n{}",
+			want:  "{}",
+		},
+		{
+			name:  "ignore the other headers",
+			input: "Content-Type: application/vscode-jsonrpc; charset=utf-8\r\ncontent-length: 2\r\n\r\n{}",
+			want:  "{}",
+		},
+		{
+			name:    "missing Content-Length",
+			input:   "Content-Type: application/vscode-jsonrpc\r\n\r\n{}",
+			wantErr: true,
+		},
+		{
+			name:    "short content",
+			input:   "Content-Length: 10\r\n\r\n{}",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer(Flags{}, bufio.NewReader(bytes.NewBufferString(test.input)), io.Discard, io.Discard)
+			got, err := s.read()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got err %v, but want err %v", err, test.wantErr)
+			}
+			if string(got) != test.want {
+				t.Errorf("got %s, but want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -82,6 +82,9 @@ func (t *LintFilesTool) Execute(args json.RawMessage) (any, error) {
 
 	if lintArgs.Fix {
 		cmdArgs = append(cmdArgs, "--fix")
+	} else {
+		// Let the client apply the fixes by itself
+		cmdArgs = append(cmdArgs, "--suggest_fixes")
 	}
 
 	// Add files at the end
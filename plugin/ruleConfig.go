@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// RuleConfig is the value of the rule in the plugins section of the config.
+type RuleConfig struct {
+	value *structpb.Value
+}
+
+// NewRuleConfig creates a new RuleConfig of the value like the one decoded from JSON, which is useful to test the rules.
+func NewRuleConfig(v interface{}) (RuleConfig, error) {
+	value, err := structpb.NewValue(v)
+	if err != nil {
+		return RuleConfig{}, err
+	}
+	return RuleConfig{value: value}, nil
+}
+
+// Value returns the value as the one decoded from JSON.
+func (c RuleConfig) Value() interface{} {
+	return c.value.AsInterface()
+}
+
+// Decode decodes the value into out in the same way as json.Unmarshal.
+func (c RuleConfig) Decode(out interface{}) error {
+	b, err := json.Marshal(c.Value())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// ConfigurableRule is a rule which accepts the config.
+// Configure is called with the config of the rule ID, and the returned rule replaces the original one.
+type ConfigurableRule interface {
+	rule.Rule
+	Configure(config RuleConfig) (rule.Rule, error)
+}
@@ -2,15 +2,21 @@ package plugin
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+	"github.com/yoheimuta/protolint/internal/addon/plugin/shared"
 	"github.com/yoheimuta/protolint/internal/linter/file"
+	"github.com/yoheimuta/protolint/internal/osutil"
+	"github.com/yoheimuta/protolint/linter/report"
 	"github.com/yoheimuta/protolint/linter/rule"
 )
 
 type ruleSet struct {
 	rawRules []rule.Rule
 
+	// mu guards rules and verbose because the host may call Apply concurrently.
+	mu      sync.RWMutex
 	rules   map[string]rule.Rule
 	verbose bool
 }
@@ -21,7 +27,10 @@ func newRuleSet(rules []rule.Rule) *ruleSet {
 	}
 }
 
-func (c *ruleSet) initialize(req *proto.ListRulesRequest) {
+func (c *ruleSet) initialize(req *proto.ListRulesRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.verbose = req.Verbose
 
 	ruleMap := make(map[string]rule.Rule)
@@ -32,13 +41,28 @@ func (c *ruleSet) initialize(req *proto.ListRulesRequest) {
 				req.FixMode,
 			)
 		}
+		if config, ok := req.RuleConfigs[r.ID()]; ok {
+			if cr, ok := r.(ConfigurableRule); ok {
+				configured, err := cr.Configure(RuleConfig{value: config})
+				if err != nil {
+					return fmt.Errorf("failed to configure rule=%s: %w", r.ID(), err)
+				}
+				r = configured
+			}
+		}
 		ruleMap[r.ID()] = r
 	}
 	c.rules = ruleMap
+	return nil
 }
 
 func (c *ruleSet) ListRules(req *proto.ListRulesRequest) (*proto.ListRulesResponse, error) {
-	c.initialize(req)
+	if err := c.initialize(req); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	var meta []*proto.ListRulesResponse_Rule
 	for _, r := range c.rules {
@@ -49,7 +73,8 @@ func (c *ruleSet) ListRules(req *proto.ListRulesRequest) (*proto.ListRulesRespon
 		})
 	}
 	return &proto.ListRulesResponse{
-		Rules: meta,
+		Rules:           meta,
+		ProtocolVersion: shared.ProtocolVersion,
 	}, nil
 }
 
@@ -66,15 +91,25 @@ func getSeverity(severity rule.Severity) proto.RuleSeverity {
 	return proto.RuleSeverity_RULE_SEVERITY_UNSPECIFIED
 }
 
-func (c *ruleSet) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
-	r, ok := c.rules[req.Id]
+func (c *ruleSet) rule(id string) (rule.Rule, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.rules[id]
 	if !ok {
-		return nil, fmt.Errorf("not found rule=%s", req.Id)
+		return nil, false, fmt.Errorf("not found rule=%s", id)
+	}
+	return r, c.verbose, nil
+}
+
+func (c *ruleSet) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
+	r, verbose, err := c.rule(req.Id)
+	if err != nil {
+		return nil, err
 	}
 
 	absPath := req.Path
 	protoFile := file.NewProtoFile(absPath, absPath)
-	p, err := protoFile.Parse(c.verbose)
+	p, err := protoFile.Parse(verbose)
 	if err != nil {
 		return nil, err
 	}
@@ -87,14 +122,87 @@ func (c *ruleSet) Apply(req *proto.ApplyRequest) (*proto.ApplyResponse, error) {
 	for _, f := range fs {
 		fsp = append(fsp, &proto.ApplyResponse_Failure{
 			Message: f.Message(),
-			Pos: &proto.ApplyResponse_Position{
-				Offset: int32(f.Pos().Offset),
-				Line:   int32(f.Pos().Line),
-				Column: int32(f.Pos().Column),
-			},
+			Pos:     getPosition(f),
 		})
 	}
 	return &proto.ApplyResponse{
 		Failures: fsp,
 	}, nil
 }
+
+func getPosition(f report.Failure) *proto.ApplyResponse_Position {
+	return &proto.ApplyResponse_Position{
+		Offset: int32(f.Pos().Offset),
+		Line:   int32(f.Pos().Line),
+		Column: int32(f.Pos().Column),
+	}
+}
+
+// ApplyV2 applies the rule to the content in the request instead of the file on the disk.
+// The fixes of the rule are kept in memory and returned as the edits of the content.
+func (c *ruleSet) ApplyV2(req *proto.ApplyV2Request) (*proto.ApplyV2Response, error) {
+	r, verbose, err := c.rule(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	displayPath := req.DisplayPath
+	if displayPath == "" {
+		displayPath = req.Path
+	}
+	// The fixer opens the display path, and the parser opens the absolute path.
+	for _, name := range []string{req.Path, displayPath} {
+		osutil.SetOverlay(name, req.Content)
+		defer osutil.RemoveOverlay(name)
+	}
+
+	p, err := file.NewProtoFile(req.Path, displayPath).Parse(verbose)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := r.Apply(p)
+	if err != nil {
+		return nil, err
+	}
+	fixed, err := osutil.ReadFile(displayPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var fsp []*proto.ApplyV2Response_Failure
+	for _, f := range fs {
+		fsp = append(fsp, &proto.ApplyV2Response_Failure{
+			Message:  f.Message(),
+			Pos:      getPosition(f),
+			Severity: getSeverity(rule.Severity(f.Severity())),
+		})
+	}
+	return &proto.ApplyV2Response{
+		Failures: fsp,
+		Edits:    textEdits(req.Content, fixed),
+	}, nil
+}
+
+// textEdits returns the edit which turns the origin into the fixed one, or nothing if they are the same.
+// The edit spans from the first to the last of the changed bytes.
+func textEdits(origin, fixed []byte) []*proto.ApplyV2Response_TextEdit {
+	prefix := 0
+	for prefix < len(origin) && prefix < len(fixed) && origin[prefix] == fixed[prefix] {
+		prefix++
+	}
+	if prefix == len(origin) && prefix == len(fixed) {
+		return nil
+	}
+	suffix := 0
+	for suffix < len(origin)-prefix && suffix < len(fixed)-prefix &&
+		origin[len(origin)-1-suffix] == fixed[len(fixed)-1-suffix] {
+		suffix++
+	}
+	return []*proto.ApplyV2Response_TextEdit{
+		{
+			StartOffset: int32(prefix),
+			EndOffset:   int32(len(origin) - suffix),
+			NewText:     string(fixed[prefix : len(fixed)-suffix]),
+		},
+	}
+}
@@ -0,0 +1,73 @@
+//go:build wasip1
+
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	gproto "google.golang.org/protobuf/proto"
+
+	"github.com/yoheimuta/protolint/internal/addon/plugin/proto"
+	"github.com/yoheimuta/protolint/linter/rule"
+)
+
+// RegisterCustomRules registers custom rules.
+// The plugin compiled to a WASI module serves a call read from the stdin, and exits.
+func RegisterCustomRules(
+	rules ...rule.Rule,
+) {
+	// The rules may print to the stdout, which is reserved for the response.
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+
+	if err := serveWasmCall(newRuleSet(rules), os.Stdin, stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func serveWasmCall(
+	rs *ruleSet,
+	r io.Reader,
+	w io.Writer,
+) error {
+	in, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var call proto.WasmCall
+	if err := gproto.Unmarshal(in, &call); err != nil {
+		return err
+	}
+	if call.ListRules == nil {
+		call.ListRules = &proto.ListRulesRequest{}
+	}
+
+	var resp gproto.Message
+	switch {
+	case call.Apply != nil:
+		if err := rs.initialize(call.ListRules); err != nil {
+			return err
+		}
+		resp, err = rs.Apply(call.Apply)
+	case call.ApplyV2 != nil:
+		if err := rs.initialize(call.ListRules); err != nil {
+			return err
+		}
+		resp, err = rs.ApplyV2(call.ApplyV2)
+	default:
+		resp, err = rs.ListRules(call.ListRules)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := gproto.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}